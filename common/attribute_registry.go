@@ -0,0 +1,166 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/stmcginnis/gofish"
+)
+
+// Attribute describes an attribute of an attribute registry: its type and the values it accepts
+type Attribute struct {
+	AttributeName string
+	// Type is either Enumeration, String, Integer, Boolean or Password
+	Type     string
+	ReadOnly bool
+	// Immutable attributes cannot be changed either, like read only ones
+	Immutable bool
+	// LowerBound, UpperBound and ScalarIncrement constrain Integer attributes
+	LowerBound      *int64
+	UpperBound      *int64
+	ScalarIncrement int64
+	// MinLength, MaxLength and ValueExpression constrain String and Password attributes
+	MinLength       *int
+	MaxLength       *int
+	ValueExpression string
+	// Values lists the values of Enumeration attributes
+	Values []struct {
+		ValueName        string
+		ValueDisplayName string
+	} `json:"Value"`
+}
+
+// AttributeRegistry is a redfish registry describing the attributes of a resource, like the ones of the bios
+type AttributeRegistry struct {
+	ID              string `json:"Id"`
+	RegistryVersion string
+	Attributes      []Attribute
+}
+
+// UnmarshalJSON decodes an attribute registry, whose attributes are nested in its RegistryEntries
+func (r *AttributeRegistry) UnmarshalJSON(data []byte) error {
+	var t struct {
+		ID              string `json:"Id"`
+		RegistryVersion string
+		RegistryEntries struct {
+			Attributes []Attribute
+		}
+	}
+	if err := json.Unmarshal(data, &t); err != nil {
+		return err
+	}
+
+	r.ID = t.ID
+	r.RegistryVersion = t.RegistryVersion
+	r.Attributes = t.RegistryEntries.Attributes
+	return nil
+}
+
+// GetAttributeRegistry fetches the attribute registry of a resource from the registries of the service. name is
+// the AttributeRegistry property of the resource, such as BiosAttributeRegistry.v1_0_3, which matches either the
+// registry or the ID of the registry file.
+func GetAttributeRegistry(service *gofish.Service, name string) (*AttributeRegistry, error) {
+	registries, err := service.Registries()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range registries {
+		if (r.Registry != name && r.ID != name) || len(r.Location) == 0 {
+			continue
+		}
+		resp, err := service.GetClient().Get(r.Location[0].URI)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		var registry AttributeRegistry
+		if err := json.NewDecoder(resp.Body).Decode(&registry); err != nil {
+			return nil, err
+		}
+		return &registry, nil
+	}
+
+	return nil, fmt.Errorf("attribute registry %s not found", name)
+}
+
+// Attribute returns the attribute of the registry with the given name, or nil if there is none
+func (r *AttributeRegistry) Attribute(name string) *Attribute {
+	for i := range r.Attributes {
+		if r.Attributes[i].AttributeName == name {
+			return &r.Attributes[i]
+		}
+	}
+	return nil
+}
+
+// Convert checks that the attribute can be set to value, written as a string like in terraform configurations, and
+// returns it converted to the type of the attribute: an int64 for Integer attributes, a bool for Boolean ones and
+// a string otherwise.
+func (a *Attribute) Convert(value string) (interface{}, error) {
+	if a.ReadOnly || a.Immutable {
+		return nil, fmt.Errorf("attribute %s is read only", a.AttributeName)
+	}
+
+	switch a.Type {
+	case "Integer":
+		v, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %s must be an integer, got %q", a.AttributeName, value)
+		}
+		if (a.LowerBound != nil && v < *a.LowerBound) || (a.UpperBound != nil && v > *a.UpperBound) {
+			return nil, fmt.Errorf("attribute %s must be between %s and %s, got %d", a.AttributeName,
+				bound(a.LowerBound), bound(a.UpperBound), v)
+		}
+		if a.ScalarIncrement > 0 && a.LowerBound != nil && (v-*a.LowerBound)%a.ScalarIncrement != 0 {
+			return nil, fmt.Errorf("attribute %s must be %d plus a multiple of %d, got %d", a.AttributeName,
+				*a.LowerBound, a.ScalarIncrement, v)
+		}
+		return v, nil
+	case "Boolean":
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %s must be true or false, got %q", a.AttributeName, value)
+		}
+		return v, nil
+	case "Enumeration":
+		names := make([]string, 0, len(a.Values))
+		for _, v := range a.Values {
+			if v.ValueName == value {
+				return value, nil
+			}
+			names = append(names, v.ValueName)
+		}
+		return nil, fmt.Errorf("attribute %s must be one of %s, got %q", a.AttributeName, strings.Join(names, ", "), value)
+	case "String", "Password":
+		if (a.MinLength != nil && len(value) < *a.MinLength) || (a.MaxLength != nil && len(value) > *a.MaxLength) {
+			return nil, fmt.Errorf("the length of attribute %s must be between %s and %s, got %d", a.AttributeName,
+				length(a.MinLength), length(a.MaxLength), len(value))
+		}
+		// Registries write their expressions for perl compatible engines, those go cannot compile are skipped
+		if re, err := regexp.Compile(a.ValueExpression); err == nil && a.ValueExpression != "" && !re.MatchString(value) {
+			return nil, fmt.Errorf("attribute %s must match %s", a.AttributeName, a.ValueExpression)
+		}
+		return value, nil
+	}
+
+	return nil, fmt.Errorf("attribute %s has the unsupported type %s", a.AttributeName, a.Type)
+}
+
+func bound(b *int64) string {
+	if b == nil {
+		return "any"
+	}
+	return strconv.FormatInt(*b, 10)
+}
+
+func length(l *int) string {
+	if l == nil {
+		return "any"
+	}
+	return strconv.Itoa(*l)
+}
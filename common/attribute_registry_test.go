@@ -0,0 +1,78 @@
+package common
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const testAttributeRegistry = `{
+	"Id": "BiosAttributeRegistry.v1_0_3",
+	"RegistryVersion": "v1_0_3",
+	"RegistryEntries": {
+		"Attributes": [
+			{"AttributeName": "NumLock", "Type": "Enumeration", "ReadOnly": false,
+			 "Value": [{"ValueDisplayName": "On", "ValueName": "On"}, {"ValueDisplayName": "Off", "ValueName": "Off"}]},
+			{"AttributeName": "AcPwrRcvryUserDelay", "Type": "Integer", "LowerBound": 60, "UpperBound": 600, "ScalarIncrement": 0},
+			{"AttributeName": "MemTestDelay", "Type": "Integer", "LowerBound": 0, "UpperBound": 60, "ScalarIncrement": 15},
+			{"AttributeName": "AssetTag", "Type": "String", "MinLength": 0, "MaxLength": 8, "ValueExpression": "^[ -~]{0,8}$"},
+			{"AttributeName": "Oem", "Type": "String", "ValueExpression": "^(?!x).*$"},
+			{"AttributeName": "PxeDev1EnDis", "Type": "Boolean"},
+			{"AttributeName": "SystemModelName", "Type": "String", "ReadOnly": true},
+			{"AttributeName": "SysMemSize", "Type": "String", "Immutable": true}
+		]
+	}
+}`
+
+func TestAttributeConvert(t *testing.T) {
+	var registry AttributeRegistry
+	if err := json.Unmarshal([]byte(testAttributeRegistry), &registry); err != nil {
+		t.Fatal(err)
+	}
+	if registry.ID != "BiosAttributeRegistry.v1_0_3" || len(registry.Attributes) != 8 {
+		t.Fatalf("got registry %s with %d attributes", registry.ID, len(registry.Attributes))
+	}
+
+	tests := []struct {
+		attribute string
+		value     string
+		want      interface{}
+		wantErr   bool
+	}{
+		{attribute: "NumLock", value: "Off", want: "Off"},
+		{attribute: "NumLock", value: "off", wantErr: true},
+		{attribute: "AcPwrRcvryUserDelay", value: "060", want: int64(60)},
+		{attribute: "AcPwrRcvryUserDelay", value: "601", wantErr: true},
+		{attribute: "AcPwrRcvryUserDelay", value: "1.5", wantErr: true},
+		{attribute: "MemTestDelay", value: "45", want: int64(45)},
+		{attribute: "MemTestDelay", value: "40", wantErr: true},
+		{attribute: "AssetTag", value: "rack-12", want: "rack-12"},
+		{attribute: "AssetTag", value: "rack-1234", wantErr: true},
+		{attribute: "AssetTag", value: "rack\n12", wantErr: true},
+		{attribute: "Oem", value: "xyz", want: "xyz"},
+		{attribute: "PxeDev1EnDis", value: "true", want: true},
+		{attribute: "PxeDev1EnDis", value: "Enabled", wantErr: true},
+		{attribute: "SystemModelName", value: "PowerEdge R650", wantErr: true},
+		{attribute: "SysMemSize", value: "64 GB", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.attribute+"="+tt.value, func(t *testing.T) {
+			attribute := registry.Attribute(tt.attribute)
+			if attribute == nil {
+				t.Fatalf("attribute %s not found", tt.attribute)
+			}
+			got, err := attribute.Convert(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want an error: %t", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+
+	if registry.Attribute("NumLok") != nil {
+		t.Error("got an attribute missing from the registry")
+	}
+}
@@ -0,0 +1,85 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	gofishcommon "github.com/stmcginnis/gofish/common"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// Message is a redfish message explaining the outcome of a request or of a job
+type Message struct {
+	// MessageID is the key of the message in its registry, such as Base.1.12.PropertyValueNotInList
+	MessageID string `json:"MessageId"`
+	Message   string
+	// Resolution is the action recommended to resolve the error
+	Resolution string
+	// Severity is either OK, Warning or Critical
+	Severity string
+	// RelatedProperties are JSON pointers to the properties of the request the message is about, such as
+	// #/Attributes/NumLock
+	RelatedProperties []string
+}
+
+// JobError is returned when a job ends without completing. It holds the messages of the job, which tell why.
+type JobError struct {
+	State    redfish.TaskState
+	Messages []Message
+}
+
+func (e *JobError) Error() string {
+	err := fmt.Sprintf("the job has finished unsucessfully with a %s state", e.State)
+	for _, m := range e.Messages {
+		err += ": " + m.Message
+	}
+	return err
+}
+
+// newJobError returns the error of a task which ended in the given state
+func newJobError(task *redfish.Task) *JobError {
+	err := &JobError{State: task.TaskState}
+	for _, m := range task.Messages {
+		err.Messages = append(err.Messages, Message{
+			MessageID:         m.MessageID,
+			Message:           m.Message,
+			Resolution:        m.Resolution,
+			Severity:          m.Severity,
+			RelatedProperties: m.RelatedProperties,
+		})
+	}
+	return err
+}
+
+// Messages returns the redfish messages explaining err: either the @Message.ExtendedInfo of an error response, or
+// the messages of a job which did not complete. It returns nil when err does not come with messages.
+func Messages(err error) []Message {
+	var jobErr *JobError
+	if errors.As(err, &jobErr) {
+		return jobErr.Messages
+	}
+
+	var redfishErr *gofishcommon.Error
+	if !errors.As(err, &redfishErr) {
+		return nil
+	}
+	// gofish leaves RelatedProperties out, so the body of the response is decoded again. Error() returns it,
+	// after the status code.
+	body := strings.TrimPrefix(redfishErr.Error(), fmt.Sprintf("%d: ", redfishErr.HTTPReturnedStatusCode))
+	var response struct {
+		Error struct {
+			ExtendedInfo []Message `json:"@Message.ExtendedInfo"`
+		} `json:"error"`
+	}
+	if json.Unmarshal([]byte(body), &response) == nil && len(response.Error.ExtendedInfo) > 0 {
+		return response.Error.ExtendedInfo
+	}
+
+	// Some errors come with a message of their own only
+	if redfishErr.Message == "" || redfishErr.Message == body {
+		return nil
+	}
+	return []Message{{MessageID: redfishErr.Code, Message: redfishErr.Message}}
+}
@@ -0,0 +1,61 @@
+package common
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	gofishcommon "github.com/stmcginnis/gofish/common"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+func TestMessages(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want []Message
+	}{
+		{
+			name: "extended info",
+			err: gofishcommon.ConstructError(400, []byte(`{"error": {"code": "Base.1.12.GeneralError", "message": "A general error has occurred.",
+				"@Message.ExtendedInfo": [{"MessageId": "Base.1.12.PropertyValueNotInList", "Message": "The value Of for the property NumLock is not in the list of acceptable values.",
+				"Resolution": "Choose a value from the enumeration list.", "Severity": "Warning", "RelatedProperties": ["#/Attributes/NumLock"]}]}}`)),
+			want: []Message{{
+				MessageID:         "Base.1.12.PropertyValueNotInList",
+				Message:           "The value Of for the property NumLock is not in the list of acceptable values.",
+				Resolution:        "Choose a value from the enumeration list.",
+				Severity:          "Warning",
+				RelatedProperties: []string{"#/Attributes/NumLock"},
+			}},
+		},
+		{
+			name: "message only",
+			err:  gofishcommon.ConstructError(500, []byte(`{"error": {"code": "Base.1.12.GeneralError", "message": "A general error has occurred."}}`)),
+			want: []Message{{MessageID: "Base.1.12.GeneralError", Message: "A general error has occurred."}},
+		},
+		{
+			name: "not redfish",
+			err:  gofishcommon.ConstructError(502, []byte(`<html>Bad Gateway</html>`)),
+		},
+		{
+			name: "wrapped job error",
+			err: fmt.Errorf("waiting: %w", newJobError(&redfish.Task{
+				TaskState: redfish.ExceptionTaskState,
+				Messages:  []gofishcommon.Message{{MessageID: "IDRAC.2.8.SUP0518", Message: "Unable to complete the job."}},
+			})),
+			want: []Message{{MessageID: "IDRAC.2.8.SUP0518", Message: "Unable to complete the job."}},
+		},
+		{
+			name: "other error",
+			err:  fmt.Errorf("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Messages(tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,11 +1,16 @@
 package common
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"path"
 	"time"
 
 	"github.com/stmcginnis/gofish"
+	gofishcommon "github.com/stmcginnis/gofish/common"
 	"github.com/stmcginnis/gofish/redfish"
 )
 
@@ -18,45 +23,69 @@ const (
 
 // WaitForJobToFinish waits for a redfish job to finish.
 // Parameters:
+//   - ctx -> context of the operation. Waiting stops with an error as soon as it is done.
 //   - jobURI -> URI for the job to check.
 //   - timeBetweenAttempts -> time to wait between attempts. I.e. 30 means 30 seconds.
-//   - timeout -> maximun time to wait until job is considered failed.
-func WaitForJobToFinish(service *gofish.Service, jobURI string, timeBetweenAttempts int, timeout int) error {
-	// Create tickers
+//   - timeout -> maximun time to wait until job is considered failed. 0 means waiting until ctx is done.
+//
+// A job ending in the Killed or Exception state is reported with a *JobError, holding the messages of the job.
+func WaitForJobToFinish(ctx context.Context, service *gofish.Service, jobURI string, timeBetweenAttempts int, timeout int) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+		defer cancel()
+	}
+
+	// Create ticker
 	attemptTick := time.NewTicker(time.Duration(timeBetweenAttempts) * time.Second)
-	timeoutTick := time.NewTicker(time.Duration(timeout) * time.Second)
+	defer attemptTick.Stop()
 	for {
 		select {
 		case <-attemptTick.C:
-			//For some reason iDRAC 4.40.00.0 from time to time gives the following error: iDRAC is not ready. The configuration values cannot be accessed. Please retry after a few minutes.
+			// The transient failures of the BMC are retried by the transport, the ones left are reported
 			job, err := redfish.GetTask(service.GetClient(), jobURI)
-			if err == nil {
-				log.Printf("[DEBUG] - Attempting one more time... Job state is %s\n", job.TaskState)
-				//Check if job has finished
-				switch status := job.TaskState; status {
-				case redfish.CompletedTaskState:
-					return nil
-				case redfish.KilledTaskState:
-					return fmt.Errorf("the job has finished unsucessfully with a %s state", job.TaskState)
-				case redfish.ExceptionTaskState:
-					return fmt.Errorf("the job has finished unsucessfully with a %s state", job.TaskState)
-				}
+			if err != nil {
+				return fmt.Errorf("error when checking the job %s: %w", jobURI, err)
+			}
+			log.Printf("[DEBUG] - Attempting one more time... Job state is %s\n", job.TaskState)
+			//Check if job has finished
+			switch status := job.TaskState; status {
+			case redfish.CompletedTaskState:
+				return nil
+			case redfish.KilledTaskState, redfish.ExceptionTaskState:
+				return newJobError(job)
 			}
-		case <-timeoutTick.C:
-			log.Printf("[DEBUG] - Error. Timeout reached\n")
-			return fmt.Errorf("Timeout waiting for the job to finish")
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				log.Printf("[DEBUG] - Error. Timeout reached\n")
+				return fmt.Errorf("Timeout waiting for the job to finish")
+			}
+			return fmt.Errorf("Stopped waiting for the job to finish: %w", ctx.Err())
 		}
 	}
 }
 
+// SleepWithContext waits for the given duration, or less if ctx is done first, in which case the error of ctx
+// is returned
+func SleepWithContext(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // DeleteDellJob is intended to delete a task schedules in a Dell system.
 // This function is only a workaround until HTTP DELETE is supported under each task o taskmonitor
 //
 //	Parameters:
+//	- jobsURI: URI of the job queue of the iDRAC, as linked from its manager
 //	- taskID: Id of the tasks to delete
-func DeleteDellJob(service *gofish.Service, taskID string) error {
-	url := "/redfish/v1/Managers/iDRAC.Embedded.1/Jobs/"
-	resp, err := service.GetClient().Delete(fmt.Sprintf("%s%s", url, taskID))
+func DeleteDellJob(service *gofish.Service, jobsURI string, taskID string) error {
+	resp, err := service.GetClient().Delete(fmt.Sprintf("%s/%s", jobsURI, taskID))
 	if err != nil {
 		return err
 	}
@@ -66,3 +95,55 @@ func DeleteDellJob(service *gofish.Service, taskID string) error {
 	}
 	return nil
 }
+
+// CreateDellJob creates a job in the job queue at jobsURI of a Dell system applying the changes staged in the given
+// settings resource, such as the bios passwords changed with the Bios.ChangePassword action, and returns the ID of
+// the job. The job waits for the system to be reset.
+func CreateDellJob(service *gofish.Service, jobsURI string, targetSettingsURI string) (string, error) {
+	resp, err := service.GetClient().Post(jobsURI, map[string]interface{}{
+		"TargetSettingsURI": targetSettingsURI,
+	})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	location, err := resp.Location()
+	if err != nil {
+		return "", fmt.Errorf("the job applying %s was created without a location: %w", targetSettingsURI, err)
+	}
+	return path.Base(location.Path), nil
+}
+
+// DellJob is a job of the job queue of a Dell system
+type DellJob struct {
+	ID   string `json:"Id"`
+	Name string
+	// JobState is the state of the job, such as Scheduled or Completed
+	JobState string
+	// JobType tells what the job does, such as BIOSConfiguration
+	JobType string
+}
+
+// ListDellJobs returns the jobs of the job queue at jobsURI of a Dell system
+func ListDellJobs(service *gofish.Service, jobsURI string) ([]DellJob, error) {
+	collection, err := gofishcommon.GetCollection(service.GetClient(), jobsURI)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]DellJob, 0, len(collection.ItemLinks))
+	for _, uri := range collection.ItemLinks {
+		resp, err := service.GetClient().Get(uri)
+		if err != nil {
+			return nil, err
+		}
+		var job DellJob
+		err = json.NewDecoder(resp.Body).Decode(&job)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
@@ -0,0 +1,134 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stmcginnis/gofish"
+)
+
+// dellJobsURI is the job queue of the served iDRAC
+const dellJobsURI = "/redfish/v1/Managers/iDRAC.Embedded.1/Jobs"
+
+// newJobService serves a service root and a job which stays in the given state, both as a task and in the Dell job
+// queue
+func newJobService(t *testing.T, taskState string) *gofish.Service {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/redfish/v1/":
+			fmt.Fprint(w, `{"@odata.id": "/redfish/v1/"}`)
+		case "/redfish/v1/TaskService/Tasks/JID_1":
+			fmt.Fprintf(w, `{"@odata.id": "/redfish/v1/TaskService/Tasks/JID_1", "Id": "JID_1", "TaskState": "%s"}`, taskState)
+		case dellJobsURI:
+			if r.Method == http.MethodPost {
+				w.Header().Set("Location", dellJobsURI+"/JID_1")
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			fmt.Fprintf(w, `{"@odata.id": "%s", "Members": [{"@odata.id": "%s/JID_1"}]}`, dellJobsURI, dellJobsURI)
+		case dellJobsURI + "/JID_1":
+			fmt.Fprintf(w, `{"@odata.id": "%s/JID_1", "Id": "JID_1", "Name": "Configure: BIOS.Setup.1-1", "JobState": "%s", "JobType": "BIOSConfiguration"}`,
+				dellJobsURI, taskState)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	api, err := gofish.Connect(gofish.ClientConfig{Endpoint: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return api.Service
+}
+
+func TestWaitForJobToFinish(t *testing.T) {
+	tests := []struct {
+		taskState string
+		err       string
+	}{
+		{taskState: "Completed"},
+		{taskState: "Exception", err: "Exception state"},
+		{taskState: "Running", err: "Timeout"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.taskState, func(t *testing.T) {
+			service := newJobService(t, tt.taskState)
+			err := WaitForJobToFinish(context.Background(), service, "/redfish/v1/TaskService/Tasks/JID_1", 1, 3)
+			if tt.err == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+				t.Fatalf("got error %v, want one containing %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestWaitForJobToFinishCancelled(t *testing.T) {
+	service := newJobService(t, "Running")
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	err := WaitForJobToFinish(ctx, service, "/redfish/v1/TaskService/Tasks/JID_1", 10, 0)
+	if err == nil || !strings.Contains(err.Error(), context.Canceled.Error()) {
+		t.Fatalf("got error %v, want the job wait to be cancelled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("cancelling took %s", elapsed)
+	}
+}
+
+func TestWaitForJobToFinishMissingJob(t *testing.T) {
+	service := newJobService(t, "Running")
+	start := time.Now()
+	err := WaitForJobToFinish(context.Background(), service, "/redfish/v1/TaskService/Tasks/JID_2", 1, 30)
+	if err == nil || !strings.Contains(err.Error(), "JID_2") {
+		t.Fatalf("got error %v, want the one of the missing job", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("reporting the error took %s", elapsed)
+	}
+}
+
+func TestSleepWithContext(t *testing.T) {
+	if err := SleepWithContext(context.Background(), time.Millisecond); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := SleepWithContext(ctx, time.Hour); err != context.DeadlineExceeded {
+		t.Errorf("got %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestListDellJobs(t *testing.T) {
+	service := newJobService(t, "Scheduled")
+	jobs, err := ListDellJobs(service, dellJobsURI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []DellJob{{ID: "JID_1", Name: "Configure: BIOS.Setup.1-1", JobState: "Scheduled", JobType: "BIOSConfiguration"}}
+	if !reflect.DeepEqual(jobs, want) {
+		t.Errorf("got jobs %+v, want %+v", jobs, want)
+	}
+}
+
+func TestCreateDellJob(t *testing.T) {
+	service := newJobService(t, "Scheduled")
+	id, err := CreateDellJob(service, dellJobsURI, "/redfish/v1/Systems/System.Embedded.1/Bios/Settings")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "JID_1" {
+		t.Errorf("got job %s, want JID_1", id)
+	}
+}
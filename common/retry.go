@@ -0,0 +1,196 @@
+package common
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// notReadyMessageID identifies the error iDRACs answer with while they are busy, such as "iDRAC is not ready.
+	// The configuration values cannot be accessed. Please retry after a few minutes."
+	notReadyMessageID = "SYS446"
+	notReadyMessage   = "iDRAC is not ready"
+	// maxErrorBodySize bounds the part of error responses read to look for transient errors
+	maxErrorBodySize = 64 << 10
+)
+
+// RetryPolicy describes how requests failing with a transient error are retried
+type RetryPolicy struct {
+	// MaxRetries is the number of times a request is retried. 0 turns retrying off.
+	MaxRetries int
+	// MinBackoff is the wait before the first retry, doubled on every retry up to MaxBackoff
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// Jitter is the fraction, between 0 and 1, of a wait which is randomly cut off from it, so that requests
+	// failing together are not retried together
+	Jitter float64
+}
+
+// DefaultRetryPolicy is the policy used when none is configured
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	MinBackoff: time.Second,
+	MaxBackoff: 30 * time.Second,
+	Jitter:     0.2,
+}
+
+// Validate checks that the policy makes sense
+func (p RetryPolicy) Validate() error {
+	if p.MaxRetries < 0 {
+		return fmt.Errorf("max_retries cannot be negative, got %d", p.MaxRetries)
+	}
+	if p.MinBackoff < 0 || p.MaxBackoff < p.MinBackoff {
+		return fmt.Errorf("min_backoff (%s) must be positive and at most max_backoff (%s)", p.MinBackoff, p.MaxBackoff)
+	}
+	if p.Jitter < 0 || p.Jitter > 1 {
+		return fmt.Errorf("jitter must be between 0 and 1, got %v", p.Jitter)
+	}
+	return nil
+}
+
+// backoff returns the wait before the given retry, counting from 0
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	wait := p.MaxBackoff
+	if exp := float64(p.MinBackoff) * math.Pow(2, float64(retry)); exp < float64(p.MaxBackoff) {
+		wait = time.Duration(exp)
+	}
+	return wait - time.Duration(rand.Float64()*p.Jitter*float64(wait))
+}
+
+// RetryTransport is an http.RoundTripper retrying the requests which fail with a transient error. Every request is
+// retried when the service asks for it to be (503 Service Unavailable, 429 Too Many Requests or the iDRAC "not ready"
+// error), since it has then not been processed. Idempotent requests are also retried on network errors and gateway
+// errors, after which it is unknown whether they have been processed. Redfish PATCH requests set properties to given
+// values, so they are taken as idempotent. Requests whose body cannot be rebuilt are never retried.
+type RetryTransport struct {
+	Policy RetryPolicy
+	// Base is the underlying transport. If nil, http.DefaultTransport is used
+	Base http.RoundTripper
+}
+
+// NewRetryTransport returns a RetryTransport following the given policy
+func NewRetryTransport(policy RetryPolicy, base http.RoundTripper) *RetryTransport {
+	return &RetryTransport{
+		Policy: policy,
+		Base:   base,
+	}
+}
+
+func (t *RetryTransport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	attempt := req
+	for retry := 0; ; retry++ {
+		resp, err := t.base().RoundTrip(attempt)
+		if retry >= t.Policy.MaxRetries || !replayable || req.Context().Err() != nil {
+			return resp, err
+		}
+
+		reason, wait := transientFailure(req, resp, err)
+		if reason == "" {
+			return resp, err
+		}
+		if wait <= 0 {
+			wait = t.Policy.backoff(retry)
+		} else if wait > t.Policy.MaxBackoff {
+			wait = t.Policy.MaxBackoff
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		log.Printf("[DEBUG] %s %s failed (%s), retrying in %s (%d/%d)\n", req.Method, req.URL.Path, reason, wait, retry+1, t.Policy.MaxRetries)
+		if err := SleepWithContext(req.Context(), wait); err != nil {
+			return nil, err
+		}
+
+		attempt = req.Clone(req.Context())
+		if req.GetBody != nil {
+			if attempt.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// transientFailure tells why the outcome of a request is worth retrying, if it is, and how long the service asked
+// to wait before doing so, 0 if it did not
+func transientFailure(req *http.Request, resp *http.Response, err error) (string, time.Duration) {
+	if err != nil {
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			// The request has not been sent
+			return err.Error(), 0
+		}
+		if isIdempotent(req.Method) {
+			return err.Error(), 0
+		}
+		return "", 0
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return resp.Status, retryAfter(resp)
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		if isIdempotent(req.Method) {
+			return resp.Status, 0
+		}
+	}
+	if resp.StatusCode >= http.StatusBadRequest && isNotReady(resp) {
+		return notReadyMessage, retryAfter(resp)
+	}
+	return "", 0
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// isNotReady tells whether an error response is the iDRAC "not ready" error. The body read is put back for the
+// caller.
+func isNotReady(resp *http.Response) bool {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+	if err != nil {
+		return false
+	}
+	return bytes.Contains(body, []byte(notReadyMessageID)) || bytes.Contains(body, []byte(notReadyMessage))
+}
+
+// retryAfter returns the wait asked by the Retry-After header of a response, either a number of seconds or a date
+func retryAfter(resp *http.Response) time.Duration {
+	value := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
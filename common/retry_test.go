@@ -0,0 +1,230 @@
+package common
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var testRetryPolicy = RetryPolicy{
+	MaxRetries: 2,
+	MinBackoff: time.Millisecond,
+	MaxBackoff: 10 * time.Millisecond,
+	Jitter:     0.5,
+}
+
+// flakyService answers the first requests it gets with fail, and the following ones with 200 and the body of the
+// request
+type flakyService struct {
+	mu       sync.Mutex
+	failures int
+	fail     func(w http.ResponseWriter)
+	requests int
+}
+
+func (f *flakyService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.requests++
+	failing := f.requests <= f.failures
+	f.mu.Unlock()
+
+	body, _ := io.ReadAll(r.Body)
+	if failing {
+		f.fail(w)
+		return
+	}
+	_, _ = w.Write(body)
+}
+
+func dropConnection(w http.ResponseWriter) {
+	conn, _, err := w.(http.Hijacker).Hijack()
+	if err == nil {
+		conn.Close()
+	}
+}
+
+func TestRetryTransport(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		failures int
+		fail     func(w http.ResponseWriter)
+		status   int
+		requests int
+	}{
+		{
+			name:     "unavailable",
+			method:   http.MethodPost,
+			failures: 2,
+			fail:     func(w http.ResponseWriter) { w.WriteHeader(http.StatusServiceUnavailable) },
+			status:   http.StatusOK,
+			requests: 3,
+		},
+		{
+			name:     "too many requests with a long Retry-After",
+			method:   http.MethodPost,
+			failures: 1,
+			fail: func(w http.ResponseWriter) {
+				w.Header().Set("Retry-After", "3600")
+				w.WriteHeader(http.StatusTooManyRequests)
+			},
+			status:   http.StatusOK,
+			requests: 2,
+		},
+		{
+			name:     "idrac not ready",
+			method:   http.MethodPatch,
+			failures: 1,
+			fail: func(w http.ResponseWriter) {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = io.WriteString(w, `{"error": {"@Message.ExtendedInfo": [{"MessageId": "IDRAC.2.8.SYS446"}]}}`)
+			},
+			status:   http.StatusOK,
+			requests: 2,
+		},
+		{
+			name:     "retries exhausted",
+			method:   http.MethodGet,
+			failures: 3,
+			fail:     func(w http.ResponseWriter) { w.WriteHeader(http.StatusServiceUnavailable) },
+			status:   http.StatusServiceUnavailable,
+			requests: 3,
+		},
+		{
+			name:     "bad gateway on an idempotent request",
+			method:   http.MethodDelete,
+			failures: 1,
+			fail:     func(w http.ResponseWriter) { w.WriteHeader(http.StatusBadGateway) },
+			status:   http.StatusOK,
+			requests: 2,
+		},
+		{
+			name:     "bad gateway on a post",
+			method:   http.MethodPost,
+			failures: 1,
+			fail:     func(w http.ResponseWriter) { w.WriteHeader(http.StatusBadGateway) },
+			status:   http.StatusBadGateway,
+			requests: 1,
+		},
+		{
+			name:     "other error",
+			method:   http.MethodGet,
+			failures: 1,
+			fail: func(w http.ResponseWriter) {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = io.WriteString(w, `{"error": {"@Message.ExtendedInfo": [{"MessageId": "Base.1.12.PropertyUnknown"}]}}`)
+			},
+			status:   http.StatusBadRequest,
+			requests: 1,
+		},
+		{
+			name:     "connection dropped on an idempotent request",
+			method:   http.MethodPatch,
+			failures: 1,
+			fail:     dropConnection,
+			status:   http.StatusOK,
+			requests: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &flakyService{failures: tt.failures, fail: tt.fail}
+			server := httptest.NewServer(service)
+			defer server.Close()
+
+			req, err := http.NewRequest(tt.method, server.URL, strings.NewReader("payload"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := NewRetryTransport(testRetryPolicy, nil).RoundTrip(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+
+			if resp.StatusCode != tt.status {
+				t.Errorf("got status %d, want %d", resp.StatusCode, tt.status)
+			}
+			if resp.StatusCode == http.StatusOK && string(body) != "payload" {
+				t.Errorf("the service got body %q, want the one of the request", body)
+			}
+			if service.requests != tt.requests {
+				t.Errorf("the service got %d requests, want %d", service.requests, tt.requests)
+			}
+		})
+	}
+}
+
+func TestRetryTransportKeepsErrorBody(t *testing.T) {
+	service := &flakyService{failures: 1, fail: func(w http.ResponseWriter) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = io.WriteString(w, "Unable to reset the server.")
+	}}
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := NewRetryTransport(testRetryPolicy, nil).RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if body, _ := io.ReadAll(resp.Body); string(body) != "Unable to reset the server." {
+		t.Errorf("got body %q, want the one of the error", body)
+	}
+}
+
+func TestRetryTransportPostNotRetriedOnDroppedConnection(t *testing.T) {
+	service := &flakyService{failures: 1, fail: dropConnection}
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("payload"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewRetryTransport(testRetryPolicy, nil).RoundTrip(req); err == nil {
+		t.Error("expected an error")
+	}
+	if service.requests != 1 {
+		t.Errorf("the service got %d requests, want 1", service.requests)
+	}
+}
+
+func TestRetryTransportCancelled(t *testing.T) {
+	service := &flakyService{failures: 1, fail: func(w http.ResponseWriter) { w.WriteHeader(http.StatusServiceUnavailable) }}
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy := testRetryPolicy
+	policy.MinBackoff, policy.MaxBackoff = time.Hour, time.Hour
+	if _, err := NewRetryTransport(policy, nil).RoundTrip(req); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 10, MinBackoff: time.Second, MaxBackoff: 5 * time.Second, Jitter: 0.2}
+	for retry, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		got := policy.backoff(retry)
+		if got > want || got < want*8/10 {
+			t.Errorf("retry %d: got a backoff of %s, want between %s and %s", retry, got, want*8/10, want)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+const (
+	// serviceRootURI is the well-known location of the redfish service root
+	serviceRootURI = "/redfish/v1/"
+	// defaultSessionsURI is used when the service root does not advertise a sessions collection
+	defaultSessionsURI = "/redfish/v1/SessionService/Sessions"
+)
+
+// SessionTransport is an http.RoundTripper that authenticates every request against a redfish
+// service with an X-Auth-Token obtained from the SessionService. The session is created lazily
+// on the first request, re-created once whenever the service answers with 401 (i.e. the session
+// expired or was deleted from the BMC) and removed with Logout.
+type SessionTransport struct {
+	// Endpoint is the URL of the redfish service (i.e. https://my-server.org)
+	Endpoint string
+	// Username and Password are used to create the session
+	Username string
+	Password string
+	// Base is the underlying transport. If nil, http.DefaultTransport is used
+	Base http.RoundTripper
+
+	mu        sync.Mutex
+	token     string
+	location  string
+	generated int
+}
+
+// NewSessionTransport returns a SessionTransport for the given endpoint and credentials.
+func NewSessionTransport(endpoint, username, password string, base http.RoundTripper) *SessionTransport {
+	return &SessionTransport{
+		Endpoint: strings.TrimSuffix(endpoint, "/"),
+		Username: username,
+		Password: password,
+		Base:     base,
+	}
+}
+
+func (t *SessionTransport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *SessionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, generation, err := t.currentToken(req.Context(), -1)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base().RoundTrip(withToken(req, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// The request body has already been consumed, so it can only be replayed if it can be rebuilt
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+	log.Printf("[DEBUG] Redfish session for %s@%s is no longer valid, creating a new one\n", t.Username, t.Endpoint)
+	token, _, err = t.currentToken(req.Context(), generation)
+	if err != nil {
+		// Keep the original 401 so the caller gets the error reported by the service
+		log.Printf("[DEBUG] Unable to renew redfish session: %v\n", err)
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	return t.base().RoundTrip(withToken(retry, token))
+}
+
+// currentToken returns the session token to use. A new session is created when there is none or when
+// the token handed out previously (identified by staleGeneration) has been rejected by the service.
+// A staleGeneration of -1 means no token has been rejected.
+func (t *SessionTransport) currentToken(ctx context.Context, staleGeneration int) (string, int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// If another request already renewed the session, just use the new token
+	if t.token != "" && t.generated != staleGeneration {
+		return t.token, t.generated, nil
+	}
+
+	token, location, err := t.login(ctx)
+	if err != nil {
+		return "", 0, err
+	}
+	t.token, t.location = token, location
+	t.generated++
+	return t.token, t.generated, nil
+}
+
+// login creates a new session and returns its token and location
+func (t *SessionTransport) login(ctx context.Context) (string, string, error) {
+	payload, err := json.Marshal(map[string]string{
+		"UserName": t.Username,
+		"Password": t.Password,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.Endpoint+t.sessionsURI(ctx), bytes.NewReader(payload))
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := t.base().RoundTrip(req)
+	if err != nil {
+		return "", "", fmt.Errorf("error creating redfish session: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", "", fmt.Errorf("error creating redfish session for user %s: %d: %s", t.Username, resp.StatusCode, body)
+	}
+
+	token := resp.Header.Get("X-Auth-Token")
+	if token == "" {
+		return "", "", fmt.Errorf("error creating redfish session for user %s: no X-Auth-Token was returned", t.Username)
+	}
+
+	location := resp.Header.Get("Location")
+	if u, err := url.Parse(location); err == nil {
+		location = u.RequestURI()
+	}
+	log.Printf("[DEBUG] Created redfish session %s for %s@%s\n", location, t.Username, t.Endpoint)
+	return token, location, nil
+}
+
+// sessionsURI looks the sessions collection up in the service root, which can be read without authentication
+func (t *SessionTransport) sessionsURI(ctx context.Context) string {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.Endpoint+serviceRootURI, nil)
+	if err != nil {
+		return defaultSessionsURI
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := t.base().RoundTrip(req)
+	if err != nil {
+		return defaultSessionsURI
+	}
+	defer resp.Body.Close()
+
+	var root struct {
+		Links struct {
+			Sessions struct {
+				ODataID string `json:"@odata.id"`
+			}
+		}
+	}
+	if resp.StatusCode != http.StatusOK || json.NewDecoder(resp.Body).Decode(&root) != nil || root.Links.Sessions.ODataID == "" {
+		return defaultSessionsURI
+	}
+	return root.Links.Sessions.ODataID
+}
+
+// Logout deletes the session from the redfish service, if there is one.
+func (t *SessionTransport) Logout(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token == "" {
+		return nil
+	}
+	token, location := t.token, t.location
+	t.token, t.location = "", ""
+	if location == "" {
+		return fmt.Errorf("session location for %s@%s is unknown, it cannot be deleted", t.Username, t.Endpoint)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, t.Endpoint+location, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Auth-Token", token)
+
+	resp, err := t.base().RoundTrip(req)
+	if err != nil {
+		return fmt.Errorf("error deleting redfish session %s: %w", location, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("error deleting redfish session %s: status code was %d", location, resp.StatusCode)
+	}
+	log.Printf("[DEBUG] Deleted redfish session %s for %s@%s\n", location, t.Username, t.Endpoint)
+	return nil
+}
+
+// withToken returns a copy of the request carrying the session token
+func withToken(req *http.Request, token string) *http.Request {
+	r := req.Clone(req.Context())
+	r.Header.Del("Authorization")
+	r.Header.Set("X-Auth-Token", token)
+	return r
+}
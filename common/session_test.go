@@ -0,0 +1,158 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeSessionService is a minimal redfish SessionService, enough to exercise SessionTransport
+type fakeSessionService struct {
+	mu       sync.Mutex
+	sessions map[string]string // token -> session location
+	logins   int
+	deleted  []string
+	bodies   []string
+}
+
+func newFakeSessionService() (*fakeSessionService, *httptest.Server) {
+	f := &fakeSessionService{sessions: map[string]string{}}
+	return f, httptest.NewServer(http.HandlerFunc(f.handle))
+}
+
+func (f *fakeSessionService) handle(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.URL.Path == "/redfish/v1/":
+		fmt.Fprint(w, `{"Links": {"Sessions": {"@odata.id": "/redfish/v1/SessionService/Sessions"}}}`)
+	case r.Method == http.MethodPost && r.URL.Path == "/redfish/v1/SessionService/Sessions":
+		var login map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&login); err != nil || login["UserName"] != "admin" || login["Password"] != "passw0rd" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		f.logins++
+		token := fmt.Sprintf("token-%d", f.logins)
+		location := fmt.Sprintf("/redfish/v1/SessionService/Sessions/%d", f.logins)
+		f.sessions[token] = location
+		w.Header().Set("X-Auth-Token", token)
+		w.Header().Set("Location", location)
+		w.WriteHeader(http.StatusCreated)
+	default:
+		location, ok := f.sessions[r.Header.Get("X-Auth-Token")]
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Method == http.MethodDelete && r.URL.Path == location {
+			delete(f.sessions, r.Header.Get("X-Auth-Token"))
+			f.deleted = append(f.deleted, location)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		f.bodies = append(f.bodies, string(body))
+		fmt.Fprint(w, `{}`)
+	}
+}
+
+// expire drops every session, as the BMC does when a session times out
+func (f *fakeSessionService) expire() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sessions = map[string]string{}
+}
+
+func doRequest(t *testing.T, client *http.Client, method, url, body string) int {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestSessionTransportReusesSession(t *testing.T) {
+	f, server := newFakeSessionService()
+	defer server.Close()
+
+	client := &http.Client{Transport: NewSessionTransport(server.URL, "admin", "passw0rd", nil)}
+	for i := 0; i < 5; i++ {
+		if got := doRequest(t, client, http.MethodGet, server.URL+"/redfish/v1/Systems", ""); got != http.StatusOK {
+			t.Fatalf("got status %d, want %d", got, http.StatusOK)
+		}
+	}
+	if f.logins != 1 {
+		t.Errorf("got %d logins, want 1", f.logins)
+	}
+}
+
+func TestSessionTransportRenewsExpiredSession(t *testing.T) {
+	f, server := newFakeSessionService()
+	defer server.Close()
+
+	client := &http.Client{Transport: NewSessionTransport(server.URL, "admin", "passw0rd", nil)}
+	doRequest(t, client, http.MethodGet, server.URL+"/redfish/v1/Systems", "")
+	f.expire()
+
+	if got := doRequest(t, client, http.MethodPatch, server.URL+"/redfish/v1/Systems/1", `{"AssetTag": "x"}`); got != http.StatusOK {
+		t.Fatalf("got status %d, want %d", got, http.StatusOK)
+	}
+	if f.logins != 2 {
+		t.Errorf("got %d logins, want 2", f.logins)
+	}
+	// The body must have been replayed on the new session
+	if last := f.bodies[len(f.bodies)-1]; last != `{"AssetTag": "x"}` {
+		t.Errorf("got body %q, want the original payload", last)
+	}
+}
+
+func TestSessionTransportWrongCredentials(t *testing.T) {
+	f, server := newFakeSessionService()
+	defer server.Close()
+
+	client := &http.Client{Transport: NewSessionTransport(server.URL, "admin", "wrong", nil)}
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/redfish/v1/Systems", nil)
+	if resp, err := client.Do(req); err == nil {
+		resp.Body.Close()
+		t.Fatal("expected an error when the session cannot be created")
+	}
+	if f.logins != 0 {
+		t.Errorf("got %d logins, want 0", f.logins)
+	}
+}
+
+func TestSessionTransportLogout(t *testing.T) {
+	f, server := newFakeSessionService()
+	defer server.Close()
+
+	transport := NewSessionTransport(server.URL, "admin", "passw0rd", nil)
+	// Nothing to do before the first request
+	if err := transport.Logout(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	doRequest(t, &http.Client{Transport: transport}, http.MethodGet, server.URL+"/redfish/v1/Systems", "")
+	if err := transport.Logout(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.deleted) != 1 || f.deleted[0] != "/redfish/v1/SessionService/Sessions/1" {
+		t.Errorf("got deleted sessions %v, want [/redfish/v1/SessionService/Sessions/1]", f.deleted)
+	}
+	if len(f.sessions) != 0 {
+		t.Errorf("got %d active sessions, want 0", len(f.sessions))
+	}
+}
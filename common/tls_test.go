@@ -0,0 +1,193 @@
+package common
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM string
+	keyPEM  string
+}
+
+// newTestCert creates a certificate signed by parent, or a self-signed one if parent is nil
+func newTestCert(t *testing.T, commonName string, isCA bool, parent *testCert) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	signer, signerKey := template, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		keyPEM:  string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})),
+	}
+}
+
+func (c *testCert) fingerprint() string {
+	sum := sha256.Sum256(c.cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// newTLSServer starts a TLS server presenting serverCert. If clientCA is set, clients must present a certificate signed by it.
+func newTLSServer(t *testing.T, serverCert *testCert, clientCA *testCert) *httptest.Server {
+	t.Helper()
+	keyPair, err := tls.X509KeyPair([]byte(serverCert.certPEM), []byte(serverCert.keyPEM))
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{keyPair}}
+	if clientCA != nil {
+		pool := x509.NewCertPool()
+		pool.AddCert(clientCA.cert)
+		server.TLS.ClientCAs = pool
+		server.TLS.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func get(t *testing.T, url string, options TLSOptions) error {
+	t.Helper()
+	config, err := options.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: config}}
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func TestTLSOptionsCA(t *testing.T) {
+	ca := newTestCert(t, "Internal CA", true, nil)
+	server := newTLSServer(t, newTestCert(t, "idrac", false, ca), nil)
+
+	if err := get(t, server.URL, TLSOptions{}); err == nil {
+		t.Error("expected the certificate to be rejected without the CA")
+	}
+	if err := get(t, server.URL, TLSOptions{CACertPEM: ca.certPEM}); err != nil {
+		t.Errorf("expected the certificate to be trusted through ca_cert_pem: %v", err)
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, []byte(ca.certPEM), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := get(t, server.URL, TLSOptions{CACertFile: caFile}); err != nil {
+		t.Errorf("expected the certificate to be trusted through ca_cert_file: %v", err)
+	}
+
+	otherCA := newTestCert(t, "Other CA", true, nil)
+	if err := get(t, server.URL, TLSOptions{CACertPEM: otherCA.certPEM}); err == nil {
+		t.Error("expected the certificate to be rejected with an unrelated CA")
+	}
+}
+
+func TestTLSOptionsFingerprint(t *testing.T) {
+	serverCert := newTestCert(t, "idrac", false, nil)
+	server := newTLSServer(t, serverCert, nil)
+
+	if err := get(t, server.URL, TLSOptions{CertFingerprintSHA256: serverCert.fingerprint()}); err != nil {
+		t.Errorf("expected the pinned certificate to be trusted: %v", err)
+	}
+
+	// The usual colon separated uppercase notation is accepted too
+	var pairs []string
+	for i := 0; i < len(serverCert.fingerprint()); i += 2 {
+		pairs = append(pairs, strings.ToUpper(serverCert.fingerprint()[i:i+2]))
+	}
+	if err := get(t, server.URL, TLSOptions{CertFingerprintSHA256: strings.Join(pairs, ":")}); err != nil {
+		t.Errorf("expected the pinned certificate to be trusted: %v", err)
+	}
+
+	other := newTestCert(t, "other", false, nil)
+	err := get(t, server.URL, TLSOptions{CertFingerprintSHA256: other.fingerprint(), Insecure: true})
+	if err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("expected a fingerprint mismatch even with ssl_insecure, got %v", err)
+	}
+}
+
+func TestTLSOptionsClientCertificate(t *testing.T) {
+	ca := newTestCert(t, "Internal CA", true, nil)
+	server := newTLSServer(t, newTestCert(t, "idrac", false, ca), ca)
+	client := newTestCert(t, "terraform", false, ca)
+
+	if err := get(t, server.URL, TLSOptions{CACertPEM: ca.certPEM}); err == nil {
+		t.Error("expected the connection to be rejected without a client certificate")
+	}
+	options := TLSOptions{CACertPEM: ca.certPEM, ClientCert: client.certPEM, ClientKey: client.keyPEM}
+	if err := get(t, server.URL, options); err != nil {
+		t.Errorf("expected the client certificate to be accepted: %v", err)
+	}
+}
+
+func TestTLSOptionsInvalid(t *testing.T) {
+	ca := newTestCert(t, "Internal CA", true, nil)
+	tests := map[string]TLSOptions{
+		"insecure with CA":  {Insecure: true, CACertPEM: ca.certPEM},
+		"invalid CA":        {CACertPEM: "not a certificate"},
+		"missing CA file":   {CACertFile: filepath.Join(t.TempDir(), "missing.pem")},
+		"cert without key":  {ClientCert: ca.certPEM},
+		"mismatched key":    {ClientCert: ca.certPEM, ClientKey: newTestCert(t, "other", false, nil).keyPEM},
+		"short fingerprint": {CertFingerprintSHA256: "ab:cd"},
+	}
+	for name, options := range tests {
+		if _, err := options.Config(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+/*
+Copyright (c) 2023 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+data "redfish_boot_options" "options" {
+  for_each = var.rack1
+
+  redfish_server {
+    user         = each.value.user
+    password     = each.value.password
+    endpoint     = each.value.endpoint
+    ssl_insecure = each.value.ssl_insecure
+  }
+
+  // system_id is an optional argument. By default, the data source uses
+  // the first ComputerSystem resource present in the ComputerSystem collection
+  system_id = "System.Embedded.1"
+}
+
+// The references of the boot options by display name, to build boot orders with
+output "boot_option_references" {
+  value = {
+    for k, v in data.redfish_boot_options.options : k => {
+      for option in v.boot_options : option.display_name => option.boot_option_reference
+    }
+  }
+}
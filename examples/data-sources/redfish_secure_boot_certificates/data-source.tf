@@ -0,0 +1,40 @@
+/*
+Copyright (c) 2023 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+data "redfish_secure_boot_certificates" "certificates" {
+  for_each = var.rack1
+
+  redfish_server {
+    user         = each.value.user
+    password     = each.value.password
+    endpoint     = each.value.endpoint
+    ssl_insecure = each.value.ssl_insecure
+  }
+
+  // database_id is an optional argument. By default, the certificates of
+  // every Secure Boot database are listed
+  database_id = "db"
+}
+
+// The fingerprints of the certificates allowed to sign boot loaders, by subject
+output "db_fingerprints" {
+  value = {
+    for k, v in data.redfish_secure_boot_certificates.certificates : k => {
+      for certificate in v.certificates : certificate.subject_common_name => certificate.fingerprint
+    }
+  }
+}
@@ -31,8 +31,20 @@ resource "redfish_bios" "bios" {
   }
 
   // Reset parameters to be applied after bios settings are applied
-  reset_type    = "ForceRestart"
-  reset_timeout = "120"
-  // The maximum amount of time to wait for the bios job to be completed
-  bios_job_timeout = "1200"
+  reset_type = "ForceRestart"
+
+  // Alternatively, stage the bios settings to be applied in a maintenance window
+  // settings_apply_time           = "AtMaintenanceWindowStart"
+  // maintenance_window_start_time = "2023-11-04T22:00:00-05:00"
+  // maintenance_window_duration   = 3600
+
+  // Restore the attributes to the values they had before being altered when the resource is destroyed
+  on_destroy = "restore_previous"
+
+  // The maximum amount of time to wait for the server reset and the bios job to be completed
+  timeouts {
+    create = "30m"
+    update = "30m"
+    delete = "30m"
+  }
 }
@@ -0,0 +1,36 @@
+/*
+Copyright (c) 2023 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+resource "redfish_bios_password" "setup" {
+  for_each = var.rack1
+
+  redfish_server {
+    user         = each.value.user
+    password     = each.value.password
+    endpoint     = each.value.endpoint
+    ssl_insecure = each.value.ssl_insecure
+  }
+
+  // The BIOS password to set, SetupPassword or SysPassword
+  password_name = "SetupPassword"
+  // The password set before, if any. Changing new_password later rotates the password.
+  old_password = ""
+  new_password = "Setup@123"
+
+  // The reset of the server for the BIOS config job to set the password
+  reset_type = "GracefulRestart"
+}
@@ -0,0 +1,44 @@
+/*
+Copyright (c) 2023 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+resource "redfish_boot" "boot" {
+  for_each = var.rack1
+
+  redfish_server {
+    user         = each.value.user
+    password     = each.value.password
+    endpoint     = each.value.endpoint
+    ssl_insecure = each.value.ssl_insecure
+  }
+
+  // Persistent boot order, listing every boot option of the system
+  boot_order = ["Boot0003", "Boot0004", "Boot0005"]
+
+  // Boot once from the network
+  boot_source_override_enabled = "Once"
+  boot_source_override_target  = "Pxe"
+  boot_source_override_mode    = "UEFI"
+
+  // Reset the system for the override to take effect at once
+  reset_system = true
+  reset_type   = "ForceRestart"
+
+  timeouts {
+    create = "10m"
+    update = "10m"
+  }
+}
@@ -0,0 +1,25 @@
+# Copyright (c) 2023 Dell Inc., or its subsidiaries. All Rights Reserved.
+#
+# Licensed under the Mozilla Public License Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://mozilla.org/MPL/2.0/
+#
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# The import ID is made of the server and the @odata.id of the Redfish object, separated by "|".
+# The server is either the alias of a server declared in the servers block of the provider, or the BMC endpoint.
+# With an endpoint, the user and password of the provider are used, which can also be set through the
+# REDFISH_USER and REDFISH_PASSWORD environment variables. Endpoints whose certificate is not trusted by default, like
+# self-signed ones, take TLS settings after a third "|", such as "https://10.0.0.1|<@odata.id>|ssl_insecure=true".
+# The settings are ssl_insecure, ca_cert_file and cert_fingerprint_sha256, separated by commas.
+
+# Every iDRAC attribute is read on import. The next apply keeps only the attributes in the configuration.
+
+terraform import redfish_dell_idrac_attributes.idrac "my-server-1|/redfish/v1/Managers/iDRAC.Embedded.1/Oem/Dell/DellAttributes/iDRAC.Embedded.1"
@@ -43,12 +43,14 @@ resource "redfish_power" "system_power" {
 
   desired_power_action = "ForceRestart"
 
-  // The maximum amount of time to wait for the server to enter the correct power state before
-  // giving up in seconds
-  maximum_wait_time = 120
-
   // The frequency with which to check the server's power state in seconds
   check_interval = 10
+
+  // The maximum amount of time to wait for the server to enter the correct power state
+  timeouts {
+    create = "10m"
+    update = "10m"
+  }
 }
 
 output "current_power_state" {
@@ -0,0 +1,42 @@
+/*
+Copyright (c) 2023 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+resource "redfish_secure_boot" "secure_boot" {
+  for_each = var.rack1
+
+  redfish_server {
+    user         = each.value.user
+    password     = each.value.password
+    endpoint     = each.value.endpoint
+    ssl_insecure = each.value.ssl_insecure
+  }
+
+  // Enforce Secure Boot from the next boot
+  secure_boot_enable = true
+  secure_boot_mode   = "DeployedMode"
+
+  // Restore the keys of the manufacturer before enabling Secure Boot
+  reset_keys = "ResetAllKeysToDefault"
+
+  // Reset type used to apply the changes
+  reset_type = "ForceRestart"
+
+  timeouts {
+    create = "30m"
+    update = "30m"
+  }
+}
@@ -29,8 +29,11 @@ resource "redfish_simple_update" "update" {
   transfer_protocol     = "HTTP"
   target_firmware_image = "/home/mikeletux/Downloads/BIOS_FXC54_WN64_1.15.0.EXE"
   // Reset parameters to be applied when upgrade is completed
-  reset_type    = "ForceRestart"
-  reset_timeout = 120 // If not set, by default will be 120s
-  // The maximum amount of time to wait for the simple update job to be completed
-  simple_update_job_timeout = 1200 // If not set, by default will be 1200s
+  reset_type = "ForceRestart"
+
+  // The maximum amount of time to wait for the upload, the server reset and the simple update job to be completed
+  timeouts {
+    create = "30m"
+    update = "30m"
+  }
 }
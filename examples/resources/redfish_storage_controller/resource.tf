@@ -0,0 +1,46 @@
+/*
+Copyright (c) 2023 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+resource "redfish_storage_controller" "controller" {
+  for_each = var.rack1
+
+  redfish_server {
+    user         = each.value.user
+    password     = each.value.password
+    endpoint     = each.value.endpoint
+    ssl_insecure = each.value.ssl_insecure
+  }
+
+  storage_controller_id = "RAID.Integrated.1-1"
+  // Settings of the controller, checked against the values it allows
+  controller_mode                        = "RAID"
+  patrol_read_mode                       = "Automatic"
+  rebuild_rate_percent                   = 60
+  check_consistency_rate_percent         = 30
+  background_initialization_rate_percent = 30
+  // Action run when the resource is created with it and every time it changes, either "ResetConfig" or "ClearForeignConfig"
+  // controller_action = "ClearForeignConfig"
+  // Flag stating when to apply the changes either "Immediate" or "OnReset"
+  settings_apply_time = "OnReset"
+  // Reset parameters to be applied when settings_apply_time is "OnReset"
+  reset_type = "ForceRestart"
+
+  // The maximum amount of time to wait for the server resets and the RAID jobs to be completed
+  timeouts {
+    create = "30m"
+    update = "30m"
+  }
+}
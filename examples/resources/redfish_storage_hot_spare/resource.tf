@@ -0,0 +1,45 @@
+/*
+Copyright (c) 2023 Dell Inc., or its subsidiaries. All Rights Reserved.
+
+Licensed under the Mozilla Public License Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://mozilla.org/MPL/2.0/
+
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+resource "redfish_storage_hot_spare" "spare" {
+  for_each = var.rack1
+
+  redfish_server {
+    user         = each.value.user
+    password     = each.value.password
+    endpoint     = each.value.endpoint
+    ssl_insecure = each.value.ssl_insecure
+  }
+
+  storage_controller_id = "RAID.Integrated.1-1"
+  // Name of the physical disk to make a hot spare
+  drive = "Solid State Disk 0:0:3"
+  // The hot spare replaces a failed drive of any volume of the controller
+  hotspare_type = "Global"
+  // A dedicated hot spare replaces the failed drives of the given volumes only
+  // hotspare_type    = "Dedicated"
+  // volume_odata_ids = [redfish_storage_volume.volume[each.key].id]
+  // Flag stating when to assign the hot spare either "Immediate" or "OnReset"
+  settings_apply_time = "OnReset"
+  // Reset parameters to be applied when settings_apply_time is "OnReset"
+  reset_type = "ForceRestart"
+
+  // The maximum amount of time to wait for the server reset and the RAID job to be completed
+  timeouts {
+    create = "30m"
+    delete = "30m"
+  }
+}
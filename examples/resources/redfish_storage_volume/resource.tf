@@ -33,16 +33,20 @@ resource "redfish_storage_volume" "volume" {
   // Flag stating when to create virtual disk either "Immediate" or "OnReset"
   settings_apply_time = "Immediate"
   // Reset parameters to be applied when upgrade is completed
-  reset_type    = "PowerCycle"
-  reset_timeout = 100
-  // The maximum amount of time to wait for the volume job to be completed
-  volume_job_timeout    = 1200
+  reset_type            = "PowerCycle"
   capacity_bytes        = 1073323222
   optimum_io_size_bytes = 131072
   read_cache_policy     = "AdaptiveReadAhead"
   write_cache_policy    = "UnprotectedWriteBack"
   disk_cache_policy     = "Disabled"
 
+  // The maximum amount of time to wait for the server reset and the volume job to be completed
+  timeouts {
+    create = "30m"
+    update = "30m"
+    delete = "30m"
+  }
+
   lifecycle {
     ignore_changes = [
       capacity_bytes,
@@ -50,3 +54,27 @@ resource "redfish_storage_volume" "volume" {
     ]
   }
 }
+
+resource "redfish_storage_volume" "mirror" {
+  for_each = var.rack1
+
+  redfish_server {
+    user         = each.value.user
+    password     = each.value.password
+    endpoint     = each.value.endpoint
+    ssl_insecure = each.value.ssl_insecure
+  }
+
+  storage_controller_id = "RAID.Integrated.1-1"
+  volume_name           = "TerraformMirror"
+  volume_type           = "Mirrored"
+  // Two unassigned SATA SSDs of at least 400GB in slots 2 to 5 are chosen instead of naming them in drives
+  drive_selector {
+    media_type         = "SSD"
+    protocol           = "SATA"
+    min_capacity_bytes = 400000000000
+    min_slot           = 2
+    max_slot           = 5
+    count              = 2
+  }
+}
@@ -0,0 +1,105 @@
+package emulator
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// patchAccount changes an account. Like iDRACs do, it ignores the properties it does not know, warning about them,
+// and refuses the whole request when one of the properties it knows is not valid.
+func (s *Server) patchAccount(w http.ResponseWriter, r *http.Request, uri string) {
+	account := s.resources[uri]
+	if account["Id"] == "1" {
+		writeError(w, http.StatusBadRequest, "Base.1.12.OperationNotAllowed",
+			"The operation was not successful because the account is reserved.")
+		return
+	}
+	var body map[string]interface{}
+	if !decode(w, r, &body) {
+		return
+	}
+
+	changes := map[string]interface{}{}
+	var warnings []map[string]interface{}
+	names := make([]string, 0, len(body))
+	for k := range body {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		v := body[k]
+		switch k {
+		case "UserName":
+			name, ok := v.(string)
+			if !ok {
+				writeTypeError(w, k, v, "#/"+k)
+				return
+			}
+			if name != "" && name != account["UserName"] && s.userNameTaken(name) {
+				writeError(w, http.StatusBadRequest, "IDRAC.2.8.SYS414",
+					fmt.Sprintf("Unable to complete the operation because the user name %s is already in use.", name), "#/UserName")
+				return
+			}
+		case "Password":
+			if _, ok := v.(string); !ok {
+				writeTypeError(w, k, v, "#/"+k)
+				return
+			}
+		case "RoleId":
+			role, ok := v.(string)
+			if !ok {
+				writeTypeError(w, k, v, "#/"+k)
+				return
+			}
+			if _, ok := s.resources[rolesURI+"/"+role]; !ok {
+				writeError(w, http.StatusBadRequest, "Base.1.12.PropertyValueNotInList",
+					fmt.Sprintf("The value %s for the property RoleId is not in the list of acceptable values.", role), "#/RoleId")
+				return
+			}
+		case "Enabled", "Locked":
+			if _, ok := v.(bool); !ok {
+				writeTypeError(w, k, v, "#/"+k)
+				return
+			}
+		default:
+			warnings = append(warnings, message("Base.1.12.PropertyUnknown",
+				fmt.Sprintf("The property %s is not in the list of valid properties for the resource.", k), "Warning", "#/"+k))
+			continue
+		}
+		changes[k] = v
+	}
+	if len(changes) == 0 {
+		writeError(w, http.StatusBadRequest, "Base.1.12.PropertyUnknown",
+			"None of the properties of the request are valid properties for the resource.")
+		return
+	}
+
+	for k, v := range changes {
+		switch k {
+		case "Password":
+			s.passwords[uri] = v.(string)
+		case "RoleId":
+			account[k] = v
+			account["Links"] = map[string]interface{}{"Role": map[string]interface{}{"@odata.id": rolesURI + "/" + v.(string)}}
+		default:
+			account[k] = v
+		}
+	}
+	// An account without a user name is a free slot, it has no password anymore
+	if account["UserName"] == "" {
+		delete(s.passwords, uri)
+	}
+	writeSuccess(w, http.StatusOK, warnings...)
+}
+
+// userNameTaken tells whether an account has the given user name
+func (s *Server) userNameTaken(name string) bool {
+	accounts, _ := s.resources[accountsURI]["Members"].([]interface{})
+	for _, a := range accounts {
+		if s.resources[a.(map[string]interface{})["@odata.id"].(string)]["UserName"] == name {
+			return true
+		}
+	}
+	return false
+}
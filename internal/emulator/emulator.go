@@ -0,0 +1,507 @@
+// Package emulator provides an offline stand-in for the redfish service of a Dell iDRAC, so the resources of the
+// provider can be tested without hardware. It serves the resources of the JSON mockups in the mockups folder over
+// TLS and keeps them in memory: PATCH, POST and DELETE requests change them, and configuration changes go through
+// jobs which progress every time they are read, like the ones of an iDRAC do.
+//
+// Tests can also inject faults, such as errors, malformed responses, latency or failing jobs, to check how the
+// provider copes with a misbehaving iDRAC.
+package emulator
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"sync"
+)
+
+const (
+	// Username and Password are the credentials of the administrator account of the emulated iDRAC
+	Username = "root"
+	Password = "calvin"
+
+	serviceRootURI = "/redfish/v1"
+	systemsURI     = "/redfish/v1/Systems"
+	sessionsURI    = "/redfish/v1/SessionService/Sessions"
+	accountsURI    = "/redfish/v1/AccountService/Accounts"
+	rolesURI       = "/redfish/v1/AccountService/Roles"
+)
+
+//go:embed mockups/*.json
+var mockups embed.FS
+
+// Server is an emulated iDRAC. It is an httptest.Server, so its URL is the endpoint of the redfish service and it
+// must be closed once the test is done.
+type Server struct {
+	*httptest.Server
+
+	// mu serializes requests, every one of them reading or changing the resources
+	mu sync.Mutex
+	// resources holds every resource of the service by @odata.id
+	resources map[string]map[string]interface{}
+	// passwords holds the password of every account by @odata.id, since accounts never show it, and the passwords
+	// of the bios by the @odata.id of the bios followed by their name, like .../Bios/SysPassword
+	passwords map[string]string
+	// pendingPasswords holds the bios passwords changed with the Bios.ChangePassword action, by the same keys as
+	// passwords, until a bios config job applies them
+	pendingPasswords map[string]string
+	// sessions holds the @odata.id of the session of every token
+	sessions map[string]string
+	// jobs holds the jobs by the @odata.id of both their task and their Dell job
+	jobs map[string]*job
+	// resetChanges are the changes the next reset of the system applies, besides the ones of scheduled jobs
+	resetChanges []func()
+	// sequence numbers sessions and jobs
+	sequence int
+	// faults holds the faults injected by tests, in the order they were injected
+	faults []*Fault
+	// jobState is the state the jobs created by the request being served end in
+	jobState string
+}
+
+// NewServer starts an emulated iDRAC in the state described by the mockups
+func NewServer() *Server {
+	s := &Server{
+		resources:        map[string]map[string]interface{}{},
+		passwords:        map[string]string{accountsURI + "/2": Password},
+		pendingPasswords: map[string]string{},
+		sessions:         map[string]string{},
+		jobs:             map[string]*job{},
+	}
+	s.load()
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// load reads the mockups. Every mockup maps the @odata.id of resources to their body.
+func (s *Server) load() {
+	files, err := mockups.ReadDir("mockups")
+	if err != nil {
+		panic(err)
+	}
+	for _, f := range files {
+		data, err := mockups.ReadFile(path.Join("mockups", f.Name()))
+		if err != nil {
+			panic(err)
+		}
+		var resources map[string]map[string]interface{}
+		if err := json.Unmarshal(data, &resources); err != nil {
+			panic(fmt.Sprintf("mockup %s is not valid: %s", f.Name(), err))
+		}
+		for uri, resource := range resources {
+			s.resources[uri] = resource
+		}
+	}
+}
+
+// Resource returns a copy of the resource at uri, or nil if there is none. It lets tests check the state of the
+// emulated iDRAC.
+func (s *Server) Resource(uri string) map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resource, ok := s.resources[strings.TrimSuffix(uri, "/")]
+	if !ok {
+		return nil
+	}
+	return copyResource(resource)
+}
+
+// Modify merges properties into the resource at uri, like a change made on the emulated iDRAC without going through
+// the provider.
+func (s *Server) Modify(uri string, properties map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if resource, ok := s.resources[strings.TrimSuffix(uri, "/")]; ok {
+		merge(resource, copyResource(properties))
+	}
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	uri := strings.TrimSuffix(r.URL.Path, "/")
+	if f := s.takeFault(r.Method, uri); f != nil {
+		s.serveFault(w, r, uri, f)
+		return
+	}
+	s.serve(w, r, uri, completedState)
+}
+
+// serve serves a request, the jobs it creates ending in jobState
+func (s *Server) serve(w http.ResponseWriter, r *http.Request, uri string, jobState string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobState = jobState
+	if !s.authorized(r, uri) {
+		writeError(w, http.StatusUnauthorized, "Base.1.12.NoValidSession",
+			"There is no valid session established with the implementation.")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.get(w, uri)
+	case http.MethodPost:
+		s.post(w, r, uri)
+	case http.MethodPatch:
+		s.patch(w, r, uri)
+	case http.MethodDelete:
+		s.delete(w, uri)
+	default:
+		s.notAllowed(w, r, uri)
+	}
+}
+
+// authorized tells whether the request may be served. Like on an iDRAC, the service root can be read and sessions
+// created without credentials, everything else needs a session token or basic authentication.
+func (s *Server) authorized(r *http.Request, uri string) bool {
+	if r.Method == http.MethodGet && (uri == "/redfish" || uri == serviceRootURI) {
+		return true
+	}
+	if r.Method == http.MethodPost && uri == sessionsURI {
+		return true
+	}
+	if _, ok := s.sessions[r.Header.Get("X-Auth-Token")]; ok {
+		return true
+	}
+	user, password, ok := r.BasicAuth()
+	return ok && s.checkCredentials(user, password)
+}
+
+// checkCredentials tells whether an enabled account has the given user name and password
+func (s *Server) checkCredentials(user, password string) bool {
+	for uri, p := range s.passwords {
+		account := s.resources[uri]
+		if account["UserName"] == user && account["Enabled"] == true && p == password {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) get(w http.ResponseWriter, uri string) {
+	if j, ok := s.jobs[uri]; ok {
+		s.poll(j)
+	}
+	resource, ok := s.resources[uri]
+	if !ok {
+		writeNotFound(w, uri)
+		return
+	}
+	body, err := jsonBody(resource)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Base.1.12.InternalError", err.Error())
+		return
+	}
+	w.Header().Set("ETag", etag(body))
+	w.Header().Set("Content-Type", "application/json;odata.metadata=minimal;charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+func (s *Server) post(w http.ResponseWriter, r *http.Request, uri string) {
+	switch {
+	case uri == sessionsURI:
+		s.login(w, r)
+	case uri == dellJobsURI:
+		s.createJob(w, r)
+	case strings.HasSuffix(uri, "/Actions/ComputerSystem.Reset"):
+		s.resetSystem(w, r, strings.TrimSuffix(uri, "/Actions/ComputerSystem.Reset"))
+	case strings.HasSuffix(uri, "/Actions/Bios.ChangePassword"):
+		s.changeBiosPassword(w, r, strings.TrimSuffix(uri, "/Actions/Bios.ChangePassword"))
+	case strings.HasSuffix(uri, "/Actions/Bios.ResetBios"):
+		s.resetBios(w, strings.TrimSuffix(uri, "/Actions/Bios.ResetBios"))
+	case strings.HasSuffix(uri, "/Actions/SecureBoot.ResetKeys"):
+		s.resetKeys(w, r, strings.TrimSuffix(uri, "/Actions/SecureBoot.ResetKeys"))
+	case strings.HasSuffix(uri, "/Actions/VirtualMedia.InsertMedia"):
+		s.insertMedia(w, r, strings.TrimSuffix(uri, "/Actions/VirtualMedia.InsertMedia"))
+	case strings.HasSuffix(uri, "/Actions/VirtualMedia.EjectMedia"):
+		s.ejectMedia(w, strings.TrimSuffix(uri, "/Actions/VirtualMedia.EjectMedia"))
+	case uri == firmwareInventoryURI:
+		s.uploadPackage(w, r)
+	case uri == simpleUpdateURI:
+		s.simpleUpdate(w, r)
+	case isSecureBootCertificates(uri) && s.resources[uri] != nil:
+		s.addCertificate(w, r, uri)
+	case strings.HasSuffix(uri, "/Volumes") && s.resources[uri] != nil:
+		s.createVolume(w, r, uri)
+	case strings.HasSuffix(uri, "/Actions/DellRaidService.AssignSpare"):
+		s.assignSpare(w, r)
+	case strings.HasSuffix(uri, "/Actions/DellRaidService.UnassignSpare"):
+		s.unassignSpare(w, r)
+	case strings.HasSuffix(uri, "/Actions/DellRaidService.ResetConfig"):
+		s.resetConfig(w, r)
+	case strings.HasSuffix(uri, "/Actions/DellRaidService.ClearForeignConfig"):
+		s.clearForeignConfig(w, r)
+	case strings.HasSuffix(uri, "/Actions/DellRaidService.ConvertToRAID"):
+		s.convertDrives(w, r, true)
+	case strings.HasSuffix(uri, "/Actions/DellRaidService.ConvertToNonRAID"):
+		s.convertDrives(w, r, false)
+	case strings.HasSuffix(uri, "/Settings/Actions/Oem/DellManager.ClearPending"):
+		s.clearPending(w, strings.TrimSuffix(uri, "/Actions/Oem/DellManager.ClearPending"))
+	default:
+		s.notAllowed(w, r, uri)
+	}
+}
+
+func (s *Server) patch(w http.ResponseWriter, r *http.Request, uri string) {
+	switch {
+	case strings.HasSuffix(uri, "/Settings") && s.resources[uri] != nil:
+		s.patchSettings(w, r, uri)
+	case strings.HasPrefix(uri, accountsURI+"/") && s.resources[uri] != nil:
+		s.patchAccount(w, r, uri)
+	case path.Dir(uri) == systemsURI && s.resources[uri] != nil:
+		s.patchSystem(w, r, uri)
+	case strings.Contains(uri, "/Oem/Dell/DellAttributes/") && s.resources[uri] != nil:
+		s.patchDellAttributes(w, r, uri)
+	case path.Base(path.Dir(uri)) == "Drives" && s.resources[uri] != nil:
+		s.patchDrive(w, r, uri)
+	case path.Base(path.Dir(uri)) == "Volumes" && s.resources[uri] != nil:
+		s.patchVolume(w, r, uri)
+	default:
+		s.notAllowed(w, r, uri)
+	}
+}
+
+func (s *Server) delete(w http.ResponseWriter, uri string) {
+	switch {
+	case strings.HasPrefix(uri, sessionsURI+"/") && s.resources[uri] != nil:
+		s.logout(w, uri)
+	case path.Base(path.Dir(uri)) == "Volumes" && s.resources[uri] != nil:
+		s.deleteVolume(w, uri)
+	case isSecureBootCertificates(path.Dir(uri)) && s.resources[uri] != nil:
+		s.deleteCertificate(w, uri)
+	case strings.HasPrefix(uri, dellJobsURI+"/") && s.jobs[uri] != nil:
+		s.deleteJob(w, uri)
+	default:
+		s.notAllowed(w, nil, uri)
+	}
+}
+
+// notAllowed answers requests the emulator does not implement for the resource
+func (s *Server) notAllowed(w http.ResponseWriter, r *http.Request, uri string) {
+	if _, ok := s.resources[uri]; !ok {
+		writeNotFound(w, uri)
+		return
+	}
+	writeError(w, http.StatusMethodNotAllowed, "Base.1.12.OperationNotAllowed",
+		"The operation was not successful because the resource does not support it.")
+}
+
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	var credentials struct {
+		UserName string
+		Password string
+	}
+	if !decode(w, r, &credentials) {
+		return
+	}
+	if !s.checkCredentials(credentials.UserName, credentials.Password) {
+		writeError(w, http.StatusUnauthorized, "Base.1.12.ResourceAtUriUnauthorized",
+			fmt.Sprintf("While accessing the resource at %s, the service received an authorization error unauthorized.", sessionsURI))
+		return
+	}
+
+	s.sequence++
+	uri := fmt.Sprintf("%s/%d", sessionsURI, s.sequence)
+	session := map[string]interface{}{
+		"@odata.id":   uri,
+		"@odata.type": "#Session.v1_6_0.Session",
+		"Id":          fmt.Sprint(s.sequence),
+		"Name":        "User Session",
+		"UserName":    credentials.UserName,
+	}
+	s.resources[uri] = session
+	s.addMember(sessionsURI, uri)
+	token := newToken()
+	s.sessions[token] = uri
+
+	w.Header().Set("X-Auth-Token", token)
+	w.Header().Set("Location", uri)
+	writeJSON(w, http.StatusCreated, session)
+}
+
+func (s *Server) logout(w http.ResponseWriter, uri string) {
+	for token, session := range s.sessions {
+		if session == uri {
+			delete(s.sessions, token)
+		}
+	}
+	s.removeMember(sessionsURI, uri)
+	delete(s.resources, uri)
+	writeSuccess(w, http.StatusOK)
+}
+
+// addMember adds a link to member in the Members of collection
+func (s *Server) addMember(collection, member string) {
+	c := s.resources[collection]
+	members, _ := c["Members"].([]interface{})
+	members = append(members, map[string]interface{}{"@odata.id": member})
+	c["Members"] = members
+	c["Members@odata.count"] = len(members)
+}
+
+// removeMember removes the link to member from the Members of collection
+func (s *Server) removeMember(collection, member string) {
+	c := s.resources[collection]
+	members, _ := c["Members"].([]interface{})
+	c["Members"] = removeLink(members, member)
+	c["Members@odata.count"] = len(c["Members"].([]interface{}))
+}
+
+// removeLink returns links without the ones to uri
+func removeLink(links []interface{}, uri string) []interface{} {
+	kept := []interface{}{}
+	for _, l := range links {
+		if link, ok := l.(map[string]interface{}); !ok || link["@odata.id"] != uri {
+			kept = append(kept, l)
+		}
+	}
+	return kept
+}
+
+// hasLink tells whether links holds a link to uri
+func hasLink(links interface{}, uri string) bool {
+	l, _ := links.([]interface{})
+	return len(removeLink(l, uri)) != len(l)
+}
+
+// merge copies every property of src into dst. Objects are merged property by property, like a PATCH does.
+func merge(dst, src map[string]interface{}) {
+	for k, v := range src {
+		if object, ok := v.(map[string]interface{}); ok {
+			if current, ok := dst[k].(map[string]interface{}); ok {
+				merge(current, object)
+				continue
+			}
+		}
+		dst[k] = v
+	}
+}
+
+// copyResource returns a deep copy of resource
+func copyResource(resource map[string]interface{}) map[string]interface{} {
+	data, _ := json.Marshal(resource)
+	var c map[string]interface{}
+	_ = json.Unmarshal(data, &c)
+	return c
+}
+
+// sameType tells whether a value can replace current without changing the type of a property.
+// Null properties, like write-only ones, take any value.
+func sameType(current, value interface{}) bool {
+	if current == nil {
+		return true
+	}
+	switch current.(type) {
+	case float64:
+		_, ok := value.(float64)
+		return ok
+	case string:
+		_, ok := value.(string)
+		return ok
+	case bool:
+		_, ok := value.(bool)
+		return ok
+	}
+	return true
+}
+
+func contains(values interface{}, value string) bool {
+	list, _ := values.([]interface{})
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func newToken() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// jsonBody returns the body of the response to a GET of resource
+func jsonBody(resource map[string]interface{}) ([]byte, error) {
+	return json.Marshal(resource)
+}
+
+func etag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return fmt.Sprintf(`W/"%s"`, hex.EncodeToString(sum[:4]))
+}
+
+// decode reads the JSON body of the request into v. It answers the request itself when the body is not valid.
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "Base.1.12.MalformedJSON",
+			"The request body submitted was malformed JSON and could not be parsed by the receiving service.")
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json;odata.metadata=minimal;charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// writeSuccess answers with the message iDRACs send when a request succeeds
+func writeSuccess(w http.ResponseWriter, status int, warnings ...map[string]interface{}) {
+	info := []interface{}{map[string]interface{}{
+		"Message":           "Successfully Completed Request",
+		"MessageArgs":       []interface{}{},
+		"MessageId":         "Base.1.12.Success",
+		"RelatedProperties": []interface{}{},
+		"Resolution":        "None",
+		"Severity":          "OK",
+	}}
+	for _, warning := range warnings {
+		info = append(info, warning)
+	}
+	writeJSON(w, status, map[string]interface{}{"@Message.ExtendedInfo": info})
+}
+
+// message returns an entry of @Message.ExtendedInfo
+func message(messageID, text, severity string, relatedProperties ...string) map[string]interface{} {
+	related := []interface{}{}
+	for _, p := range relatedProperties {
+		related = append(related, p)
+	}
+	return map[string]interface{}{
+		"Message":           text,
+		"MessageArgs":       []interface{}{},
+		"MessageId":         messageID,
+		"RelatedProperties": related,
+		"Resolution":        "Correct the request and resubmit it.",
+		"Severity":          severity,
+	}
+}
+
+// writeError answers with a redfish error, the message given being the only entry of @Message.ExtendedInfo
+func writeError(w http.ResponseWriter, status int, messageID, text string, relatedProperties ...string) {
+	writeJSON(w, status, map[string]interface{}{
+		"error": map[string]interface{}{
+			"@Message.ExtendedInfo": []interface{}{message(messageID, text, "Critical", relatedProperties...)},
+			"code":                  "Base.1.12.GeneralError",
+			"message":               "A general error has occurred. See ExtendedInfo for more information",
+		},
+	})
+}
+
+func writeNotFound(w http.ResponseWriter, uri string) {
+	writeError(w, http.StatusNotFound, "Base.1.12.ResourceMissingAtURI",
+		fmt.Sprintf("The resource at the URI %s was not found.", uri))
+}
@@ -0,0 +1,728 @@
+package emulator
+
+import (
+	"net/http"
+	"path"
+	"testing"
+
+	"github.com/stmcginnis/gofish"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+const (
+	systemURI  = "/redfish/v1/Systems/System.Embedded.1"
+	storageURI = systemURI + "/Storage/RAID.Integrated.1-1"
+	drive0URI  = storageURI + "/Drives/Disk.Bay.0:Enclosure.Internal.0-1:RAID.Integrated.1-1"
+	drive1URI  = storageURI + "/Drives/Disk.Bay.1:Enclosure.Internal.0-1:RAID.Integrated.1-1"
+	drive2URI  = storageURI + "/Drives/Disk.Bay.2:Enclosure.Internal.0-1:RAID.Integrated.1-1"
+	drive3URI  = storageURI + "/Drives/Disk.Bay.3:Enclosure.Internal.0-1:RAID.Integrated.1-1"
+)
+
+func connect(t *testing.T) (*Server, *gofish.APIClient) {
+	t.Helper()
+	server := NewServer()
+	t.Cleanup(server.Close)
+	api, err := gofish.Connect(gofish.ClientConfig{
+		Endpoint:   server.URL,
+		Username:   Username,
+		Password:   Password,
+		HTTPClient: server.Client(),
+	})
+	if err != nil {
+		t.Fatalf("connecting to the emulator: %s", err)
+	}
+	t.Cleanup(api.Logout)
+	return server, api
+}
+
+// waitForJob reads the task at uri until it completes, failing if it takes more reads than a job should
+func waitForJob(t *testing.T, api *gofish.APIClient, uri string) {
+	t.Helper()
+	for i := 0; i <= runningPolls+1; i++ {
+		task, err := redfish.GetTask(api, uri)
+		if err != nil {
+			t.Fatalf("reading task %s: %s", uri, err)
+		}
+		if task.TaskState == redfish.CompletedTaskState {
+			return
+		}
+	}
+	t.Fatalf("task %s did not complete", uri)
+}
+
+func TestSessions(t *testing.T) {
+	server, api := connect(t)
+
+	if sessions := server.Resource(sessionsURI)["Members@odata.count"]; sessions != float64(1) {
+		t.Errorf("got %v sessions after login, want 1", sessions)
+	}
+	api.Logout()
+	if sessions := server.Resource(sessionsURI)["Members@odata.count"]; sessions != float64(0) {
+		t.Errorf("got %v sessions after logout, want 0", sessions)
+	}
+
+	_, err := gofish.Connect(gofish.ClientConfig{
+		Endpoint:   server.URL,
+		Username:   Username,
+		Password:   "wrong",
+		HTTPClient: server.Client(),
+	})
+	if err == nil {
+		t.Error("logging in with a wrong password succeeded")
+	}
+
+	res, err := server.Client().Get(server.URL + systemURI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusUnauthorized {
+		t.Errorf("reading a system without credentials returned %d, want %d", res.StatusCode, http.StatusUnauthorized)
+	}
+}
+
+func TestResetStartsScheduledJobs(t *testing.T) {
+	server, api := connect(t)
+
+	res, err := api.Patch(systemURI+"/Bios/Settings", map[string]interface{}{
+		"Attributes":                 map[string]interface{}{"NumLock": "Off"},
+		"@Redfish.SettingsApplyTime": map[string]interface{}{"ApplyTime": "OnReset"},
+	})
+	if err != nil {
+		t.Fatalf("patching the bios settings: %s", err)
+	}
+	res.Body.Close()
+	taskURI := res.Header.Get("Location")
+
+	task, err := redfish.GetTask(api, taskURI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.TaskState != redfish.TaskState(scheduledState) {
+		t.Errorf("task state is %s before the reset, want %s", task.TaskState, scheduledState)
+	}
+	if pending := server.Resource(systemURI + "/Bios/Settings")["Attributes"].(map[string]interface{})["NumLock"]; pending != "Off" {
+		t.Errorf("pending NumLock is %v, want Off", pending)
+	}
+
+	system, err := redfish.GetComputerSystem(api, systemURI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := system.Reset(redfish.ForceOffResetType); err != nil {
+		t.Fatalf("powering off: %s", err)
+	}
+	if err := system.Reset(redfish.ForceOffResetType); err == nil {
+		t.Error("powering off a system already off succeeded")
+	}
+	if err := system.Reset(redfish.OnResetType); err != nil {
+		t.Fatalf("powering on: %s", err)
+	}
+	waitForJob(t, api, taskURI)
+
+	if numLock := server.Resource(systemURI + "/Bios")["Attributes"].(map[string]interface{})["NumLock"]; numLock != "Off" {
+		t.Errorf("NumLock is %v after the job, want Off", numLock)
+	}
+	if pending := server.Resource(systemURI + "/Bios/Settings")["Attributes"].(map[string]interface{}); len(pending) != 0 {
+		t.Errorf("got pending attributes %v after the job, want none", pending)
+	}
+}
+
+func TestClearPending(t *testing.T) {
+	server, api := connect(t)
+
+	res, err := api.Patch(systemURI+"/Bios/Settings", map[string]interface{}{
+		"Attributes":                 map[string]interface{}{"NumLock": "Off"},
+		"@Redfish.SettingsApplyTime": map[string]interface{}{"ApplyTime": "OnReset"},
+	})
+	if err != nil {
+		t.Fatalf("patching the bios settings: %s", err)
+	}
+	res.Body.Close()
+	job, err := redfish.GetTask(api, res.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clearPending := systemURI + "/Bios/Settings/Actions/Oem/DellManager.ClearPending"
+	if _, err := api.Post(clearPending, map[string]interface{}{}); err == nil {
+		t.Error("clearing settings a scheduled job is to apply succeeded")
+	}
+	res, err = api.Delete(dellJobsURI + "/" + job.ID)
+	if err != nil {
+		t.Fatalf("deleting the job: %s", err)
+	}
+	res.Body.Close()
+	res, err = api.Post(clearPending, map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("clearing the pending settings: %s", err)
+	}
+	res.Body.Close()
+
+	if pending := server.Resource(systemURI + "/Bios/Settings")["Attributes"].(map[string]interface{}); len(pending) != 0 {
+		t.Errorf("got pending attributes %v after clearing them, want none", pending)
+	}
+	if server.Resource(dellJobsURI+"/"+job.ID) != nil {
+		t.Errorf("the job %s still exists", job.ID)
+	}
+}
+
+func TestResetBios(t *testing.T) {
+	server, api := connect(t)
+
+	res, err := api.Patch(systemURI+"/Bios/Settings", map[string]interface{}{
+		"Attributes": map[string]interface{}{"NumLock": "Off"},
+	})
+	if err != nil {
+		t.Fatalf("patching the bios settings: %s", err)
+	}
+	res.Body.Close()
+	taskURI := res.Header.Get("Location")
+	system, err := redfish.GetComputerSystem(api, systemURI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := system.Reset(redfish.ForceRestartResetType); err != nil {
+		t.Fatalf("resetting the system: %s", err)
+	}
+	waitForJob(t, api, taskURI)
+
+	bios, err := system.Bios()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bios.ResetBios(); err != nil {
+		t.Fatalf("resetting the bios: %s", err)
+	}
+	if numLock := server.Resource(systemURI + "/Bios")["Attributes"].(map[string]interface{})["NumLock"]; numLock != "Off" {
+		t.Errorf("NumLock is %v before the reset, want Off", numLock)
+	}
+	if err := system.Reset(redfish.ForceRestartResetType); err != nil {
+		t.Fatalf("resetting the system: %s", err)
+	}
+	if numLock := server.Resource(systemURI + "/Bios")["Attributes"].(map[string]interface{})["NumLock"]; numLock != "On" {
+		t.Errorf("NumLock is %v after the reset, want its default On", numLock)
+	}
+}
+
+func TestChangeBiosPassword(t *testing.T) {
+	server, api := connect(t)
+
+	changePassword := func(name, old, new string) error {
+		res, err := api.Post(systemURI+"/Bios/Actions/Bios.ChangePassword", map[string]interface{}{
+			"PasswordName": name,
+			"OldPassword":  old,
+			"NewPassword":  new,
+		})
+		if err == nil {
+			res.Body.Close()
+		}
+		return err
+	}
+	createJob := func() (string, error) {
+		res, err := api.Post(dellJobsURI, map[string]interface{}{"TargetSettingsURI": systemURI + "/Bios/Settings"})
+		if err != nil {
+			return "", err
+		}
+		res.Body.Close()
+		return tasksURI + "/" + path.Base(res.Header.Get("Location")), nil
+	}
+
+	if _, err := createJob(); err == nil {
+		t.Error("creating a bios config job without pending changes succeeded")
+	}
+	if err := changePassword("SysPassword", "", "Passw0rd!"); err != nil {
+		t.Fatalf("setting the password: %s", err)
+	}
+	if _, ok := server.passwords[systemURI+"/Bios/SysPassword"]; ok {
+		t.Error("the password was set before a bios config job applied it")
+	}
+	jobURI, err := createJob()
+	if err != nil {
+		t.Fatalf("creating the bios config job: %s", err)
+	}
+	if _, err := createJob(); err == nil {
+		t.Error("creating a second bios config job succeeded")
+	}
+	system, err := redfish.GetComputerSystem(api, systemURI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := system.Reset(redfish.ForceRestartResetType); err != nil {
+		t.Fatalf("resetting the system: %s", err)
+	}
+	waitForJob(t, api, jobURI)
+
+	if err := changePassword("SysPassword", "wrong", "Other0ne!"); err == nil {
+		t.Error("changing the password with a wrong old one succeeded")
+	}
+	if err := changePassword("SysPassword", "Passw0rd!", "Other0ne!"); err != nil {
+		t.Fatalf("changing the password: %s", err)
+	}
+	if password := server.passwords[systemURI+"/Bios/SysPassword"]; password != "Passw0rd!" {
+		t.Errorf("got password %q, want Passw0rd! until the change is applied", password)
+	}
+	if err := changePassword("NumLock", "", "On"); err == nil {
+		t.Error("changing an attribute which is not a password succeeded")
+	}
+}
+
+func TestPatchBoot(t *testing.T) {
+	server, api := connect(t)
+
+	system, err := redfish.GetComputerSystem(api, systemURI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, boot := range map[string]map[string]interface{}{
+		"missing boot option": {"BootOrder": []string{"Boot0004", "Boot0003"}},
+		"unknown boot option": {"BootOrder": []string{"Boot0004", "Boot0003", "Boot0009"}},
+		"target":              {"BootSourceOverrideTarget": "Network"},
+		"unknown property":    {"BootNext": "Boot0004"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := api.Patch(systemURI, map[string]interface{}{"Boot": boot}); err == nil {
+				t.Error("the patch succeeded")
+			}
+		})
+	}
+
+	if err := system.SetBoot(redfish.Boot{
+		BootOrder:                 []string{"Boot0005", "Boot0003", "Boot0004"},
+		BootSourceOverrideEnabled: redfish.OnceBootSourceOverrideEnabled,
+		BootSourceOverrideTarget:  redfish.PxeBootSourceOverrideTarget,
+	}); err != nil {
+		t.Fatalf("patching the boot settings: %s", err)
+	}
+	boot := server.Resource(systemURI)["Boot"].(map[string]interface{})
+	if boot["BootSourceOverrideTarget"] != "Pxe" || boot["BootOrder"].([]interface{})[0] != "Boot0005" {
+		t.Errorf("got boot settings %v after the patch", boot)
+	}
+
+	if err := system.Reset(redfish.ForceRestartResetType); err != nil {
+		t.Fatalf("resetting the system: %s", err)
+	}
+	boot = server.Resource(systemURI)["Boot"].(map[string]interface{})
+	if boot["BootSourceOverrideEnabled"] != "Disabled" || boot["BootSourceOverrideTarget"] != "None" {
+		t.Errorf("got override %v of %v after the reset, want the one time override used up",
+			boot["BootSourceOverrideEnabled"], boot["BootSourceOverrideTarget"])
+	}
+}
+
+func TestSecureBoot(t *testing.T) {
+	server, api := connect(t)
+
+	for name, payload := range map[string]map[string]interface{}{
+		"wrong type": {"SecureBootEnable": "true"},
+		"mode":       {"SecureBootMode": "SetupMode"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := api.Patch(systemURI+"/SecureBoot/Settings", payload); err == nil {
+				t.Error("the patch succeeded")
+			}
+		})
+	}
+
+	res, err := api.Patch(systemURI+"/SecureBoot/Settings", map[string]interface{}{"SecureBootEnable": true})
+	if err != nil {
+		t.Fatalf("patching the secure boot settings: %s", err)
+	}
+	res.Body.Close()
+	taskURI := res.Header.Get("Location")
+	system, err := redfish.GetComputerSystem(api, systemURI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := system.Reset(redfish.ForceRestartResetType); err != nil {
+		t.Fatalf("resetting the system: %s", err)
+	}
+	waitForJob(t, api, taskURI)
+	if current := server.Resource(systemURI + "/SecureBoot")["SecureBootCurrentBoot"]; current != "Enabled" {
+		t.Errorf("SecureBootCurrentBoot is %v once enabled, want Enabled", current)
+	}
+
+	secureBoot, err := system.SecureBoot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := secureBoot.ResetKeys(redfish.DeletePKResetKeysType); err != nil {
+		t.Fatalf("deleting the platform key: %s", err)
+	}
+	if err := system.Reset(redfish.ForceRestartResetType); err != nil {
+		t.Fatalf("resetting the system: %s", err)
+	}
+	secureBoot, err = system.SecureBoot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secureBoot.SecureBootMode != redfish.SetupModeSecureBootModeType || secureBoot.SecureBootCurrentBoot != redfish.DisabledSecureBootCurrentBootType {
+		t.Errorf("got mode %s and current boot %s without a platform key", secureBoot.SecureBootMode, secureBoot.SecureBootCurrentBoot)
+	}
+}
+
+func TestSecureBootCertificates(t *testing.T) {
+	server, api := connect(t)
+	databasesURI := systemURI + "/SecureBoot/SecureBootDatabases"
+
+	enroll := func(database, certificate string) (string, error) {
+		res, err := api.Post(databasesURI+"/"+database+"/Certificates", map[string]interface{}{
+			"CertificateString": certificate,
+			"CertificateType":   "PEM",
+		})
+		if err != nil {
+			return "", err
+		}
+		res.Body.Close()
+		return res.Header.Get("Location"), nil
+	}
+
+	certificate := NewCertificate("Example Signing Key")
+	uri, err := enroll("db", certificate)
+	if err != nil {
+		t.Fatalf("enrolling the certificate: %s", err)
+	}
+	enrolled, err := redfish.GetCertificate(api, uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if enrolled.Subject.CommonName != "Example Signing Key" || enrolled.FingerprintHashAlgorithm != "TPM_ALG_SHA256" || len(enrolled.Fingerprint) != 95 {
+		t.Errorf("got subject %v and fingerprint %s %s", enrolled.Subject, enrolled.FingerprintHashAlgorithm, enrolled.Fingerprint)
+	}
+
+	for name, tt := range map[string]struct{ database, certificate string }{
+		"enrolled twice":    {"db", certificate},
+		"second PK":         {"PK", NewCertificate("Other Platform Key")},
+		"not a certificate": {"KEK", "-----BEGIN CERTIFICATE-----\nbm90IGEgY2VydGlmaWNhdGU=\n-----END CERTIFICATE-----\n"},
+		"unknown database":  {"dbt", certificate},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := enroll(tt.database, tt.certificate); err == nil {
+				t.Error("the certificate was enrolled")
+			}
+		})
+	}
+
+	pk, err := redfish.ListReferencedCertificates(api, databasesURI+"/PK/Certificates")
+	if err != nil || len(pk) != 1 {
+		t.Fatalf("got %d platform keys: %v", len(pk), err)
+	}
+	res, err := api.Delete(pk[0].ODataID)
+	if err != nil {
+		t.Fatalf("deleting the platform key: %s", err)
+	}
+	res.Body.Close()
+	if mode := server.Resource(systemURI + "/SecureBoot")["SecureBootMode"]; mode != "SetupMode" {
+		t.Errorf("SecureBootMode is %v without a platform key, want SetupMode", mode)
+	}
+}
+
+func TestSettingsValidation(t *testing.T) {
+	_, api := connect(t)
+
+	for name, payload := range map[string]map[string]interface{}{
+		"unknown attribute":  {"Attributes": map[string]interface{}{"Unknown": "Off"}},
+		"wrong type":         {"Attributes": map[string]interface{}{"AcPwrRcvryUserDelay": "60"}},
+		"apply time":         {"Attributes": map[string]interface{}{"NumLock": "Off"}, "@Redfish.SettingsApplyTime": map[string]interface{}{"ApplyTime": "Immediate"}},
+		"maintenance window": {"Attributes": map[string]interface{}{"NumLock": "Off"}, "@Redfish.SettingsApplyTime": map[string]interface{}{"ApplyTime": "AtMaintenanceWindowStart"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := api.Patch(systemURI+"/Bios/Settings", payload)
+			if err == nil {
+				t.Error("the patch succeeded")
+			}
+		})
+	}
+}
+
+func TestVolumeLifecycle(t *testing.T) {
+	server, api := connect(t)
+
+	newVolume := map[string]interface{}{
+		"VolumeType":                  "Mirrored",
+		"Name":                        "MyVol",
+		"@Redfish.OperationApplyTime": "Immediate",
+		"Drives": []map[string]string{
+			{"@odata.id": drive0URI},
+			{"@odata.id": drive1URI},
+		},
+	}
+	empty := map[string]interface{}{"CapacityBytes": 0}
+	for k, v := range newVolume {
+		empty[k] = v
+	}
+	if _, err := api.Post(storageURI+"/Volumes", empty); err == nil {
+		t.Error("creating a volume of 0 bytes succeeded")
+	}
+	res, err := api.Post(storageURI+"/Volumes", newVolume)
+	if err != nil {
+		t.Fatalf("creating the volume: %s", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusAccepted {
+		t.Fatalf("creating the volume returned %d, want %d", res.StatusCode, http.StatusAccepted)
+	}
+	waitForJob(t, api, res.Header.Get("Location"))
+
+	storage, err := redfish.GetStorage(api, storageURI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	volumes, err := storage.Volumes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(volumes) != 1 || volumes[0].Name != "MyVol" {
+		t.Fatalf("got volumes %v, want MyVol only", volumes)
+	}
+	if volumes[0].CapacityBytes != 479559942144 {
+		t.Errorf("got a capacity of %d bytes, want the one of a single drive", volumes[0].CapacityBytes)
+	}
+	drives, err := volumes[0].Drives()
+	if err != nil || len(drives) != 2 {
+		t.Errorf("got drives %v (%v), want 2 of them", drives, err)
+	}
+
+	if _, err := api.Post(storageURI+"/Volumes", newVolume); err == nil {
+		t.Error("creating a volume on drives in use succeeded")
+	}
+
+	res, err = api.Delete(volumes[0].ODataID)
+	if err != nil {
+		t.Fatalf("deleting the volume: %s", err)
+	}
+	res.Body.Close()
+	waitForJob(t, api, res.Header.Get("Location"))
+
+	if volume := server.Resource(volumes[0].ODataID); volume != nil {
+		t.Error("the volume still exists after its deletion")
+	}
+	links := server.Resource(drive0URI)["Links"].(map[string]interface{})
+	if count := links["Volumes@odata.count"]; count != float64(0) {
+		t.Errorf("the drive still belongs to %v volumes after the deletion", count)
+	}
+}
+
+func TestVolumeRAIDTypes(t *testing.T) {
+	server, api := connect(t)
+
+	drives := []map[string]string{
+		{"@odata.id": drive0URI},
+		{"@odata.id": drive1URI},
+		{"@odata.id": drive2URI},
+		{"@odata.id": drive3URI},
+	}
+	for _, raidType := range []string{"RAID1E", "RAID60"} {
+		if _, err := api.Post(storageURI+"/Volumes", map[string]interface{}{"RAIDType": raidType, "Name": "MyVol", "Drives": drives}); err == nil {
+			t.Errorf("creating a %s volume on 4 drives succeeded", raidType)
+		}
+	}
+	spans := map[string]interface{}{"Dell": map[string]interface{}{"DellVolume": map[string]interface{}{"SpanDepth": 3, "SpanLength": 2}}}
+	if _, err := api.Post(storageURI+"/Volumes", map[string]interface{}{"RAIDType": "RAID10", "Name": "MyVol", "Drives": drives, "Oem": spans}); err == nil {
+		t.Error("creating a volume with more drives in its spans than it has succeeded")
+	}
+
+	res, err := api.Post(storageURI+"/Volumes", map[string]interface{}{"RAIDType": "RAID6", "Name": "MyVol", "Drives": drives})
+	if err != nil {
+		t.Fatalf("creating the volume: %s", err)
+	}
+	res.Body.Close()
+	waitForJob(t, api, res.Header.Get("Location"))
+
+	storage, err := redfish.GetStorage(api, storageURI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	volumes, err := storage.Volumes()
+	if err != nil || len(volumes) != 1 {
+		t.Fatalf("got volumes %v (%v), want 1", volumes, err)
+	}
+	if volumes[0].VolumeType != redfish.StripedWithParityVolumeType || volumes[0].CapacityBytes != 2*479559942144 {
+		t.Errorf("got a %s volume of %d bytes, want a StripedWithParity one of 2 drives", volumes[0].VolumeType, volumes[0].CapacityBytes)
+	}
+	dell := server.Resource(volumes[0].ODataID)["Oem"].(map[string]interface{})["Dell"].(map[string]interface{})["DellVolume"].(map[string]interface{})
+	if dell["SpanDepth"] != float64(1) || dell["SpanLength"] != float64(4) {
+		t.Errorf("got a span depth of %v and a span length of %v, want 1 and 4", dell["SpanDepth"], dell["SpanLength"])
+	}
+}
+
+func TestHotSpares(t *testing.T) {
+	server, api := connect(t)
+	raidServiceURI := systemURI + "/Oem/Dell/DellRaidService"
+	post := func(uri string, payload map[string]interface{}) {
+		t.Helper()
+		res, err := api.Post(uri, payload)
+		if err != nil {
+			t.Fatalf("posting to %s: %s", uri, err)
+		}
+		res.Body.Close()
+		waitForJob(t, api, res.Header.Get("Location"))
+	}
+	hotspareType := func(driveURI string) interface{} {
+		return server.Resource(driveURI)["HotspareType"]
+	}
+
+	post(raidServiceURI+"/Actions/DellRaidService.AssignSpare", map[string]interface{}{"TargetFQDD": path.Base(drive3URI)})
+	if got := hotspareType(drive3URI); got != "Global" {
+		t.Errorf("got a %v hot spare, want a Global one", got)
+	}
+	if _, err := api.Post(storageURI+"/Volumes", map[string]interface{}{
+		"RAIDType": "RAID0", "Name": "MyVol", "Drives": []map[string]string{{"@odata.id": drive3URI}},
+	}); err == nil {
+		t.Error("creating a volume on a hot spare succeeded")
+	}
+	post(raidServiceURI+"/Actions/DellRaidService.UnassignSpare", map[string]interface{}{"TargetFQDD": path.Base(drive3URI)})
+	if got := hotspareType(drive3URI); got != "None" {
+		t.Errorf("got a %v hot spare after unassigning it, want None", got)
+	}
+	if _, err := api.Post(raidServiceURI+"/Actions/DellRaidService.UnassignSpare", map[string]interface{}{"TargetFQDD": path.Base(drive3URI)}); err == nil {
+		t.Error("unassigning a drive which is not a hot spare succeeded")
+	}
+
+	post(storageURI+"/Volumes", map[string]interface{}{
+		"RAIDType": "RAID0", "Name": "MyVol", "Drives": []map[string]string{{"@odata.id": drive0URI}},
+	})
+	volumeURI := server.Resource(storageURI + "/Volumes")["Members"].([]interface{})[0].(map[string]interface{})["@odata.id"].(string)
+	post(raidServiceURI+"/Actions/DellRaidService.AssignSpare", map[string]interface{}{
+		"TargetFQDD": path.Base(drive1URI), "VirtualDiskArray": []string{path.Base(volumeURI)},
+	})
+	if got := hotspareType(drive1URI); got != "Dedicated" {
+		t.Errorf("got a %v hot spare, want a Dedicated one", got)
+	}
+	if count := server.Resource(volumeURI)["Links"].(map[string]interface{})["DedicatedSpareDrives@odata.count"]; count != float64(1) {
+		t.Errorf("the volume has %v dedicated hot spares, want 1", count)
+	}
+	res, err := api.Delete(volumeURI)
+	if err != nil {
+		t.Fatalf("deleting the volume: %s", err)
+	}
+	res.Body.Close()
+	waitForJob(t, api, res.Header.Get("Location"))
+	if got := hotspareType(drive1URI); got != "None" {
+		t.Errorf("got a %v hot spare after deleting its volume, want None", got)
+	}
+}
+
+func TestConvertDrives(t *testing.T) {
+	server, api := connect(t)
+	raidServiceURI := systemURI + "/Oem/Dell/DellRaidService"
+	convert := func(action string, driveURIs ...string) {
+		t.Helper()
+		fqdds := make([]string, 0, len(driveURIs))
+		for _, uri := range driveURIs {
+			fqdds = append(fqdds, path.Base(uri))
+		}
+		res, err := api.Post(raidServiceURI+"/Actions/DellRaidService."+action, map[string]interface{}{"PDArray": fqdds})
+		if err != nil {
+			t.Fatalf("converting the drives with %s: %s", action, err)
+		}
+		res.Body.Close()
+		waitForJob(t, api, res.Header.Get("Location"))
+	}
+
+	convert("ConvertToNonRAID", drive2URI, drive3URI)
+	for _, uri := range []string{drive2URI, drive3URI} {
+		if got := raidStatus(server.Resource(uri)); got != "Non-RAID" {
+			t.Errorf("got the RAID status %s for %s, want Non-RAID", got, uri)
+		}
+	}
+	if _, err := api.Post(storageURI+"/Volumes", map[string]interface{}{
+		"RAIDType": "RAID0", "Name": "MyVol", "Drives": []map[string]string{{"@odata.id": drive2URI}},
+	}); err == nil {
+		t.Error("creating a volume on a non-RAID drive succeeded")
+	}
+	if _, err := api.Post(raidServiceURI+"/Actions/DellRaidService.ConvertToNonRAID", map[string]interface{}{"PDArray": []string{path.Base(drive2URI)}}); err == nil {
+		t.Error("converting a non-RAID drive to non-RAID succeeded")
+	}
+
+	convert("ConvertToRAID", drive2URI)
+	if got := raidStatus(server.Resource(drive2URI)); got != "Ready" {
+		t.Errorf("got the RAID status %s after converting the drive to RAID, want Ready", got)
+	}
+}
+
+func TestStorageControllerSettings(t *testing.T) {
+	server, api := connect(t)
+	raidServiceURI := systemURI + "/Oem/Dell/DellRaidService"
+	controller := func(settings map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"Oem": map[string]interface{}{"Dell": map[string]interface{}{"DellStorageController": settings}}}
+	}
+
+	res, err := api.Patch(storageURI+"/Settings", controller(map[string]interface{}{"PatrolReadMode": "Manual", "RebuildRatePercent": 60}))
+	if err != nil {
+		t.Fatalf("changing the controller settings: %s", err)
+	}
+	res.Body.Close()
+	waitForJob(t, api, res.Header.Get("Location"))
+	settings := server.Resource(storageURI)["Oem"].(map[string]interface{})["Dell"].(map[string]interface{})["DellStorageController"].(map[string]interface{})
+	if settings["PatrolReadMode"] != "Manual" || settings["RebuildRatePercent"] != float64(60) {
+		t.Errorf("got the patrol read mode %v and rebuild rate %v, want Manual and 60", settings["PatrolReadMode"], settings["RebuildRatePercent"])
+	}
+	if _, err := api.Patch(storageURI+"/Settings", controller(map[string]interface{}{"ControllerMode": "JBOD"})); err == nil {
+		t.Error("changing the controller mode to a value which is not allowed succeeded")
+	}
+
+	res, err = api.Post(storageURI+"/Volumes", map[string]interface{}{
+		"RAIDType": "RAID0", "Name": "MyVol", "Drives": []map[string]string{{"@odata.id": drive0URI}},
+	})
+	if err != nil {
+		t.Fatalf("creating the volume: %s", err)
+	}
+	res.Body.Close()
+	waitForJob(t, api, res.Header.Get("Location"))
+	res, err = api.Post(raidServiceURI+"/Actions/DellRaidService.ResetConfig", map[string]interface{}{"TargetFQDD": path.Base(storageURI)})
+	if err != nil {
+		t.Fatalf("resetting the controller configuration: %s", err)
+	}
+	res.Body.Close()
+	waitForJob(t, api, res.Header.Get("Location"))
+	if count := server.Resource(storageURI + "/Volumes")["Members@odata.count"]; count != float64(0) {
+		t.Errorf("the controller has %v volumes after resetting its configuration, want 0", count)
+	}
+	if _, err := api.Post(raidServiceURI+"/Actions/DellRaidService.ClearForeignConfig", map[string]interface{}{"TargetFQDD": "RAID.Slot.9-1"}); err == nil {
+		t.Error("clearing the foreign configuration of an unknown controller succeeded")
+	}
+}
+
+func TestAccounts(t *testing.T) {
+	server, api := connect(t)
+
+	res, err := api.Patch(accountsURI+"/3", map[string]interface{}{
+		"UserName": "test",
+		"Password": "T0pSecret!",
+		"RoleId":   "Operator",
+		"Enabled":  true,
+	})
+	if err != nil {
+		t.Fatalf("creating the account: %s", err)
+	}
+	res.Body.Close()
+	if !server.checkCredentials("test", "T0pSecret!") {
+		t.Error("the new account cannot log in")
+	}
+
+	if _, err := api.Patch(accountsURI+"/4", map[string]interface{}{"UserName": "test"}); err == nil {
+		t.Error("creating an account with a user name in use succeeded")
+	}
+	if _, err := api.Patch(accountsURI+"/1", map[string]interface{}{"UserName": "other"}); err == nil {
+		t.Error("changing the reserved account succeeded")
+	}
+
+	// Unknown properties are only a warning, like the misspelt Enabled property here
+	res, err = api.Patch(accountsURI+"/3", map[string]interface{}{"Enable": "false", "RoleId": "None"})
+	if err != nil {
+		t.Fatalf("changing the role: %s", err)
+	}
+	res.Body.Close()
+	if role := server.Resource(accountsURI + "/3")["RoleId"]; role != "None" {
+		t.Errorf("got role %v, want None", role)
+	}
+
+	res, err = api.Patch(accountsURI+"/3", map[string]interface{}{"UserName": ""})
+	if err != nil {
+		t.Fatalf("deleting the account: %s", err)
+	}
+	res.Body.Close()
+	if _, ok := server.passwords[accountsURI+"/3"]; ok {
+		t.Error("the deleted account still has a password")
+	}
+}
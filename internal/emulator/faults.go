@@ -0,0 +1,163 @@
+package emulator
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+)
+
+const (
+	// NotReadyMessageID and NotReadyMessage make up the error iDRACs intermittently answer with while they are busy
+	NotReadyMessageID = "IDRAC.2.8.SYS446"
+	NotReadyMessage   = "iDRAC is not ready. The configuration values cannot be accessed. Please retry after a few minutes."
+
+	// JobException and JobKilled are the states failing jobs end in
+	JobException = exceptionState
+	JobKilled    = killedState
+)
+
+// Fault describes how the emulated iDRAC misbehaves on some requests. Every field left to its zero value keeps the
+// normal behaviour, so for instance a fault with only a latency delays responses without changing them.
+type Fault struct {
+	// Method is the method of the requests the fault applies to. Empty applies it to every method.
+	Method string
+	// URI is the path of the requests the fault applies to, without trailing slash. A URI ending with * applies
+	// it to every path starting with the rest, like /redfish/v1/TaskService/Tasks/* for every task.
+	URI string
+	// Count is the number of requests the fault applies to, after which it is cleared. 0 never clears it.
+	Count int
+
+	// Latency delays the response
+	Latency time.Duration
+	// DropConnection closes the connection without answering
+	DropConnection bool
+	// StatusCode answers with a redfish error of this status instead of serving the request. MessageID and Message
+	// make up the extended information of the error.
+	StatusCode int
+	MessageID  string
+	Message    string
+	// MalformedJSON truncates the body of the response, which becomes invalid JSON
+	MalformedJSON bool
+	// NoLocation removes the Location header from the response, like the job ID of a 202 Accepted
+	NoLocation bool
+	// JobState is the state the jobs created by the request end in instead of completing, such as JobException or
+	// JobKilled. The changes of failing jobs are not applied.
+	JobState string
+}
+
+// NotReady returns a fault answering count requests to uri with the "iDRAC is not ready" error
+func NotReady(uri string, count int) Fault {
+	return Fault{
+		URI:        uri,
+		Count:      count,
+		StatusCode: http.StatusServiceUnavailable,
+		MessageID:  NotReadyMessageID,
+		Message:    NotReadyMessage,
+	}
+}
+
+// Inject makes the emulated iDRAC misbehave as f describes. When several faults apply to a request, the first
+// one injected wins.
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes every fault injected, the emulated iDRAC behaving normally again
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+func (f *Fault) matches(method, uri string) bool {
+	if f.Method != "" && f.Method != method {
+		return false
+	}
+	if prefix := strings.TrimSuffix(f.URI, "*"); prefix != f.URI {
+		return strings.HasPrefix(uri, prefix)
+	}
+	return f.URI == uri
+}
+
+// takeFault returns the fault applying to a request, if any, counting the request against it
+func (s *Server) takeFault(method, uri string) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, f := range s.faults {
+		if !f.matches(method, uri) {
+			continue
+		}
+		if f.Count > 0 {
+			f.Count--
+			if f.Count == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+// serveFault serves a request the way fault f says. The resources are not locked while the response is delayed, so
+// other requests are served in the meantime.
+func (s *Server) serveFault(w http.ResponseWriter, r *http.Request, uri string, f *Fault) {
+	if f.Latency > 0 {
+		select {
+		case <-time.After(f.Latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if f.DropConnection {
+		hijacker, ok := w.(http.Hijacker)
+		if !ok {
+			panic("emulator: the connection cannot be dropped")
+		}
+		conn, _, err := hijacker.Hijack()
+		if err == nil {
+			conn.Close()
+		}
+		return
+	}
+
+	if f.StatusCode != 0 {
+		messageID, message := f.MessageID, f.Message
+		if messageID == "" {
+			messageID = "Base.1.12.GeneralError"
+		}
+		if message == "" {
+			message = http.StatusText(f.StatusCode)
+		}
+		writeError(w, f.StatusCode, messageID, message)
+		return
+	}
+
+	jobState := completedState
+	if f.JobState != "" {
+		jobState = f.JobState
+	}
+	if !f.MalformedJSON && !f.NoLocation {
+		s.serve(w, r, uri, jobState)
+		return
+	}
+
+	recorder := httptest.NewRecorder()
+	s.serve(recorder, r, uri, jobState)
+	for k, v := range recorder.Header() {
+		w.Header()[k] = v
+	}
+	if f.NoLocation {
+		w.Header().Del("Location")
+	}
+	body := recorder.Body.Bytes()
+	if f.MalformedJSON {
+		body = body[:len(body)/2]
+	}
+	w.WriteHeader(recorder.Code)
+	_, _ = w.Write(body)
+}
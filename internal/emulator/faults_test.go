@@ -0,0 +1,125 @@
+package emulator
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stmcginnis/gofish/common"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+func TestFaultStatusCode(t *testing.T) {
+	server, api := connect(t)
+	server.Inject(NotReady(systemURI, 2))
+
+	for i := 0; i < 2; i++ {
+		_, err := redfish.GetComputerSystem(api, systemURI)
+		e, ok := err.(*common.Error)
+		if !ok {
+			t.Fatalf("got error %v, want a redfish error", err)
+		}
+		if e.HTTPReturnedStatusCode != http.StatusServiceUnavailable || !strings.Contains(e.Error(), NotReadyMessage) {
+			t.Errorf("got error %d %s, want the iDRAC not ready one", e.HTTPReturnedStatusCode, e)
+		}
+	}
+	if _, err := redfish.GetComputerSystem(api, systemURI); err != nil {
+		t.Errorf("the fault applied to more requests than its count: %s", err)
+	}
+}
+
+func TestFaultMalformedJSON(t *testing.T) {
+	server, api := connect(t)
+	server.Inject(Fault{Method: http.MethodGet, URI: systemURI, MalformedJSON: true})
+
+	if _, err := redfish.GetComputerSystem(api, systemURI); err == nil {
+		t.Error("reading a malformed system succeeded")
+	}
+	server.ClearFaults()
+	if _, err := redfish.GetComputerSystem(api, systemURI); err != nil {
+		t.Errorf("reading the system once the faults are cleared failed: %s", err)
+	}
+}
+
+func TestFaultLatency(t *testing.T) {
+	server, api := connect(t)
+	server.Inject(Fault{URI: systemURI, Latency: 200 * time.Millisecond})
+
+	start := time.Now()
+	if _, err := redfish.GetComputerSystem(api, systemURI); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("the response came after %s, want at least 200ms", elapsed)
+	}
+
+	// Other resources are served in the meantime
+	client := server.Client()
+	client.Timeout = 50 * time.Millisecond
+	req, _ := http.NewRequest(http.MethodGet, server.URL+systemURI, nil)
+	req.SetBasicAuth(Username, Password)
+	if _, err := client.Do(req); err == nil {
+		t.Error("a delayed request did not time out")
+	}
+	if _, err := redfish.GetStorage(api, storageURI); err != nil {
+		t.Errorf("reading another resource during a delayed request failed: %s", err)
+	}
+}
+
+func TestFaultDropConnection(t *testing.T) {
+	server, api := connect(t)
+	server.Inject(Fault{URI: systemURI, DropConnection: true})
+
+	if _, err := redfish.GetComputerSystem(api, systemURI); err == nil {
+		t.Error("reading the system succeeded while connections are dropped")
+	}
+}
+
+func TestFaultNoLocation(t *testing.T) {
+	server, api := connect(t)
+	server.Inject(Fault{Method: http.MethodPatch, URI: systemURI + "/Bios/Settings", NoLocation: true})
+
+	res, err := api.Patch(systemURI+"/Bios/Settings", map[string]interface{}{
+		"Attributes": map[string]interface{}{"NumLock": "Off"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusAccepted || res.Header.Get("Location") != "" {
+		t.Errorf("got status %d and location %q, want 202 without location", res.StatusCode, res.Header.Get("Location"))
+	}
+}
+
+func TestFaultJobState(t *testing.T) {
+	for _, state := range []string{JobException, JobKilled} {
+		t.Run(state, func(t *testing.T) {
+			server, api := connect(t)
+			server.Inject(Fault{Method: http.MethodPost, URI: storageURI + "/Volumes", JobState: state})
+
+			res, err := api.Post(storageURI+"/Volumes", map[string]interface{}{
+				"VolumeType": "NonRedundant",
+				"Name":       "MyVol",
+				"Drives":     []map[string]string{{"@odata.id": drive0URI}},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			res.Body.Close()
+
+			var task *redfish.Task
+			for i := 0; i <= runningPolls; i++ {
+				if task, err = redfish.GetTask(api, res.Header.Get("Location")); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if task.TaskState != redfish.TaskState(state) {
+				t.Errorf("the job ended in state %s, want %s", task.TaskState, state)
+			}
+			if count := server.Resource(storageURI + "/Volumes")["Members@odata.count"]; count != float64(0) {
+				t.Errorf("the failed job created %v volumes", count)
+			}
+		})
+	}
+}
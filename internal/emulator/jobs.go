@@ -0,0 +1,208 @@
+package emulator
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+)
+
+const (
+	tasksURI    = "/redfish/v1/TaskService/Tasks"
+	dellJobsURI = "/redfish/v1/Managers/iDRAC.Embedded.1/Jobs"
+
+	biosJobType = "BIOSConfiguration"
+	raidJobType = "RAIDConfiguration"
+
+	// runningPolls is the number of times a running job is read before it completes
+	runningPolls = 1
+)
+
+// Job states, named after the TaskState of redfish tasks
+const (
+	scheduledState = "Scheduled"
+	runningState   = "Running"
+	completedState = "Completed"
+	exceptionState = "Exception"
+	killedState    = "Killed"
+)
+
+// job is a configuration job. It is shown both as a redfish task and as a Dell job, at the same ID.
+// Scheduled jobs wait for the system to be reset, running ones end after being read runningPolls times and
+// their changes are applied to the resources when they complete.
+type job struct {
+	id   string
+	name string
+	// jobType is the JobType of the Dell job, such as BIOSConfiguration
+	jobType string
+	state   string
+	// endState is the state the job ends in. Jobs failing because of an injected fault end in another state than
+	// Completed.
+	endState string
+	polls    int
+	apply    func()
+}
+
+func (j *job) taskURI() string {
+	return tasksURI + "/" + j.id
+}
+
+func (j *job) dellJobURI() string {
+	return dellJobsURI + "/" + j.id
+}
+
+// newJob creates a job of the given type applying the given changes and returns the @odata.id of its task. A
+// scheduled job only starts when the system is reset.
+func (s *Server) newJob(name string, jobType string, scheduled bool, apply func()) string {
+	s.sequence++
+	j := &job{
+		id:       fmt.Sprintf("JID_%012d", s.sequence),
+		name:     name,
+		jobType:  jobType,
+		state:    runningState,
+		endState: s.jobState,
+		polls:    runningPolls,
+		apply:    apply,
+	}
+	if scheduled {
+		j.state = scheduledState
+	}
+	s.jobs[j.taskURI()] = j
+	s.jobs[j.dellJobURI()] = j
+	s.render(j)
+	s.addMember(tasksURI, j.taskURI())
+	s.addMember(dellJobsURI, j.dellJobURI())
+	return j.taskURI()
+}
+
+// poll makes a running job progress, as it is being read
+func (s *Server) poll(j *job) {
+	if j.state != runningState {
+		return
+	}
+	if j.polls > 0 {
+		j.polls--
+	} else {
+		if j.endState == completedState {
+			j.apply()
+		}
+		j.state = j.endState
+	}
+	s.render(j)
+}
+
+// scheduledJob tells whether a job of the given type waits for the system to be reset
+func (s *Server) scheduledJob(jobType string) bool {
+	for _, j := range s.jobs {
+		if j.jobType == jobType && j.state == scheduledState {
+			return true
+		}
+	}
+	return false
+}
+
+// startScheduledJobs starts the jobs waiting for the system to be reset, and applies the other changes waiting for it
+func (s *Server) startScheduledJobs() {
+	for _, apply := range s.resetChanges {
+		apply()
+	}
+	s.resetChanges = nil
+	for _, j := range s.jobs {
+		if j.state == scheduledState {
+			j.state = runningState
+			s.render(j)
+		}
+	}
+}
+
+// render updates the task and the Dell job showing the state of j
+func (s *Server) render(j *job) {
+	messageID, text, percent, status, dellState := "IDRAC.2.8.JCP001", "Task successfully scheduled.", 0, "OK", j.state
+	switch j.state {
+	case runningState:
+		messageID, text, percent = "IDRAC.2.8.PR20", "Job in progress.", 50
+	case completedState:
+		messageID, text, percent = "IDRAC.2.8.PR19", "Job completed successfully.", 100
+	case exceptionState:
+		messageID, text, percent, status, dellState = "IDRAC.2.8.SUP0518", "Unable to complete the job.", 100, "Critical", "Failed"
+	case killedState:
+		messageID, text, percent, status, dellState = "IDRAC.2.8.SUP0516", "The job was cancelled.", 100, "Warning", "Failed"
+	}
+
+	s.resources[j.taskURI()] = map[string]interface{}{
+		"@odata.id":       j.taskURI(),
+		"@odata.type":     "#Task.v1_6_0.Task",
+		"Description":     "Server Configuration and other Tasks running on iDRAC are listed here",
+		"Id":              j.id,
+		"Messages":        []interface{}{map[string]interface{}{"Message": text, "MessageArgs": []interface{}{}, "MessageId": messageID}},
+		"Name":            j.name,
+		"PercentComplete": percent,
+		"TaskState":       j.state,
+		"TaskStatus":      status,
+	}
+	s.resources[j.dellJobURI()] = map[string]interface{}{
+		"@odata.id":       j.dellJobURI(),
+		"@odata.type":     "#DellJob.v1_5_0.DellJob",
+		"Description":     "Job Instance",
+		"Id":              j.id,
+		"JobState":        dellState,
+		"JobType":         j.jobType,
+		"Message":         text,
+		"MessageArgs":     []interface{}{},
+		"MessageId":       messageID,
+		"Name":            j.name,
+		"PercentComplete": percent,
+	}
+}
+
+// deleteJob deletes a job from the job queue of the iDRAC, whatever its state
+func (s *Server) deleteJob(w http.ResponseWriter, uri string) {
+	j := s.jobs[uri]
+	for _, u := range []string{j.taskURI(), j.dellJobURI()} {
+		delete(s.jobs, u)
+		delete(s.resources, u)
+	}
+	s.removeMember(tasksURI, j.taskURI())
+	s.removeMember(dellJobsURI, j.dellJobURI())
+	writeSuccess(w, http.StatusOK)
+}
+
+// createJob creates a job of the job queue of the iDRAC applying the changes pending in the bios settings given as
+// TargetSettingsURI, which are the bios passwords. Changes of the attributes get their job when they are staged.
+// The job waits for the system to be reset.
+func (s *Server) createJob(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		TargetSettingsURI string
+	}
+	if !decode(w, r, &body) {
+		return
+	}
+	target := body.TargetSettingsURI
+	if _, ok := s.resources[target]; !ok || !strings.HasSuffix(target, "/Bios/Settings") {
+		writeError(w, http.StatusBadRequest, "Base.1.12.PropertyValueNotInList",
+			fmt.Sprintf("The value %s for the property TargetSettingsURI is not in the list of acceptable values.", target),
+			"#/TargetSettingsURI")
+		return
+	}
+	if s.scheduledJob(biosJobType) {
+		writeError(w, http.StatusBadRequest, "IDRAC.2.8.SYS011",
+			"Pending configuration values are already committed, unable to perform another set operation.")
+		return
+	}
+	biosURI := path.Dir(target)
+	pending := false
+	for name := range s.pendingPasswords {
+		pending = pending || path.Dir(name) == biosURI
+	}
+	if !pending {
+		writeError(w, http.StatusBadRequest, "IDRAC.2.8.SYS030",
+			"No pending data present to create a Configuration job.")
+		return
+	}
+
+	taskURI := s.newJob("Configure: BIOS.Setup.1-1", biosJobType, true, func() {
+		s.applyBiosPasswords(biosURI)
+	})
+	w.Header().Set("Location", dellJobsURI+"/"+path.Base(taskURI))
+	writeSuccess(w, http.StatusOK)
+}
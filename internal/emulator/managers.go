@@ -0,0 +1,42 @@
+package emulator
+
+import (
+	"net/http"
+	"strings"
+)
+
+// patchDellAttributes changes Dell OEM attributes of the iDRAC, the system or the lifecycle controller. Changes are
+// applied immediately, passwords are never shown.
+func (s *Server) patchDellAttributes(w http.ResponseWriter, r *http.Request, uri string) {
+	var body struct {
+		Attributes map[string]interface{}
+	}
+	if !decode(w, r, &body) {
+		return
+	}
+	if len(body.Attributes) == 0 {
+		writeError(w, http.StatusBadRequest, "Base.1.12.PropertyMissing",
+			"The property Attributes is a required property and must be included in the request.", "#/Attributes")
+		return
+	}
+
+	current := s.resources[uri]["Attributes"].(map[string]interface{})
+	for name, value := range body.Attributes {
+		old, ok := current[name]
+		if !ok {
+			writePropertyUnknown(w, name, "#/Attributes/"+name)
+			return
+		}
+		if !sameType(old, value) {
+			writeTypeError(w, name, value, "#/Attributes/"+name)
+			return
+		}
+	}
+	for name, value := range body.Attributes {
+		if strings.HasSuffix(name, ".Password") {
+			continue
+		}
+		current[name] = value
+	}
+	writeSuccess(w, http.StatusOK)
+}
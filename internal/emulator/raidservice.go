@@ -0,0 +1,270 @@
+package emulator
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// raidServiceRequest is the body of the actions of the Dell RAID service, which name drives and volumes by their FQDD,
+// the ID of their resource
+type raidServiceRequest struct {
+	TargetFQDD       string
+	VirtualDiskArray []string
+	PDArray          []string
+	ApplyTime        string `json:"@Redfish.OperationApplyTime"`
+}
+
+// decodeRAIDServiceRequest reads the body of an action of the Dell RAID service, or writes the error and returns false
+func decodeRAIDServiceRequest(w http.ResponseWriter, r *http.Request, action string, body *raidServiceRequest) bool {
+	if !decode(w, r, body) {
+		return false
+	}
+	if body.ApplyTime == "" {
+		body.ApplyTime = "Immediate"
+	}
+	if body.ApplyTime != "Immediate" && body.ApplyTime != "OnReset" {
+		writeError(w, http.StatusBadRequest, "Base.1.12.ActionParameterValueNotInList",
+			fmt.Sprintf("The value %s for the parameter @Redfish.OperationApplyTime in the action %s is not in the list of acceptable values.", body.ApplyTime, action),
+			"#/@Redfish.OperationApplyTime")
+		return false
+	}
+	return true
+}
+
+// findDrive returns the URI of the drive with the FQDD given to a parameter of an action of the Dell RAID service,
+// or writes the error and returns false
+func (s *Server) findDrive(w http.ResponseWriter, action, parameter, fqdd string) (string, bool) {
+	uri := s.findStorageResource("Drives", fqdd)
+	if uri == "" {
+		writeError(w, http.StatusBadRequest, "Base.1.12.ActionParameterValueNotInList",
+			fmt.Sprintf("The value %s for the parameter %s in the action %s is not in the list of acceptable values.", fqdd, path.Base(parameter), action),
+			parameter)
+		return "", false
+	}
+	return uri, true
+}
+
+// findStorageResource returns the URI of the drive, volume or storage controller with the given ID, or an empty string if there is none
+func (s *Server) findStorageResource(collection, id string) string {
+	if id == "" {
+		return ""
+	}
+	for uri := range s.resources {
+		if strings.HasSuffix(uri, "/"+collection+"/"+id) && strings.Contains(uri, "/Storage/") {
+			return uri
+		}
+	}
+	return ""
+}
+
+// assignSpare runs the DellRaidService.AssignSpare action, which makes a drive a global hot spare, or a dedicated
+// one of the volumes in VirtualDiskArray
+func (s *Server) assignSpare(w http.ResponseWriter, r *http.Request) {
+	var body raidServiceRequest
+	if !decodeRAIDServiceRequest(w, r, "DellRaidService.AssignSpare", &body) {
+		return
+	}
+	driveURI, ok := s.findDrive(w, "DellRaidService.AssignSpare", "#/TargetFQDD", body.TargetFQDD)
+	if !ok {
+		return
+	}
+	drive := s.resources[driveURI]
+	if !unusedRAIDDrive(drive) {
+		writeError(w, http.StatusBadRequest, "Base.1.12.ResourceInUse",
+			fmt.Sprintf("The change to the requested resource failed because the resource %s is in use or in transition.", driveURI), "#/TargetFQDD")
+		return
+	}
+	storageURI := path.Dir(path.Dir(driveURI))
+	var volumes []string
+	for i, id := range body.VirtualDiskArray {
+		uri := s.findStorageResource("Volumes", id)
+		if uri == "" || path.Dir(path.Dir(uri)) != storageURI {
+			writeError(w, http.StatusBadRequest, "Base.1.12.ActionParameterValueNotInList",
+				fmt.Sprintf("The value %s for the parameter VirtualDiskArray in the action DellRaidService.AssignSpare is not in the list of acceptable values.", id),
+				fmt.Sprintf("#/VirtualDiskArray/%d", i))
+			return
+		}
+		volumes = append(volumes, uri)
+	}
+
+	jobURI := s.newJob("Configure: "+path.Base(storageURI), raidJobType, body.ApplyTime == "OnReset", func() {
+		drive["HotspareType"] = "Global"
+		if len(volumes) != 0 {
+			drive["HotspareType"] = "Dedicated"
+		}
+		for _, uri := range volumes {
+			links := s.resources[uri]["Links"].(map[string]interface{})
+			spares, _ := links["DedicatedSpareDrives"].([]interface{})
+			links["DedicatedSpareDrives"] = append(spares, map[string]interface{}{"@odata.id": driveURI})
+			links["DedicatedSpareDrives@odata.count"] = len(spares) + 1
+		}
+	})
+	w.Header().Set("Location", jobURI)
+	writeSuccess(w, http.StatusAccepted)
+}
+
+// unassignSpare runs the DellRaidService.UnassignSpare action, which makes a hot spare an unused drive again
+func (s *Server) unassignSpare(w http.ResponseWriter, r *http.Request) {
+	var body raidServiceRequest
+	if !decodeRAIDServiceRequest(w, r, "DellRaidService.UnassignSpare", &body) {
+		return
+	}
+	driveURI, ok := s.findDrive(w, "DellRaidService.UnassignSpare", "#/TargetFQDD", body.TargetFQDD)
+	if !ok {
+		return
+	}
+	drive := s.resources[driveURI]
+	if drive["HotspareType"] == "None" {
+		writeError(w, http.StatusBadRequest, "Base.1.12.ActionParameterValueConflict",
+			fmt.Sprintf("The value %s for the parameter TargetFQDD in the action DellRaidService.UnassignSpare conflicts with the drive, which is not a hot spare.", body.TargetFQDD),
+			"#/TargetFQDD")
+		return
+	}
+
+	storageURI := path.Dir(path.Dir(driveURI))
+	jobURI := s.newJob("Configure: "+path.Base(storageURI), raidJobType, body.ApplyTime == "OnReset", func() {
+		s.removeDedicatedSpare(storageURI, driveURI)
+		drive["HotspareType"] = "None"
+	})
+	w.Header().Set("Location", jobURI)
+	writeSuccess(w, http.StatusAccepted)
+}
+
+// removeDedicatedSpare removes a drive from the dedicated hot spares of the volumes of a storage
+func (s *Server) removeDedicatedSpare(storageURI, driveURI string) {
+	for uri, volume := range s.resources {
+		if path.Dir(uri) != storageURI+"/Volumes" {
+			continue
+		}
+		links, _ := volume["Links"].(map[string]interface{})
+		if spares, ok := links["DedicatedSpareDrives"].([]interface{}); ok {
+			links["DedicatedSpareDrives"] = removeLink(spares, driveURI)
+			links["DedicatedSpareDrives@odata.count"] = len(links["DedicatedSpareDrives"].([]interface{}))
+		}
+	}
+}
+
+// dedicatedSpare tells whether a drive is a dedicated hot spare of a volume of a storage
+func (s *Server) dedicatedSpare(storageURI, driveURI string) bool {
+	for uri, volume := range s.resources {
+		if path.Dir(uri) != storageURI+"/Volumes" {
+			continue
+		}
+		links, _ := volume["Links"].(map[string]interface{})
+		if hasLink(links["DedicatedSpareDrives"], driveURI) {
+			return true
+		}
+	}
+	return false
+}
+
+// raidStatus returns the Dell RAID status of a drive, such as Ready or Non-RAID
+func raidStatus(drive map[string]interface{}) string {
+	oem, _ := drive["Oem"].(map[string]interface{})
+	dell, _ := oem["Dell"].(map[string]interface{})
+	disk, _ := dell["DellPhysicalDisk"].(map[string]interface{})
+	status, _ := disk["RaidStatus"].(string)
+	return status
+}
+
+// unusedRAIDDrive tells whether a drive can be part of a new volume or a hot spare: it is RAID capable, in no volume
+// and not a hot spare
+func unusedRAIDDrive(drive map[string]interface{}) bool {
+	driveLinks := drive["Links"].(map[string]interface{})
+	return len(driveLinks["Volumes"].([]interface{})) == 0 && drive["HotspareType"] == "None" && raidStatus(drive) != "Non-RAID"
+}
+
+// convertDrives runs the DellRaidService.ConvertToRAID and DellRaidService.ConvertToNonRAID actions, which turn
+// non-RAID drives into RAID capable ones and unused RAID capable drives into non-RAID ones
+func (s *Server) convertDrives(w http.ResponseWriter, r *http.Request, toRAID bool) {
+	action, from, to := "DellRaidService.ConvertToNonRAID", "Ready", "Non-RAID"
+	if toRAID {
+		action, from, to = "DellRaidService.ConvertToRAID", "Non-RAID", "Ready"
+	}
+	var body raidServiceRequest
+	if !decodeRAIDServiceRequest(w, r, action, &body) {
+		return
+	}
+	if len(body.PDArray) == 0 {
+		writeError(w, http.StatusBadRequest, "Base.1.12.ActionParameterMissing",
+			fmt.Sprintf("The action %s requires the parameter PDArray to be present in the request body.", action), "#/PDArray")
+		return
+	}
+	var drives []string
+	for i, fqdd := range body.PDArray {
+		uri, ok := s.findDrive(w, action, fmt.Sprintf("#/PDArray/%d", i), fqdd)
+		if !ok {
+			return
+		}
+		drive := s.resources[uri]
+		if raidStatus(drive) != from || (!toRAID && !unusedRAIDDrive(drive)) {
+			writeError(w, http.StatusBadRequest, "Base.1.12.ResourceInUse",
+				fmt.Sprintf("The change to the requested resource failed because the resource %s is in use or in transition.", uri),
+				fmt.Sprintf("#/PDArray/%d", i))
+			return
+		}
+		drives = append(drives, uri)
+	}
+
+	jobURI := s.newJob("Configure: "+path.Base(path.Dir(path.Dir(drives[0]))), raidJobType, body.ApplyTime == "OnReset", func() {
+		for _, uri := range drives {
+			merge(s.resources[uri], map[string]interface{}{
+				"Oem": map[string]interface{}{"Dell": map[string]interface{}{"DellPhysicalDisk": map[string]interface{}{"RaidStatus": to}}},
+			})
+		}
+	})
+	w.Header().Set("Location", jobURI)
+	writeSuccess(w, http.StatusAccepted)
+}
+
+// resetConfig runs the DellRaidService.ResetConfig action, which deletes every volume of a storage controller and
+// unassigns its hot spares. The controller settings are kept.
+func (s *Server) resetConfig(w http.ResponseWriter, r *http.Request) {
+	storageURI, body, ok := s.decodeControllerRequest(w, r, "DellRaidService.ResetConfig")
+	if !ok {
+		return
+	}
+	jobURI := s.newJob("Configure: "+path.Base(storageURI), raidJobType, body.ApplyTime == "OnReset", func() {
+		members, _ := s.resources[storageURI+"/Volumes"]["Members"].([]interface{})
+		for _, m := range append([]interface{}{}, members...) {
+			s.removeVolume(m.(map[string]interface{})["@odata.id"].(string))
+		}
+		drives, _ := s.resources[storageURI]["Drives"].([]interface{})
+		for _, l := range drives {
+			s.resources[l.(map[string]interface{})["@odata.id"].(string)]["HotspareType"] = "None"
+		}
+	})
+	w.Header().Set("Location", jobURI)
+	writeSuccess(w, http.StatusAccepted)
+}
+
+// clearForeignConfig runs the DellRaidService.ClearForeignConfig action. The emulated drives never come from another
+// controller, so the job has no foreign configuration to clear.
+func (s *Server) clearForeignConfig(w http.ResponseWriter, r *http.Request) {
+	storageURI, body, ok := s.decodeControllerRequest(w, r, "DellRaidService.ClearForeignConfig")
+	if !ok {
+		return
+	}
+	jobURI := s.newJob("Configure: "+path.Base(storageURI), raidJobType, body.ApplyTime == "OnReset", func() {})
+	w.Header().Set("Location", jobURI)
+	writeSuccess(w, http.StatusAccepted)
+}
+
+// decodeControllerRequest reads the body of an action of the Dell RAID service targeting a storage controller and
+// returns the URI of the controller, or writes the error and returns false
+func (s *Server) decodeControllerRequest(w http.ResponseWriter, r *http.Request, action string) (string, raidServiceRequest, bool) {
+	var body raidServiceRequest
+	if !decodeRAIDServiceRequest(w, r, action, &body) {
+		return "", body, false
+	}
+	uri := s.findStorageResource("Storage", body.TargetFQDD)
+	if uri == "" {
+		writeError(w, http.StatusBadRequest, "Base.1.12.ActionParameterValueNotInList",
+			fmt.Sprintf("The value %s for the parameter TargetFQDD in the action %s is not in the list of acceptable values.", body.TargetFQDD, action),
+			"#/TargetFQDD")
+		return "", body, false
+	}
+	return uri, body, true
+}
@@ -0,0 +1,243 @@
+package emulator
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// secureBootDatabases are the databases holding the keys of secure boot, the platform key being the only one of PK
+var secureBootDatabases = []string{"PK", "KEK", "db", "dbx"}
+
+// resetKeys runs the SecureBoot.ResetKeys action, which changes the keys of the secure boot databases at the next
+// reset of the system. Without a platform key the system is in setup mode, where secure boot is not enforced.
+// The emulated system has no default keys, so ResetAllKeysToDefault keeps the enrolled ones.
+func (s *Server) resetKeys(w http.ResponseWriter, r *http.Request, uri string) {
+	secureBoot, ok := s.resources[uri]
+	if !ok {
+		writeNotFound(w, uri)
+		return
+	}
+	var body struct {
+		ResetKeysType string
+	}
+	if !decode(w, r, &body) {
+		return
+	}
+	action := secureBoot["Actions"].(map[string]interface{})["#SecureBoot.ResetKeys"].(map[string]interface{})
+	if !contains(action["ResetKeysType@Redfish.AllowableValues"], body.ResetKeysType) {
+		writeError(w, http.StatusBadRequest, "Base.1.12.ActionParameterValueNotInList",
+			fmt.Sprintf("The value %s for the parameter ResetKeysType in the action SecureBoot.ResetKeys is not in the list of acceptable values.", body.ResetKeysType),
+			"#/ResetKeysType")
+		return
+	}
+	s.resetChanges = append(s.resetChanges, func() {
+		switch body.ResetKeysType {
+		case "ResetAllKeysToDefault":
+			secureBoot["SecureBootMode"] = "DeployedMode"
+		case "DeleteAllKeys":
+			for _, id := range secureBootDatabases {
+				s.clearCertificates(uri + "/SecureBootDatabases/" + id + "/Certificates")
+			}
+			secureBoot["SecureBootMode"] = "SetupMode"
+		case "DeletePK":
+			s.clearCertificates(uri + "/SecureBootDatabases/PK/Certificates")
+			secureBoot["SecureBootMode"] = "SetupMode"
+		}
+		setSecureBootCurrentBoot(secureBoot)
+	})
+	writeSuccess(w, http.StatusOK)
+}
+
+// setSecureBootCurrentBoot sets whether the system booted with secure boot, which is enforced once enabled unless
+// the system is in setup or audit mode
+func setSecureBootCurrentBoot(secureBoot map[string]interface{}) {
+	enforced := secureBoot["SecureBootEnable"] == true &&
+		(secureBoot["SecureBootMode"] == "UserMode" || secureBoot["SecureBootMode"] == "DeployedMode")
+	secureBoot["SecureBootCurrentBoot"] = "Disabled"
+	if enforced {
+		secureBoot["SecureBootCurrentBoot"] = "Enabled"
+	}
+}
+
+// isSecureBootCertificates tells whether uri is the certificate collection of a secure boot database
+func isSecureBootCertificates(uri string) bool {
+	return path.Base(uri) == "Certificates" && path.Base(path.Dir(path.Dir(uri))) == "SecureBootDatabases"
+}
+
+// addCertificate enrolls a PEM certificate in a secure boot database. Enrolling the platform key leaves setup mode.
+func (s *Server) addCertificate(w http.ResponseWriter, r *http.Request, collectionURI string) {
+	var body struct {
+		CertificateString  string
+		CertificateType    string
+		UefiSignatureOwner string
+	}
+	if !decode(w, r, &body) {
+		return
+	}
+	if body.CertificateString == "" || body.CertificateType == "" {
+		writeError(w, http.StatusBadRequest, "Base.1.12.PropertyMissing",
+			"The properties CertificateString and CertificateType are required properties and must be included in the request.")
+		return
+	}
+	if !contains(s.resources[collectionURI]["@Redfish.SupportedCertificates"], body.CertificateType) {
+		writeError(w, http.StatusBadRequest, "Base.1.12.PropertyValueNotInList",
+			fmt.Sprintf("The value %s for the property CertificateType is not in the list of acceptable values.", body.CertificateType),
+			"#/CertificateType")
+		return
+	}
+	certificate, err := certificateProperties(body.CertificateString)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Base.1.12.PropertyValueFormatError",
+			fmt.Sprintf("The value for the property CertificateString is of a different format than the property can accept: %s.", err),
+			"#/CertificateString")
+		return
+	}
+
+	databaseURI := path.Dir(collectionURI)
+	members, _ := s.resources[collectionURI]["Members"].([]interface{})
+	if path.Base(databaseURI) == "PK" && len(members) != 0 {
+		writeError(w, http.StatusBadRequest, "Base.1.12.CreateLimitReachedForResource",
+			"The create operation failed because the resource has reached the limit of possible resources.")
+		return
+	}
+	for _, m := range members {
+		link, _ := m.(map[string]interface{})
+		uri, _ := link["@odata.id"].(string)
+		if s.resources[uri]["Fingerprint"] == certificate["Fingerprint"] {
+			writeError(w, http.StatusBadRequest, "Base.1.12.ResourceAlreadyExists",
+				fmt.Sprintf("The requested resource already exists at %s.", uri), "#/CertificateString")
+			return
+		}
+	}
+
+	var id, uri string
+	for id == "" || s.resources[uri] != nil {
+		s.sequence++
+		id = fmt.Sprintf("SecureBoot.Cert.%d", s.sequence)
+		uri = collectionURI + "/" + id
+	}
+	merge(certificate, map[string]interface{}{
+		"@odata.id":         uri,
+		"@odata.type":       "#Certificate.v1_7_0.Certificate",
+		"CertificateString": body.CertificateString,
+		"CertificateType":   body.CertificateType,
+		"Id":                id,
+		"Name":              "Secure Boot Certificate",
+	})
+	if body.UefiSignatureOwner != "" {
+		certificate["UefiSignatureOwner"] = body.UefiSignatureOwner
+	}
+	s.resources[uri] = certificate
+	s.addMember(collectionURI, uri)
+
+	if path.Base(databaseURI) == "PK" {
+		secureBoot := s.resources[path.Dir(path.Dir(databaseURI))]
+		if secureBoot["SecureBootMode"] == "SetupMode" {
+			secureBoot["SecureBootMode"] = "UserMode"
+		}
+	}
+
+	w.Header().Set("Location", uri)
+	writeJSON(w, http.StatusCreated, certificate)
+}
+
+// deleteCertificate removes a certificate from a secure boot database. Deleting the platform key enters setup mode.
+func (s *Server) deleteCertificate(w http.ResponseWriter, uri string) {
+	collectionURI := path.Dir(uri)
+	s.removeMember(collectionURI, uri)
+	delete(s.resources, uri)
+	if databaseURI := path.Dir(collectionURI); path.Base(databaseURI) == "PK" {
+		s.resources[path.Dir(path.Dir(databaseURI))]["SecureBootMode"] = "SetupMode"
+	}
+	writeSuccess(w, http.StatusOK)
+}
+
+// clearCertificates removes every certificate of a collection
+func (s *Server) clearCertificates(collectionURI string) {
+	members, _ := s.resources[collectionURI]["Members"].([]interface{})
+	for _, m := range members {
+		link, _ := m.(map[string]interface{})
+		uri, _ := link["@odata.id"].(string)
+		delete(s.resources, uri)
+	}
+	s.resources[collectionURI]["Members"] = []interface{}{}
+	s.resources[collectionURI]["Members@odata.count"] = 0
+}
+
+// certificateProperties returns the properties a redfish certificate shows for a PEM certificate
+func certificateProperties(certificateString string) (map[string]interface{}, error) {
+	block, _ := pem.Decode([]byte(certificateString))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("no PEM encoded certificate found")
+	}
+	c, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	fingerprint := sha256.Sum256(c.Raw)
+	return map[string]interface{}{
+		"Fingerprint":              colonHex(fingerprint[:]),
+		"FingerprintHashAlgorithm": "TPM_ALG_SHA256",
+		"Issuer":                   certificateIdentifier(c.Issuer.CommonName, c.Issuer.Organization, c.Issuer.Country),
+		"SerialNumber":             colonHex(c.SerialNumber.Bytes()),
+		"SignatureAlgorithm":       c.SignatureAlgorithm.String(),
+		"Subject":                  certificateIdentifier(c.Subject.CommonName, c.Subject.Organization, c.Subject.Country),
+		"ValidNotAfter":            c.NotAfter.UTC().Format(time.RFC3339),
+		"ValidNotBefore":           c.NotBefore.UTC().Format(time.RFC3339),
+	}, nil
+}
+
+func certificateIdentifier(commonName string, organization, country []string) map[string]interface{} {
+	identifier := map[string]interface{}{"CommonName": commonName}
+	if len(organization) != 0 {
+		identifier["Organization"] = organization[0]
+	}
+	if len(country) != 0 {
+		identifier["Country"] = country[0]
+	}
+	return identifier
+}
+
+// colonHex writes b the way certificates show fingerprints and serial numbers, like 4F:0A:...
+func colonHex(b []byte) string {
+	parts := make([]string, len(b))
+	for i, v := range b {
+		parts[i] = fmt.Sprintf("%02X", v)
+	}
+	return strings.Join(parts, ":")
+}
+
+// NewCertificate returns a self-signed PEM certificate with the given common name, for tests to enroll in the secure
+// boot databases
+func NewCertificate(commonName string) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 64))
+	if err != nil {
+		panic(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName, Organization: []string{"Example Corp"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().AddDate(1, 0, 0),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		panic(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
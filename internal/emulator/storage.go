@@ -0,0 +1,324 @@
+package emulator
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// raidTypes describes the RAID types of volumes: the volume type they are shown as, the number of drives they need at
+// least and the number of spans they are made of by default
+var raidTypes = map[string]struct {
+	volumeType string
+	minDrives  int
+	spans      int
+}{
+	"RAID0":  {"NonRedundant", 1, 1},
+	"RAID1":  {"Mirrored", 2, 1},
+	"RAID5":  {"StripedWithParity", 3, 1},
+	"RAID6":  {"StripedWithParity", 4, 1},
+	"RAID10": {"SpannedMirrors", 4, 2},
+	"RAID50": {"SpannedStripesWithParity", 6, 2},
+	"RAID60": {"SpannedStripesWithParity", 8, 2},
+}
+
+// volumeTypeRAIDTypes are the RAID types volumes created with a volume type have
+var volumeTypeRAIDTypes = map[string]string{
+	"NonRedundant":             "RAID0",
+	"Mirrored":                 "RAID1",
+	"StripedWithParity":        "RAID5",
+	"SpannedMirrors":           "RAID10",
+	"SpannedStripesWithParity": "RAID50",
+}
+
+// createVolume creates the job making a volume out of unused drives of a storage controller
+func (s *Server) createVolume(w http.ResponseWriter, r *http.Request, collectionURI string) {
+	storageURI := strings.TrimSuffix(collectionURI, "/Volumes")
+	storage, ok := s.resources[storageURI]
+	if !ok {
+		s.notAllowed(w, r, collectionURI)
+		return
+	}
+	var body map[string]interface{}
+	if !decode(w, r, &body) {
+		return
+	}
+
+	supported := s.resources[collectionURI]["@Redfish.OperationApplyTimeSupport"].(map[string]interface{})["SupportedValues"]
+	applyTime := "Immediate"
+	if v, ok := body["@Redfish.OperationApplyTime"]; ok {
+		applyTime, _ = v.(string)
+		if !contains(supported, applyTime) {
+			writeError(w, http.StatusBadRequest, "Base.1.12.PropertyValueNotInList",
+				fmt.Sprintf("The value %v for the property @Redfish.OperationApplyTime is not in the list of acceptable values.", v),
+				"#/@Redfish.OperationApplyTime")
+			return
+		}
+	}
+	if name, _ := body["Name"].(string); name == "" {
+		writeError(w, http.StatusBadRequest, "Base.1.12.PropertyMissing",
+			"The property Name is a required property and must be included in the request.", "#/Name")
+		return
+	}
+	// The sizes are left out for the iDRAC to choose them, a volume of 0 bytes is refused
+	for _, property := range []string{"CapacityBytes", "OptimumIOSizeBytes"} {
+		if v, ok := body[property]; ok {
+			if size, _ := v.(float64); size <= 0 {
+				writeError(w, http.StatusBadRequest, "Base.1.12.PropertyValueOutOfRange",
+					fmt.Sprintf("The value %v for the property %s is not in the supported range of acceptable values.", v, property),
+					"#/"+property)
+				return
+			}
+		}
+	}
+	// The RAID type replaces the volume type in newer versions of the schema
+	raidType, _ := body["RAIDType"].(string)
+	if v, ok := body["VolumeType"]; ok {
+		volumeType, _ := v.(string)
+		if raidType, ok = volumeTypeRAIDTypes[volumeType]; !ok {
+			writeError(w, http.StatusBadRequest, "Base.1.12.PropertyValueNotInList",
+				fmt.Sprintf("The value %v for the property VolumeType is not in the list of acceptable values.", v),
+				"#/VolumeType")
+			return
+		}
+	}
+	if !contains(storage["StorageControllers"].([]interface{})[0].(map[string]interface{})["SupportedRAIDTypes"], raidType) {
+		writeError(w, http.StatusBadRequest, "Base.1.12.PropertyValueNotInList",
+			fmt.Sprintf("The value %v for the property RAIDType is not in the list of acceptable values.", body["RAIDType"]),
+			"#/RAIDType")
+		return
+	}
+
+	links, _ := body["Drives"].([]interface{})
+	var drives []string
+	for i, l := range links {
+		link, _ := l.(map[string]interface{})
+		uri, _ := link["@odata.id"].(string)
+		pointer := fmt.Sprintf("#/Drives/%d", i)
+		drive, ok := s.resources[uri]
+		if !ok || !hasLink(storage["Drives"], uri) {
+			writeError(w, http.StatusBadRequest, "Base.1.12.ResourceMissingAtURI",
+				fmt.Sprintf("The resource at the URI %s was not found.", uri), pointer)
+			return
+		}
+		if !unusedRAIDDrive(drive) {
+			writeError(w, http.StatusBadRequest, "Base.1.12.ResourceInUse",
+				fmt.Sprintf("The change to the requested resource failed because the resource %s is in use or in transition.", uri), pointer)
+			return
+		}
+		drives = append(drives, uri)
+	}
+	if len(drives) < raidTypes[raidType].minDrives {
+		writeError(w, http.StatusBadRequest, "IDRAC.2.8.STOR016",
+			fmt.Sprintf("The number of physical disks is not sufficient for a %s volume.", raidType), "#/Drives")
+		return
+	}
+	spanDepth, spanLength := volumeSpans(body, raidType, len(drives))
+	if spanDepth*spanLength != len(drives) || (raidTypes[raidType].spans == 1 && spanDepth != 1) {
+		writeError(w, http.StatusBadRequest, "IDRAC.2.8.STOR022",
+			fmt.Sprintf("The span depth %d and span length %d are not valid for a %s volume of %d physical disks.", spanDepth, spanLength, raidType, len(drives)),
+			"#/Oem/Dell/DellVolume")
+		return
+	}
+
+	s.sequence++
+	id := fmt.Sprintf("Disk.Virtual.%d:%s", s.sequence, path.Base(storageURI))
+	jobURI := s.newJob("Configure: "+path.Base(storageURI), raidJobType, applyTime == "OnReset", func() {
+		s.addVolume(collectionURI, id, body, raidType, drives)
+	})
+	w.Header().Set("Location", jobURI)
+	writeSuccess(w, http.StatusAccepted)
+}
+
+// volumeSpans returns the span depth and span length of a new volume, the number of spans and the number of drives in
+// each of them, which Dell OEM properties set
+func volumeSpans(body map[string]interface{}, raidType string, drives int) (int, int) {
+	var dell map[string]interface{}
+	if oem, ok := body["Oem"].(map[string]interface{}); ok {
+		if d, ok := oem["Dell"].(map[string]interface{}); ok {
+			dell, _ = d["DellVolume"].(map[string]interface{})
+		}
+	}
+	spanDepth := raidTypes[raidType].spans
+	if v, ok := dell["SpanDepth"].(float64); ok && v >= 1 {
+		spanDepth = int(v)
+	}
+	spanLength := drives / spanDepth
+	if v, ok := dell["SpanLength"].(float64); ok {
+		spanLength = int(v)
+	}
+	return spanDepth, spanLength
+}
+
+// addVolume adds a volume made of drives to the volume collection
+func (s *Server) addVolume(collectionURI, id string, body map[string]interface{}, raidType string, drives []string) {
+	uri := collectionURI + "/" + id
+	volume := map[string]interface{}{}
+	for k, v := range body {
+		if !strings.HasPrefix(k, "@") && k != "Drives" {
+			volume[k] = v
+		}
+	}
+	spanDepth, spanLength := volumeSpans(body, raidType, len(drives))
+	volume["RAIDType"] = raidType
+	volume["VolumeType"] = raidTypes[raidType].volumeType
+	for _, property := range []string{"StripSizeBytes", "OptimumIOSizeBytes"} {
+		if _, ok := volume[property]; !ok {
+			volume[property] = 65536
+		}
+	}
+	merge(volume, map[string]interface{}{
+		"Oem": map[string]interface{}{"Dell": map[string]interface{}{"DellVolume": map[string]interface{}{
+			"SpanDepth":  spanDepth,
+			"SpanLength": spanLength,
+		}}},
+	})
+	links := []interface{}{}
+	var capacity float64
+	for _, d := range drives {
+		links = append(links, map[string]interface{}{"@odata.id": d})
+		drive := s.resources[d]
+		if c, ok := drive["CapacityBytes"].(float64); ok && (capacity == 0 || c < capacity) {
+			capacity = c
+		}
+		driveLinks := drive["Links"].(map[string]interface{})
+		driveLinks["Volumes"] = []interface{}{map[string]interface{}{"@odata.id": uri}}
+		driveLinks["Volumes@odata.count"] = 1
+	}
+	// Like an iDRAC, the requested capacity is rounded down to whole stripes across the drives holding data
+	usable := usableDrives(raidType, len(drives), spanDepth)
+	if requested, ok := volume["CapacityBytes"].(float64); ok {
+		stripe := 65536 * usable
+		if size, ok := volume["StripSizeBytes"].(float64); ok {
+			stripe = size * usable
+		}
+		volume["CapacityBytes"] = math.Floor(requested/stripe) * stripe
+	} else {
+		volume["CapacityBytes"] = capacity * usable
+	}
+	merge(volume, map[string]interface{}{
+		"@odata.id":   uri,
+		"@odata.type": "#Volume.v1_8_0.Volume",
+		"@Redfish.Settings": map[string]interface{}{
+			"SettingsObject":      map[string]interface{}{"@odata.id": uri + "/Settings"},
+			"SupportedApplyTimes": []interface{}{"Immediate", "OnReset"},
+		},
+		"Id":    id,
+		"Links": map[string]interface{}{"Drives": links, "Drives@odata.count": len(links)},
+		"Status": map[string]interface{}{
+			"Health": "OK",
+			"State":  "Enabled",
+		},
+	})
+	s.resources[uri] = volume
+	s.resources[uri+"/Settings"] = map[string]interface{}{
+		"@odata.id":   uri + "/Settings",
+		"@odata.type": "#Volume.v1_8_0.Volume",
+		"Id":          id,
+	}
+	s.addMember(collectionURI, uri)
+}
+
+// usableDrives returns how many of the drives of a volume hold data, the others holding mirrors or parity
+func usableDrives(raidType string, drives int, spans int) float64 {
+	switch raidType {
+	case "RAID1", "RAID10":
+		return float64(drives / 2)
+	case "RAID5", "RAID50":
+		return float64(drives - spans)
+	case "RAID6", "RAID60":
+		return float64(drives - 2*spans)
+	}
+	return float64(drives)
+}
+
+// deleteVolume creates the job deleting a volume, which frees its drives
+func (s *Server) deleteVolume(w http.ResponseWriter, uri string) {
+	jobURI := s.newJob("Configure: "+path.Base(path.Dir(path.Dir(uri))), raidJobType, false, func() {
+		s.removeVolume(uri)
+	})
+	w.Header().Set("Location", jobURI)
+	writeSuccess(w, http.StatusAccepted)
+}
+
+// removeVolume deletes a volume, freeing its drives and unassigning the hot spares dedicated to it alone
+func (s *Server) removeVolume(uri string) {
+	collectionURI := path.Dir(uri)
+	volume, ok := s.resources[uri]
+	if !ok {
+		// Another job deleted it first
+		return
+	}
+	links := volume["Links"].(map[string]interface{})["Drives"].([]interface{})
+	for _, l := range links {
+		driveLinks := s.resources[l.(map[string]interface{})["@odata.id"].(string)]["Links"].(map[string]interface{})
+		driveLinks["Volumes"] = removeLink(driveLinks["Volumes"].([]interface{}), uri)
+		driveLinks["Volumes@odata.count"] = len(driveLinks["Volumes"].([]interface{}))
+	}
+	s.removeMember(collectionURI, uri)
+	delete(s.resources, uri)
+	delete(s.resources, uri+"/Settings")
+	// The dedicated hot spares of the volume alone are no longer spares
+	spares, _ := volume["Links"].(map[string]interface{})["DedicatedSpareDrives"].([]interface{})
+	for _, l := range spares {
+		driveURI := l.(map[string]interface{})["@odata.id"].(string)
+		if !s.dedicatedSpare(path.Dir(collectionURI), driveURI) {
+			s.resources[driveURI]["HotspareType"] = "None"
+		}
+	}
+}
+
+// patchDrive changes the hot spare type of a drive the standard way, which BMCs without the Dell RAID service use.
+// Unlike the DellRaidService.UnassignSpare action, it leaves the links of the volumes to their dedicated spares.
+func (s *Server) patchDrive(w http.ResponseWriter, r *http.Request, uri string) {
+	var body map[string]interface{}
+	if !decode(w, r, &body) {
+		return
+	}
+	for name, value := range body {
+		if name != "HotspareType" {
+			writePropertyUnknown(w, name, "#/"+name)
+			return
+		}
+		if value != "None" && value != "Global" && value != "Dedicated" {
+			writeTypeError(w, name, value, "#/"+name)
+			return
+		}
+	}
+	merge(s.resources[uri], body)
+	writeSuccess(w, http.StatusOK)
+}
+
+// patchVolume changes the dedicated hot spares of a volume the standard way, through its DedicatedSpareDrives links
+func (s *Server) patchVolume(w http.ResponseWriter, r *http.Request, uri string) {
+	var body struct {
+		Links map[string][]map[string]string
+	}
+	if !decode(w, r, &body) {
+		return
+	}
+	links := s.resources[uri]["Links"].(map[string]interface{})
+	for name, spares := range body.Links {
+		if name != "DedicatedSpareDrives" {
+			writePropertyUnknown(w, name, "#/Links/"+name)
+			return
+		}
+		for i, spare := range spares {
+			if _, ok := s.resources[spare["@odata.id"]]; !ok {
+				writeError(w, http.StatusBadRequest, "Base.1.12.ResourceNotFound",
+					fmt.Sprintf("The requested resource of type Drive named %s was not found.", spare["@odata.id"]),
+					fmt.Sprintf("#/Links/DedicatedSpareDrives/%d", i))
+				return
+			}
+		}
+		value := make([]interface{}, 0, len(spares))
+		for _, spare := range spares {
+			value = append(value, map[string]interface{}{"@odata.id": spare["@odata.id"]})
+		}
+		links["DedicatedSpareDrives"] = value
+		links["DedicatedSpareDrives@odata.count"] = len(value)
+	}
+	writeSuccess(w, http.StatusOK)
+}
@@ -0,0 +1,462 @@
+package emulator
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// resetSystem runs the ComputerSystem.Reset action. Power changes are immediate, and resetting or powering on the
+// system boots it.
+func (s *Server) resetSystem(w http.ResponseWriter, r *http.Request, uri string) {
+	system, ok := s.resources[uri]
+	if !ok {
+		writeNotFound(w, uri)
+		return
+	}
+	var body struct {
+		ResetType string
+	}
+	if !decode(w, r, &body) {
+		return
+	}
+	if body.ResetType == "" {
+		writeError(w, http.StatusBadRequest, "Base.1.12.ActionParameterMissing",
+			"The action ComputerSystem.Reset requires the parameter ResetType to be present in the request body.", "#/ResetType")
+		return
+	}
+	reset := system["Actions"].(map[string]interface{})["#ComputerSystem.Reset"].(map[string]interface{})
+	if !contains(reset["ResetType@Redfish.AllowableValues"], body.ResetType) {
+		writeError(w, http.StatusBadRequest, "Base.1.12.ActionParameterValueNotInList",
+			fmt.Sprintf("The value %s for the parameter ResetType in the action ComputerSystem.Reset is not in the list of acceptable values.", body.ResetType),
+			"#/ResetType")
+		return
+	}
+
+	state := system["PowerState"]
+	switch body.ResetType {
+	case "On", "ForceOn":
+		if state == "On" {
+			writeConflict(w)
+			return
+		}
+		system["PowerState"] = "On"
+		s.boot(system)
+	case "ForceOff", "GracefulShutdown":
+		if state == "Off" {
+			writeConflict(w)
+			return
+		}
+		system["PowerState"] = "Off"
+	case "PushPowerButton":
+		if state == "On" {
+			system["PowerState"] = "Off"
+		} else {
+			system["PowerState"] = "On"
+			s.boot(system)
+		}
+	case "ForceRestart", "GracefulRestart", "PowerCycle":
+		system["PowerState"] = "On"
+		s.boot(system)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// boot starts the jobs waiting for the system to be reset, and uses up a boot source override enabled once
+func (s *Server) boot(system map[string]interface{}) {
+	s.startScheduledJobs()
+	if boot, _ := system["Boot"].(map[string]interface{}); boot["BootSourceOverrideEnabled"] == "Once" {
+		boot["BootSourceOverrideEnabled"] = "Disabled"
+		boot["BootSourceOverrideTarget"] = "None"
+	}
+}
+
+// patchSystem changes the boot settings of a system, the only ones which can be written. The boot order must list
+// every boot option of the system, and the other settings take one of their allowable values.
+func (s *Server) patchSystem(w http.ResponseWriter, r *http.Request, uri string) {
+	system := s.resources[uri]
+	var body map[string]interface{}
+	if !decode(w, r, &body) {
+		return
+	}
+	for k := range body {
+		if k != "Boot" {
+			writeError(w, http.StatusBadRequest, "Base.1.12.PropertyNotWritable",
+				fmt.Sprintf("The property %s is a read only property and cannot be assigned a value.", k), "#/"+k)
+			return
+		}
+	}
+	changes, ok := body["Boot"].(map[string]interface{})
+	if !ok {
+		writeTypeError(w, "Boot", body["Boot"], "#/Boot")
+		return
+	}
+
+	boot := system["Boot"].(map[string]interface{})
+	for k, v := range changes {
+		pointer := "#/Boot/" + k
+		switch k {
+		case "BootOrder":
+			if !samePermutation(boot["BootOrder"], v) {
+				writeError(w, http.StatusBadRequest, "Base.1.12.PropertyValueIncorrect",
+					fmt.Sprintf("The value %v for the property BootOrder is incorrect: it must list every boot option once.", v), pointer)
+				return
+			}
+		case "BootSourceOverrideEnabled", "BootSourceOverrideTarget", "BootSourceOverrideMode":
+			value, _ := v.(string)
+			if !contains(boot[k+"@Redfish.AllowableValues"], value) {
+				writeError(w, http.StatusBadRequest, "Base.1.12.PropertyValueNotInList",
+					fmt.Sprintf("The value %v for the property %s is not in the list of acceptable values.", v, k), pointer)
+				return
+			}
+		case "UefiTargetBootSourceOverride":
+			if _, ok := v.(string); !ok {
+				writeTypeError(w, k, v, pointer)
+				return
+			}
+		default:
+			writePropertyUnknown(w, k, pointer)
+			return
+		}
+	}
+	merge(boot, changes)
+	writeSuccess(w, http.StatusOK)
+}
+
+// samePermutation tells whether value is a list of the strings of current, in any order
+func samePermutation(current, value interface{}) bool {
+	c, _ := current.([]interface{})
+	v, ok := value.([]interface{})
+	if !ok || len(c) != len(v) {
+		return false
+	}
+	seen := map[interface{}]bool{}
+	for _, e := range v {
+		if seen[e] || !contains(c, fmt.Sprint(e)) {
+			return false
+		}
+		seen[e] = true
+	}
+	return true
+}
+
+func writeConflict(w http.ResponseWriter) {
+	writeError(w, http.StatusConflict, "IDRAC.2.8.PSU501", "Unable to perform the operation because the server is already in the requested power state.")
+}
+
+// insertMedia runs the VirtualMedia.InsertMedia action
+func (s *Server) insertMedia(w http.ResponseWriter, r *http.Request, uri string) {
+	media, ok := s.resources[uri]
+	if !ok {
+		writeNotFound(w, uri)
+		return
+	}
+	var body struct {
+		Image                string
+		Inserted             *bool
+		WriteProtected       *bool
+		TransferMethod       string
+		TransferProtocolType string
+	}
+	if !decode(w, r, &body) {
+		return
+	}
+	if media["Inserted"] == true {
+		writeError(w, http.StatusBadRequest, "IDRAC.2.8.VRM0012",
+			"The Virtual Media image server is already connected.")
+		return
+	}
+	if body.Image == "" {
+		writeError(w, http.StatusBadRequest, "Base.1.12.ActionParameterMissing",
+			"The action VirtualMedia.InsertMedia requires the parameter Image to be present in the request body.", "#/Image")
+		return
+	}
+	image, err := url.Parse(body.Image)
+	if err != nil || image.Scheme == "" {
+		writeError(w, http.StatusBadRequest, "IDRAC.2.8.VRM0021",
+			"Unable to locate the ISO or IMG image file or folder in the network share location because the file or folder path or the user credentials entered are incorrect.", "#/Image")
+		return
+	}
+	if ext := path.Ext(image.Path); ext != ".iso" && ext != ".img" {
+		writeError(w, http.StatusBadRequest, "IDRAC.2.8.VRM0009",
+			"Unable to mount the virtual media because the image file is not supported.", "#/Image")
+		return
+	}
+
+	media["Image"] = body.Image
+	media["ImageName"] = path.Base(image.Path)
+	media["ConnectedVia"] = "URI"
+	media["Inserted"] = true
+	if body.Inserted != nil {
+		media["Inserted"] = *body.Inserted
+	}
+	media["WriteProtected"] = true
+	if body.WriteProtected != nil {
+		media["WriteProtected"] = *body.WriteProtected
+	}
+	media["TransferMethod"] = "Stream"
+	if body.TransferMethod != "" {
+		media["TransferMethod"] = body.TransferMethod
+	}
+	media["TransferProtocolType"] = strings.ToUpper(image.Scheme)
+	if body.TransferProtocolType != "" {
+		media["TransferProtocolType"] = body.TransferProtocolType
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ejectMedia runs the VirtualMedia.EjectMedia action
+func (s *Server) ejectMedia(w http.ResponseWriter, uri string) {
+	media, ok := s.resources[uri]
+	if !ok {
+		writeNotFound(w, uri)
+		return
+	}
+	if media["Inserted"] != true {
+		writeError(w, http.StatusBadRequest, "IDRAC.2.8.VRM0009",
+			"No Virtual Media devices are currently connected.")
+		return
+	}
+	media["Image"] = nil
+	media["ImageName"] = nil
+	media["ConnectedVia"] = "NotConnected"
+	media["Inserted"] = false
+	media["WriteProtected"] = true
+	media["TransferMethod"] = "Stream"
+	media["TransferProtocolType"] = nil
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// patchSettings stages changes in the settings resource of a resource, like the one of the bios or of a volume,
+// and creates the job applying them. The job waits for the system to be reset unless the changes are applied
+// immediately.
+func (s *Server) patchSettings(w http.ResponseWriter, r *http.Request, uri string) {
+	parentURI := strings.TrimSuffix(uri, "/Settings")
+	parent := s.resources[parentURI]
+	settingsInfo, _ := parent["@Redfish.Settings"].(map[string]interface{})
+	settingsObject, _ := settingsInfo["SettingsObject"].(map[string]interface{})
+	if parent == nil || settingsObject["@odata.id"] != uri {
+		s.notAllowed(w, r, uri)
+		return
+	}
+
+	var body map[string]interface{}
+	if !decode(w, r, &body) {
+		return
+	}
+
+	supported := settingsInfo["SupportedApplyTimes"]
+	applyTime := "OnReset"
+	if contains(supported, "Immediate") {
+		applyTime = "Immediate"
+	}
+	if v, ok := body["@Redfish.SettingsApplyTime"]; ok {
+		settingsApplyTime, _ := v.(map[string]interface{})
+		a, _ := settingsApplyTime["ApplyTime"].(string)
+		if !contains(supported, a) {
+			writeError(w, http.StatusBadRequest, "Base.1.12.PropertyValueNotInList",
+				fmt.Sprintf("The value %v for the property ApplyTime is not in the list of acceptable values.", a),
+				"#/@Redfish.SettingsApplyTime/ApplyTime")
+			return
+		}
+		// Changes applied in a maintenance window wait for a reset like the ones applied on reset, the window
+		// never starting
+		if _, ok := settingsApplyTime["MaintenanceWindowStartTime"]; strings.Contains(a, "MaintenanceWindow") && !ok {
+			writeError(w, http.StatusBadRequest, "Base.1.12.PropertyMissing",
+				"The property MaintenanceWindowStartTime is a required property and must be included in the request.",
+				"#/@Redfish.SettingsApplyTime/MaintenanceWindowStartTime")
+			return
+		}
+		applyTime = a
+		delete(body, "@Redfish.SettingsApplyTime")
+	}
+	if len(body) == 0 {
+		writeError(w, http.StatusBadRequest, "Base.1.12.PropertyMissing",
+			"The request body does not contain any property to change.")
+		return
+	}
+
+	for k, v := range body {
+		if k != "Attributes" {
+			if !checkSettingsProperty(w, parent, k, v, "#/"+k) {
+				return
+			}
+			continue
+		}
+		attributes, _ := v.(map[string]interface{})
+		current, _ := parent["Attributes"].(map[string]interface{})
+		for name, value := range attributes {
+			old, ok := current[name]
+			if !ok {
+				writePropertyUnknown(w, name, "#/Attributes/"+name)
+				return
+			}
+			if !sameType(old, value) {
+				writeTypeError(w, name, value, "#/Attributes/"+name)
+				return
+			}
+		}
+	}
+
+	settings := s.resources[uri]
+	merge(settings, body)
+	// Secure boot settings are bios attributes on an iDRAC, applied by the same jobs
+	jobType := raidJobType
+	if base := path.Base(parentURI); base == "Bios" || base == "SecureBoot" {
+		jobType = biosJobType
+	}
+	jobURI := s.newJob("Configure: "+path.Base(parentURI), jobType, applyTime != "Immediate", func() {
+		merge(parent, body)
+		if jobType == biosJobType {
+			s.applyBiosPasswords(path.Join(path.Dir(parentURI), "Bios"))
+		}
+		if _, ok := parent["SecureBootCurrentBoot"]; ok {
+			setSecureBootCurrentBoot(parent)
+		}
+		staged, _ := settings["Attributes"].(map[string]interface{})
+		applied, _ := body["Attributes"].(map[string]interface{})
+		for name := range applied {
+			delete(staged, name)
+		}
+	})
+	w.Header().Set("Location", jobURI)
+	writeSuccess(w, http.StatusAccepted)
+}
+
+// resetBios runs the Bios.ResetBios action, which sets the attributes of the bios to the default values of its
+// registry at the next reset of the system
+func (s *Server) resetBios(w http.ResponseWriter, uri string) {
+	bios, ok := s.resources[uri]
+	if !ok {
+		writeNotFound(w, uri)
+		return
+	}
+	s.resetChanges = append(s.resetChanges, func() {
+		registry, _ := s.resources[uri+"/BiosRegistry"]["RegistryEntries"].(map[string]interface{})
+		entries, _ := registry["Attributes"].([]interface{})
+		attributes, _ := bios["Attributes"].(map[string]interface{})
+		for _, e := range entries {
+			entry, _ := e.(map[string]interface{})
+			name, _ := entry["AttributeName"].(string)
+			if _, ok := attributes[name]; ok {
+				attributes[name] = entry["DefaultValue"]
+			}
+		}
+	})
+	writeSuccess(w, http.StatusOK)
+}
+
+// changeBiosPassword runs the Bios.ChangePassword action. The password names are the Password attributes of the
+// registry of the bios, and a password is only changed given the current one, which is empty until one is set. The
+// new password is pending until a bios config job applies it.
+func (s *Server) changeBiosPassword(w http.ResponseWriter, r *http.Request, uri string) {
+	if _, ok := s.resources[uri]; !ok {
+		writeNotFound(w, uri)
+		return
+	}
+	var body struct {
+		PasswordName string
+		OldPassword  string
+		NewPassword  *string
+	}
+	if !decode(w, r, &body) {
+		return
+	}
+	if body.PasswordName == "" || body.NewPassword == nil {
+		writeError(w, http.StatusBadRequest, "Base.1.12.ActionParameterMissing",
+			"The action Bios.ChangePassword requires the parameters PasswordName and NewPassword to be present in the request body.")
+		return
+	}
+
+	registry, _ := s.resources[uri+"/BiosRegistry"]["RegistryEntries"].(map[string]interface{})
+	entries, _ := registry["Attributes"].([]interface{})
+	found := false
+	for _, e := range entries {
+		entry, _ := e.(map[string]interface{})
+		found = found || (entry["AttributeName"] == body.PasswordName && entry["Type"] == "Password")
+	}
+	if !found {
+		writeError(w, http.StatusBadRequest, "Base.1.12.ActionParameterValueNotInList",
+			fmt.Sprintf("The value %s for the parameter PasswordName in the action Bios.ChangePassword is not in the list of acceptable values.", body.PasswordName),
+			"#/PasswordName")
+		return
+	}
+	if s.passwords[uri+"/"+body.PasswordName] != body.OldPassword {
+		writeError(w, http.StatusBadRequest, "Base.1.12.ActionParameterValueError",
+			"The value for the parameter OldPassword in the action Bios.ChangePassword is invalid.", "#/OldPassword")
+		return
+	}
+	s.pendingPasswords[uri+"/"+body.PasswordName] = *body.NewPassword
+	writeSuccess(w, http.StatusOK)
+}
+
+// applyBiosPasswords sets the pending passwords of the bios at uri, as its config jobs do
+func (s *Server) applyBiosPasswords(uri string) {
+	for name, password := range s.pendingPasswords {
+		if path.Dir(name) == uri {
+			s.passwords[name] = password
+			delete(s.pendingPasswords, name)
+		}
+	}
+}
+
+// clearPending runs the DellManager.ClearPending action of the settings of the bios, which drops the changes staged
+// there and the pending passwords. Changes a scheduled job is to apply cannot be dropped, the job has to be deleted first.
+func (s *Server) clearPending(w http.ResponseWriter, uri string) {
+	settings, ok := s.resources[uri]
+	if !ok {
+		writeNotFound(w, uri)
+		return
+	}
+	if s.scheduledJob(biosJobType) {
+		writeError(w, http.StatusBadRequest, "IDRAC.2.8.SYS011",
+			"Pending configuration values are already committed, unable to perform another set operation.")
+		return
+	}
+	settings["Attributes"] = map[string]interface{}{}
+	for name := range s.pendingPasswords {
+		if path.Dir(name) == path.Dir(uri) {
+			delete(s.pendingPasswords, name)
+		}
+	}
+	writeSuccess(w, http.StatusOK)
+}
+
+// checkSettingsProperty checks that a property changed through a settings resource exists, keeps its type and takes
+// one of its allowable values, going through the properties of objects like Oem ones, or writes the error
+func checkSettingsProperty(w http.ResponseWriter, parent map[string]interface{}, k string, v interface{}, pointer string) bool {
+	current, ok := parent[k]
+	if !ok {
+		writePropertyUnknown(w, k, pointer)
+		return false
+	}
+	if !sameType(current, v) {
+		writeTypeError(w, k, v, pointer)
+		return false
+	}
+	if allowable, ok := parent[k+"@Redfish.AllowableValues"]; ok && !contains(allowable, fmt.Sprint(v)) {
+		writeError(w, http.StatusBadRequest, "Base.1.12.PropertyValueNotInList",
+			fmt.Sprintf("The value %v for the property %s is not in the list of acceptable values.", v, k), pointer)
+		return false
+	}
+	object, _ := current.(map[string]interface{})
+	properties, _ := v.(map[string]interface{})
+	for name, value := range properties {
+		if !checkSettingsProperty(w, object, name, value, pointer+"/"+name) {
+			return false
+		}
+	}
+	return true
+}
+
+func writePropertyUnknown(w http.ResponseWriter, name, pointer string) {
+	writeError(w, http.StatusBadRequest, "Base.1.12.PropertyUnknown",
+		fmt.Sprintf("The property %s is not in the list of valid properties for the resource.", name), pointer)
+}
+
+func writeTypeError(w http.ResponseWriter, name string, value interface{}, pointer string) {
+	writeError(w, http.StatusBadRequest, "Base.1.12.PropertyValueTypeError",
+		fmt.Sprintf("The value %v for the property %s is of a different type than the property can accept.", value, name), pointer)
+}
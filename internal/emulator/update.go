@@ -0,0 +1,138 @@
+package emulator
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+const (
+	firmwareInventoryURI = "/redfish/v1/UpdateService/FirmwareInventory"
+	simpleUpdateURI      = "/redfish/v1/UpdateService/Actions/UpdateService.SimpleUpdate"
+)
+
+// softwareIDs maps the component named first in Dell update package file names, like BIOS_FXC54_WN64_1.15.0.EXE,
+// to the software ID of the component in the firmware inventory
+var softwareIDs = map[string]string{
+	"BIOS":                            "159",
+	"iDRAC-with-Lifecycle-Controller": "25227",
+	"SAS-RAID":                        "101560",
+}
+
+// packageInfo returns the software ID and the version of an update package from its file name
+func packageInfo(name string) (softwareID, version string) {
+	name = strings.TrimSuffix(path.Base(name), path.Ext(name))
+	parts := strings.Split(name, "_")
+	softwareID, ok := softwareIDs[parts[0]]
+	if !ok {
+		softwareID = parts[0]
+	}
+	return softwareID, parts[len(parts)-1]
+}
+
+// uploadPackage stores an update package pushed to the firmware inventory, which must not have changed since
+// its ETag was read
+func (s *Server) uploadPackage(w http.ResponseWriter, r *http.Request) {
+	body, err := jsonBody(s.resources[firmwareInventoryURI])
+	if err == nil && r.Header.Get("If-Match") != etag(body) {
+		writeError(w, http.StatusPreconditionFailed, "Base.1.12.PreconditionFailed",
+			"The ETag supplied did not match the ETag required to change this resource.")
+		return
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Base.1.12.ActionParameterMissing",
+			"The request requires the parameter file to be present in the request body.", "#/file")
+		return
+	}
+	file.Close()
+
+	softwareID, version := packageInfo(header.Filename)
+	id := fmt.Sprintf("Available-%s-%s", softwareID, version)
+	uri := firmwareInventoryURI + "/" + id
+	if _, ok := s.resources[uri]; !ok {
+		s.resources[uri] = softwareInventory(id, header.Filename, softwareID, version)
+		s.addMember(firmwareInventoryURI, uri)
+	}
+	w.Header().Set("Location", uri)
+	writeSuccess(w, http.StatusCreated)
+}
+
+// simpleUpdate creates the job installing an update package, either one uploaded before or one to download. The
+// job waits for the system to be reset.
+func (s *Server) simpleUpdate(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		ImageURI         string
+		TransferProtocol string
+	}
+	if !decode(w, r, &body) {
+		return
+	}
+	if body.ImageURI == "" {
+		writeError(w, http.StatusBadRequest, "Base.1.12.ActionParameterMissing",
+			"The action UpdateService.SimpleUpdate requires the parameter ImageURI to be present in the request body.", "#/ImageURI")
+		return
+	}
+	action := s.resources["/redfish/v1/UpdateService"]["Actions"].(map[string]interface{})["#UpdateService.SimpleUpdate"].(map[string]interface{})
+	if body.TransferProtocol != "" && !contains(action["TransferProtocol@Redfish.AllowableValues"], body.TransferProtocol) {
+		writeError(w, http.StatusBadRequest, "Base.1.12.ActionParameterValueNotInList",
+			fmt.Sprintf("The value %s for the parameter TransferProtocol in the action UpdateService.SimpleUpdate is not in the list of acceptable values.", body.TransferProtocol),
+			"#/TransferProtocol")
+		return
+	}
+
+	available := strings.TrimSuffix(body.ImageURI, "/")
+	name := available
+	if _, ok := s.resources[available]; ok {
+		name, _ = s.resources[available]["Name"].(string)
+	} else if image, err := url.Parse(body.ImageURI); err != nil || image.Scheme == "" {
+		writeError(w, http.StatusBadRequest, "Base.1.12.ActionParameterValueFormatError",
+			fmt.Sprintf("The value %s for the parameter ImageURI in the action UpdateService.SimpleUpdate is of a different format than the parameter can accept.", body.ImageURI),
+			"#/ImageURI")
+		return
+	}
+	softwareID, version := packageInfo(name)
+
+	jobURI := s.newJob("Firmware Update: "+path.Base(name), "FirmwareUpdate", true, func() {
+		s.install(softwareID, version, name)
+		if _, ok := s.resources[available]; ok {
+			s.removeMember(firmwareInventoryURI, available)
+			delete(s.resources, available)
+		}
+	})
+	w.Header().Set("Location", jobURI)
+	writeSuccess(w, http.StatusAccepted)
+}
+
+// install replaces the installed firmware of a component with another version
+func (s *Server) install(softwareID, version, name string) {
+	for uri, resource := range s.resources {
+		if strings.HasPrefix(uri, firmwareInventoryURI+"/Installed-") && resource["SoftwareId"] == softwareID {
+			name, _ = resource["Name"].(string)
+			s.removeMember(firmwareInventoryURI, uri)
+			delete(s.resources, uri)
+		}
+	}
+	id := fmt.Sprintf("Installed-%s-%s", softwareID, version)
+	s.resources[firmwareInventoryURI+"/"+id] = softwareInventory(id, name, softwareID, version)
+	s.addMember(firmwareInventoryURI, firmwareInventoryURI+"/"+id)
+}
+
+func softwareInventory(id, name, softwareID, version string) map[string]interface{} {
+	return map[string]interface{}{
+		"@odata.id":   firmwareInventoryURI + "/" + id,
+		"@odata.type": "#SoftwareInventory.v1_5_0.SoftwareInventory",
+		"Description": "Represents Firmware Inventory",
+		"Id":          id,
+		"Name":        name,
+		"SoftwareId":  softwareID,
+		"Status": map[string]interface{}{
+			"Health": "OK",
+			"State":  "Enabled",
+		},
+		"Updateable": true,
+		"Version":    version,
+	}
+}
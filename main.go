@@ -46,6 +46,7 @@ func main() {
 
 	if debugMode {
 		err := plugin.Debug(context.Background(), "registry.terraform.io/dell/redfish", opts)
+		redfish.CloseSessions()
 		if err != nil {
 			log.Fatal(err.Error())
 		}
@@ -54,4 +55,7 @@ func main() {
 
 	plugin.Serve(opts)
 
+	// Terraform stops the plugin server once it is done with the provider, delete the redfish sessions left behind
+	redfish.CloseSessions()
+
 }
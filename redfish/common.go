@@ -1,19 +1,25 @@
 package redfish
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
+	"github.com/dell/terraform-provider-redfish/common"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/stmcginnis/gofish"
+	redfishcommon "github.com/stmcginnis/gofish/common"
 	"github.com/stmcginnis/gofish/redfish"
 )
 
-// Based on an instance of Service from the gofish library, retrieve a concrete system on which we can take action
-func getSystemResource(service *gofish.Service) (*redfish.ComputerSystem, error) {
+// Based on an instance of Service from the gofish library, retrieve a concrete system on which we can take action.
+// If systemID is empty, the first system of the collection is used
+func getSystemResource(service *gofish.Service, systemID string) (*redfish.ComputerSystem, error) {
 
 	systems, err := service.Systems()
 
@@ -23,79 +29,371 @@ func getSystemResource(service *gofish.Service) (*redfish.ComputerSystem, error)
 	if len(systems) == 0 {
 		return nil, errors.New("No computer systems found")
 	}
+	if systemID == "" {
+		return systems[0], nil
+	}
 
-	return systems[0], err
+	var ids []string
+	for _, system := range systems {
+		if system.ID == systemID {
+			return system, nil
+		}
+		ids = append(ids, system.ID)
+	}
+	return nil, fmt.Errorf("Could not find a ComputerSystem with ID %s. Available systems are: %s", systemID, strings.Join(ids, ", "))
 }
 
-// NewConfig function creates the needed gofish structs to query the redfish API
-// See https://github.com/stmcginnis/gofish for details. This function returns a Service struct which can then be
-// used to make any required API calls.
-func NewConfig(provider *schema.ResourceData, resource *schema.ResourceData) (*gofish.Service, error) {
-	//Get redfish connection details from resource block
-	var providerUser, providerPassword string
+// Based on an instance of Service from the gofish library, retrieve a concrete manager (i.e. the iDRAC) on which we
+// can take action. If managerID is empty, the first manager of the collection is used
+func getManagerResource(service *gofish.Service, managerID string) (*redfish.Manager, error) {
+
+	managers, err := service.Managers()
+
+	if err != nil {
+		return nil, err
+	}
+	if len(managers) == 0 {
+		return nil, errors.New("No managers found")
+	}
+	if managerID == "" {
+		return managers[0], nil
+	}
 
-	if v, ok := provider.GetOk("user"); ok {
-		providerUser = v.(string)
+	var ids []string
+	for _, manager := range managers {
+		if manager.ID == managerID {
+			return manager, nil
+		}
+		ids = append(ids, manager.ID)
 	}
-	if v, ok := provider.GetOk("password"); ok {
-		providerPassword = v.(string)
+	return nil, fmt.Errorf("Could not find a Manager with ID %s. Available managers are: %s", managerID, strings.Join(ids, ", "))
+}
+
+// getJSON decodes the redfish resource at uri into v, for properties gofish leaves out
+func getJSON(client redfishcommon.Client, uri string, v interface{}) error {
+	resp, err := client.Get(uri)
+	if err != nil {
+		return err
 	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(v)
+}
 
-	resourceServerConfig := resource.Get("redfish_server").([]interface{}) //It must be just one element
+// patchSettingsObject stages changes of the settings of a resource and returns the URI of the task of the job applying
+// them, if any. Resources with a settings object, like the secure boot and storage resources of iDRACs, stage them
+// there, the others take them on the resource itself.
+func patchSettingsObject(client redfishcommon.Client, resourceURI string, payload map[string]interface{}) (string, error) {
+	var settings struct {
+		Settings struct {
+			SettingsObject redfishcommon.Link
+		} `json:"@Redfish.Settings"`
+	}
+	if err := getJSON(client, resourceURI, &settings); err != nil {
+		return "", err
+	}
+	uri := settings.Settings.SettingsObject.String()
+	if uri == "" {
+		uri = resourceURI
+	}
 
-	//Overwrite parameters (just user and password for client connection)
-	//Get redfish username at resource level over provider level
-	var redfishClientUser, redfishClientPass string
-	if len(resourceServerConfig[0].(map[string]interface{})["user"].(string)) > 0 {
-		redfishClientUser = resourceServerConfig[0].(map[string]interface{})["user"].(string)
-		log.Println("Using redfish user from resource")
+	log.Printf("[DEBUG] Changing the settings %s to %v", uri, payload)
+	resp, err := client.Patch(uri, payload)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	if location, err := resp.Location(); err == nil {
+		return location.EscapedPath(), nil
+	}
+	return "", nil
+}
+
+// getDellJobQueue returns the URI of the job queue of the manager of a system, or an empty string if the manager has
+// none, as only the Dell iDRACs have one
+func getDellJobQueue(service *gofish.Service, systemID string) (string, error) {
+	system, err := getSystemResource(service, systemID)
+	if err != nil {
+		return "", err
+	}
+	if len(system.ManagedBy) == 0 {
+		return "", nil
+	}
+	var links struct {
+		Links struct {
+			Oem struct {
+				Dell struct {
+					Jobs redfishcommon.Link
+				}
+			}
+		}
+	}
+	if err := getJSON(service.GetClient(), system.ManagedBy[0], &links); err != nil {
+		return "", err
+	}
+	return links.Links.Oem.Dell.Jobs.String(), nil
+}
+
+// getDellJobTask returns the URI of the task following a job of the Dell job queue, the task having the ID of the job
+func getDellJobTask(service *gofish.Service, jobID string) (string, error) {
+	var root struct {
+		TaskService redfishcommon.Link
+	}
+	if err := getJSON(service.GetClient(), service.ODataID, &root); err != nil {
+		return "", err
+	}
+	var taskService struct {
+		Tasks redfishcommon.Link
+	}
+	if err := getJSON(service.GetClient(), root.TaskService.String(), &taskService); err != nil {
+		return "", err
+	}
+	if taskService.Tasks.String() == "" {
+		return "", errors.New("the service has no task collection")
+	}
+	return taskService.Tasks.String() + "/" + jobID, nil
+}
+
+// systemIDSchema returns the schema of the system_id attribute. Resources set forceNew, since changing it means
+// acting on another system. It is then computed too, so the system found when importing a resource is kept in state.
+func systemIDSchema(forceNew bool) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Computed:    forceNew,
+		ForceNew:    forceNew,
+		Description: "ID of the computer system to act on. If not provided, then the first system resource is used from the computer system collection",
+	}
+}
+
+// managerIDSchema returns the schema of the manager_id attribute. Resources set forceNew, since changing it means
+// acting on another manager. It is then computed too, so the manager found when importing a resource is kept in state.
+func managerIDSchema(forceNew bool) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Computed:    forceNew,
+		ForceNew:    forceNew,
+		Description: "ID of the manager to act on. If not provided, then the first manager resource is used from the manager collection",
+	}
+}
+
+// deprecatedTimeout returns the number of seconds set through a deprecated timeout attribute, such as reset_timeout,
+// which bounds a single step of an operation. It returns 0 when the attribute is not set, the step being then bounded
+// by the timeouts block of the resource only. The configuration is not available on delete, where a value other than
+// the default one is taken as set.
+func deprecatedTimeout(d *schema.ResourceData, key string, defaultValue int) int {
+	value := d.Get(key).(int)
+	if config := d.GetRawConfig(); !config.IsNull() && config.IsKnown() {
+		if config.GetAttr(key).IsNull() {
+			return 0
+		}
+		return value
+	}
+	if value == defaultValue {
+		return 0
+	}
+	return value
+}
+
+// redfishServerSchema returns the schema of the redfish_server block, used by resources and data sources to
+// declare the server they act on
+func redfishServerSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeList,
+		Optional:     true,
+		MaxItems:     1,
+		ExactlyOneOf: []string{"redfish_alias", "redfish_server"},
+		Description:  "List of server BMCs and their respective user credentials",
+		Elem: &schema.Resource{
+			Schema: redfishServerElemSchema(),
+		},
+	}
+}
+
+// redfishServerElemSchema returns the connection details of a server, shared by the redfish_server block
+// and the servers block of the provider
+func redfishServerElemSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"user": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "User name for login",
+		},
+		"password": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "User password for login",
+			Sensitive:   true,
+		},
+		"endpoint": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "Server BMC IP address or hostname",
+		},
+		"ssl_insecure": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "This field indicates whether the SSL/TLS certificate must be verified or not",
+		},
+		"ca_cert_file": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Path of a PEM bundle with the certificate authorities trusted to sign the BMC certificate",
+		},
+		"ca_cert_pem": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "PEM bundle with the certificate authorities trusted to sign the BMC certificate",
+		},
+		"client_cert": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "PEM encoded client certificate presented to the BMC for mutual TLS",
+		},
+		"client_key": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "PEM encoded private key of client_cert",
+			Sensitive:   true,
+		},
+		"cert_fingerprint_sha256": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Description: "SHA-256 fingerprint of the BMC certificate, in hexadecimal with or without colons. " +
+				"When no certificate authority is given, a certificate matching the fingerprint is trusted without verifying its chain",
+		},
+	}
+}
+
+// redfishAliasSchema returns the schema of the redfish_alias attribute, an alternative to the redfish_server
+// block which refers to a server declared in the provider servers block
+func redfishAliasSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ExactlyOneOf: []string{"redfish_alias", "redfish_server"},
+		Description:  "Alias of a server declared in the servers block of the provider",
+	}
+}
+
+// resourceConfig reads the configuration of a resource, either from its ResourceData or from its ResourceDiff during
+// the plan
+type resourceConfig interface {
+	Get(key string) interface{}
+	GetOk(key string) (interface{}, bool)
+}
+
+// getServerConfig returns the connection details of the server a resource acts on. The server is either the
+// one declared in the provider servers block under redfish_alias, or the one in the redfish_server block.
+// Credentials missing in both places are taken from the provider user and password.
+func getServerConfig(provider *schema.ResourceData, resource resourceConfig) (clientConfig, error) {
+	var config clientConfig
+	var serverConfig map[string]interface{}
+	var err error
+
+	if alias, ok := resource.GetOk("redfish_alias"); ok {
+		for _, v := range provider.Get("servers").([]interface{}) {
+			if server := v.(map[string]interface{}); server["alias"].(string) == alias.(string) {
+				serverConfig = server
+				break
+			}
+		}
+		if serverConfig == nil {
+			return config, fmt.Errorf("Error. Redfish alias %s has not been declared in the servers of the provider", alias.(string))
+		}
+		log.Printf("Using redfish server %s from provider\n", alias.(string))
 	} else {
-		redfishClientUser = providerUser
+		resourceServerConfig := resource.Get("redfish_server").([]interface{}) //It must be just one element
+		if len(resourceServerConfig) == 0 || resourceServerConfig[0] == nil {
+			return config, fmt.Errorf("Error. Either redfish_alias or redfish_server must be set. Please check your configuration")
+		}
+		serverConfig = resourceServerConfig[0].(map[string]interface{})
+	}
+
+	config.endpoint = serverConfig["endpoint"].(string)
+	if config.retry, err = getRetryPolicy(provider); err != nil {
+		return config, err
+	}
+	config.tls = common.TLSOptions{
+		Insecure:              serverConfig["ssl_insecure"].(bool),
+		CACertFile:            serverConfig["ca_cert_file"].(string),
+		CACertPEM:             serverConfig["ca_cert_pem"].(string),
+		ClientCert:            serverConfig["client_cert"].(string),
+		ClientKey:             serverConfig["client_key"].(string),
+		CertFingerprintSHA256: serverConfig["cert_fingerprint_sha256"].(string),
+	}
+
+	//Overwrite parameters (just user and password for client connection)
+	//Get redfish username at server level over provider level
+	if len(serverConfig["user"].(string)) > 0 {
+		config.user = serverConfig["user"].(string)
+		log.Println("Using redfish user from server")
+	} else if v, ok := provider.GetOk("user"); ok {
+		config.user = v.(string)
 		log.Println("Using redfish user from provider")
 	}
-	//Get redfish password at resource level over provider level
-	if len(resourceServerConfig[0].(map[string]interface{})["password"].(string)) > 0 {
-		redfishClientPass = resourceServerConfig[0].(map[string]interface{})["password"].(string)
-		log.Println("Using redfish password from resource")
-	} else {
-		redfishClientPass = providerPassword
+	//Get redfish password at server level over provider level
+	if len(serverConfig["password"].(string)) > 0 {
+		config.password = serverConfig["password"].(string)
+		log.Println("Using redfish password from server")
+	} else if v, ok := provider.GetOk("password"); ok {
+		config.password = v.(string)
 		log.Println("Using redfish password from provider")
 	}
+
+	return config, nil
+}
+
+// serverConfigKnown tells whether the connection details of the server a resource acts on are known during the plan,
+// which is not the case when they come from resources yet to be created
+func serverConfigKnown(diff *schema.ResourceDiff) bool {
+	for _, k := range []string{"redfish_alias", "redfish_server.0.endpoint", "redfish_server.0.user", "redfish_server.0.password"} {
+		if !diff.NewValueKnown(k) {
+			return false
+		}
+	}
+	return true
+}
+
+// NewConfig function creates the needed gofish structs to query the redfish API
+// See https://github.com/stmcginnis/gofish for details. This function returns a Service struct which can then be
+// used to make any required API calls. Connections are authenticated with a redfish session which is shared by
+// every resource targeting the same endpoint and user (see session.go).
+func NewConfig(provider *schema.ResourceData, resource resourceConfig) (*gofish.Service, error) {
+	config, err := getServerConfig(provider, resource)
+	if err != nil {
+		return nil, err
+	}
 	//If for some reason none user or pass has been set at provider/resource level, trow an error
-	if len(redfishClientUser) == 0 || len(redfishClientPass) == 0 {
+	if len(config.user) == 0 || len(config.password) == 0 {
 		return nil, fmt.Errorf("Error. Either Redfish client username or password has not been set. Please check your configuration")
 	}
 
-	clientConfig := gofish.ClientConfig{
-		Endpoint:  resourceServerConfig[0].(map[string]interface{})["endpoint"].(string),
-		Username:  redfishClientUser,
-		Password:  redfishClientPass,
-		BasicAuth: true,
-		Insecure:  resourceServerConfig[0].(map[string]interface{})["ssl_insecure"].(bool),
-	}
-	api, err := gofish.Connect(clientConfig)
+	api, err := getClient(config)
 	if err != nil {
 		return nil, fmt.Errorf("Error connecting to redfish API: %v", err)
 	}
-	log.Printf("Connection with the redfish endpoint %v was sucessful\n", resourceServerConfig[0].(map[string]interface{})["endpoint"].(string))
+	log.Printf("Connection with the redfish endpoint %v was sucessful\n", config.endpoint)
 	return api.Service, nil
 }
 
-// PowerOperation Executes a power operation against the target server. It takes four arguments. The first is the reset
-// type. See the struct "ResetType" at https://github.com/stmcginnis/gofish/blob/main/redfish/computersystem.go for all
-// possible options. The second is maximumWaitTime which is the maximum amount of time to wait for the server to reach
-// the expected power state before considering it a failure. The third is checkInterval which is how often to check the
-// server's power state for updates. The last is a pointer to a gofish.Service object with which the function can
-// interact with the server. It will return a tuple consisting of the server's power state at time of return and
-// diagnostics
-func PowerOperation(resetType string, maximumWaitTime int, checkInterval int, service *gofish.Service) (redfish.PowerState, diag.Diagnostics) {
+// PowerOperation Executes a power operation against the target server. It takes six arguments. The first is the context
+// of the operation, the wait for the expected power state stops with an error as soon as it is done. The second is the
+// reset type. See the struct "ResetType" at https://github.com/stmcginnis/gofish/blob/main/redfish/computersystem.go for
+// all possible options. The third is maximumWaitTime which is the maximum amount of time to wait for the server to reach
+// the expected power state before considering it a failure, 0 meaning waiting until the context is done. The fourth is
+// checkInterval which is how often to check the server's power state for updates. The fifth is a pointer to a
+// gofish.Service object with which the function can interact with the server. The last is the ID of the system to act
+// on, the first system is used when it is empty.
+// It will return a tuple consisting of the server's power state at time of return and diagnostics
+func PowerOperation(ctx context.Context, resetType string, maximumWaitTime int, checkInterval int, service *gofish.Service, systemID string) (redfish.PowerState, diag.Diagnostics) {
 
 	var diags diag.Diagnostics
 
-	system, err := getSystemResource(service)
+	system, err := getSystemResource(service, systemID)
 	if err != nil {
 		log.Printf("[ERROR]: Failed to identify system: %s", err)
-		return "", diag.Errorf(err.Error())
+		return "", redfishDiagnostics("Failed to identify system", err, nil)
 	}
 
 	var targetPowerState redfish.PowerState
@@ -139,21 +437,26 @@ func PowerOperation(resetType string, maximumWaitTime int, checkInterval int, se
 	log.Printf("[TRACE]: Performing system.Reset(%s)", resetType)
 	if err = system.Reset(redfish.ResetType(resetType)); err != nil {
 		log.Printf("[WARN]: system.Reset returned an error: %s", err)
-		return system.PowerState, diag.Errorf(err.Error())
+		return system.PowerState, redfishDiagnostics(fmt.Sprintf("there was an issue with the %s reset of the system", resetType), err, nil)
 	}
 
 	// Wait for the server to be in the correct power state
+	waitCtx := ctx
+	if maximumWaitTime > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, time.Duration(maximumWaitTime)*time.Second)
+		defer cancel()
+	}
 	totalTime := 0
-	for totalTime < maximumWaitTime {
+	for common.SleepWithContext(waitCtx, time.Duration(checkInterval)*time.Second) == nil {
 
-		time.Sleep(time.Duration(checkInterval) * time.Second)
 		totalTime += checkInterval
 		log.Printf("[TRACE]: Total time is %d seconds. Checking power state now.", totalTime)
 
-		system, err := getSystemResource(service)
+		system, err = getSystemResource(service, systemID)
 		if err != nil {
 			log.Printf("[ERROR]: Failed to identify system: %s", err)
-			return system.PowerState, diag.Errorf(err.Error())
+			return "", redfishDiagnostics("Failed to identify system", err, nil)
 		}
 
 		if system.PowerState == targetPowerState {
@@ -163,6 +466,11 @@ func PowerOperation(resetType string, maximumWaitTime int, checkInterval int, se
 
 	}
 
+	// The operation has been cancelled or has run out of the time given by the timeouts of the resource
+	if ctx.Err() != nil {
+		return system.PowerState, diag.Errorf("the system did not reach the %s power state: %s", targetPowerState, ctx.Err())
+	}
+
 	// If we've reached here it means the system never reached the appropriate target state
 	// We will instead set the power state to whatever the current state is and return
 	// TODO : Change to warning when updated to plugin framework
@@ -171,10 +479,21 @@ func PowerOperation(resetType string, maximumWaitTime int, checkInterval int, se
 
 }
 
-// getRedfishServerEndpoint returns the endpoint from an schema. This might be useful
+// getRedfishServerEndpoint returns the endpoint of the server a resource acts on. This might be useful
 // when using MutexKV, since we need a way to differentiate mutex operations
 // across servers
-func getRedfishServerEndpoint(resource *schema.ResourceData) string {
-	resourceServerConfig := resource.Get("redfish_server").([]interface{})
-	return resourceServerConfig[0].(map[string]interface{})["endpoint"].(string)
+func getRedfishServerEndpoint(provider *schema.ResourceData, resource *schema.ResourceData) string {
+	// Resources lock once connected, so NewConfig has already reported any configuration error
+	config, _ := getServerConfig(provider, resource)
+	return config.endpoint
+}
+
+// contains tells whether list holds value
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
 }
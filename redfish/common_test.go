@@ -0,0 +1,141 @@
+package redfish
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dell/terraform-provider-redfish/common"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestGetServerConfig(t *testing.T) {
+	provider := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"user":     "root",
+		"password": "calvin",
+		"servers": []interface{}{
+			map[string]interface{}{
+				"alias":        "server1",
+				"endpoint":     "https://server1",
+				"ssl_insecure": true,
+			},
+			map[string]interface{}{
+				"alias":                   "server2",
+				"cert_fingerprint_sha256": "AB:CD",
+				"endpoint":                "https://server2",
+				"user":                    "admin",
+				"password":                "passw0rd",
+			},
+		},
+	})
+	resourceSchema := getDataSourceRedfishBiosSchema()
+
+	tests := []struct {
+		name   string
+		raw    map[string]interface{}
+		want   clientConfig
+		errors bool
+	}{
+		{
+			name: "alias with provider credentials",
+			raw:  map[string]interface{}{"redfish_alias": "server1"},
+			want: clientConfig{endpoint: "https://server1", user: "root", password: "calvin", tls: common.TLSOptions{Insecure: true}, retry: common.DefaultRetryPolicy},
+		},
+		{
+			name: "alias with its own credentials",
+			raw:  map[string]interface{}{"redfish_alias": "server2"},
+			want: clientConfig{endpoint: "https://server2", user: "admin", password: "passw0rd", tls: common.TLSOptions{CertFingerprintSHA256: "AB:CD"}, retry: common.DefaultRetryPolicy},
+		},
+		{
+			name: "inline server",
+			raw: map[string]interface{}{
+				"redfish_server": []interface{}{
+					map[string]interface{}{"endpoint": "https://server3", "password": "secret", "ca_cert_file": "/etc/ssl/ca.pem"},
+				},
+			},
+			want: clientConfig{endpoint: "https://server3", user: "root", password: "secret", tls: common.TLSOptions{CACertFile: "/etc/ssl/ca.pem"}, retry: common.DefaultRetryPolicy},
+		},
+		{
+			name:   "unknown alias",
+			raw:    map[string]interface{}{"redfish_alias": "server4"},
+			errors: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getServerConfig(provider, schema.TestResourceDataRaw(t, resourceSchema, tt.raw))
+			if tt.errors {
+				if err == nil {
+					t.Errorf("expected an error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		raw    map[string]interface{}
+		want   common.RetryPolicy
+		errors bool
+	}{
+		{
+			name: "default",
+			raw:  map[string]interface{}{},
+			want: common.DefaultRetryPolicy,
+		},
+		{
+			name: "configured",
+			raw: map[string]interface{}{
+				"retry": []interface{}{
+					map[string]interface{}{"max_retries": 5, "min_backoff": "500ms", "max_backoff": "1m", "jitter": 0.5},
+				},
+			},
+			want: common.RetryPolicy{MaxRetries: 5, MinBackoff: 500 * time.Millisecond, MaxBackoff: time.Minute, Jitter: 0.5},
+		},
+		{
+			name: "defaults of the block",
+			raw: map[string]interface{}{
+				"retry": []interface{}{
+					map[string]interface{}{"max_retries": 0},
+				},
+			},
+			want: common.RetryPolicy{MaxBackoff: common.DefaultRetryPolicy.MaxBackoff, MinBackoff: common.DefaultRetryPolicy.MinBackoff, Jitter: common.DefaultRetryPolicy.Jitter},
+		},
+		{
+			name: "minimum backoff above the maximum",
+			raw: map[string]interface{}{
+				"retry": []interface{}{
+					map[string]interface{}{"min_backoff": "1m", "max_backoff": "10s"},
+				},
+			},
+			errors: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getRetryPolicy(schema.TestResourceDataRaw(t, Provider().Schema, tt.raw))
+			if tt.errors {
+				if err == nil {
+					t.Errorf("expected an error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -17,36 +17,9 @@ func dataSourceRedfishBios() *schema.Resource {
 
 func getDataSourceRedfishBiosSchema() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
-		"redfish_server": {
-			Type:        schema.TypeList,
-			Required:    true,
-			Description: "List of server BMCs and their respective user credentials",
-			Elem: &schema.Resource{
-				Schema: map[string]*schema.Schema{
-					"user": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Description: "User name for login",
-					},
-					"password": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Description: "User password for login",
-						Sensitive:   true,
-					},
-					"endpoint": {
-						Type:        schema.TypeString,
-						Required:    true,
-						Description: "Server BMC IP address or hostname",
-					},
-					"ssl_insecure": {
-						Type:        schema.TypeBool,
-						Optional:    true,
-						Description: "This field indicates whether the SSL/TLS certificate must be verified or not",
-					},
-				},
-			},
-		},
+		"redfish_alias":  redfishAliasSchema(),
+		"redfish_server": redfishServerSchema(),
+		"system_id":      systemIDSchema(false),
 		"odata_id": {
 			Type:        schema.TypeString,
 			Description: "OData ID for the Bios resource",
@@ -70,23 +43,23 @@ func getDataSourceRedfishBiosSchema() map[string]*schema.Schema {
 }
 
 func dataSourceRedfishBiosRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	service, err := NewConfig(m.(*schema.ResourceData), d)
+	service, err := NewConfig(m.(*providerMeta).config, d)
 	if err != nil {
 		return diag.Errorf(err.Error())
 	}
-	return readRedfishBios(service, d)
+	return readRedfishBios(service, d, m)
 }
 
-func readRedfishBios(service *gofish.Service, d *schema.ResourceData) diag.Diagnostics {
+func readRedfishBios(service *gofish.Service, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	systems, err := service.Systems()
+	system, err := getSystemResource(service, d.Get("system_id").(string))
 	if err != nil {
 		return diag.Errorf("error fetching computer systems collection: %s", err)
 
 	}
 
-	bios, err := systems[0].Bios()
+	bios, err := system.Bios()
 	if err != nil {
 		return diag.Errorf("error fetching bios: %s", err)
 	}
@@ -118,8 +91,7 @@ func readRedfishBios(service *gofish.Service, d *schema.ResourceData) diag.Diagn
 	}
 
 	// Set the ID to the redfish endpoint + bios @odata.id
-	serverConfig := d.Get("redfish_server").([]interface{})
-	endpoint := serverConfig[0].(map[string]interface{})["endpoint"].(string)
+	endpoint := getRedfishServerEndpoint(m.(*providerMeta).config, d)
 	biosResourceId := endpoint + bios.ODataID
 	d.SetId(biosResourceId)
 
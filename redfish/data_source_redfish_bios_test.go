@@ -3,6 +3,7 @@ package redfish
 import (
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"regexp"
 	"testing"
 )
 
@@ -19,6 +20,35 @@ func TestAccRedfishBiosDataSource_basic(t *testing.T) {
 	})
 }
 
+func TestAccRedfishBiosDataSource_alias(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishDataSourceBiosAliasConfig(creds),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.redfish_bios.bios", "redfish_alias", "server1"),
+					resource.TestCheckNoResourceAttr("data.redfish_bios.bios", "redfish_server.0.password"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRedfishBiosDataSource_invalidSystemID(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccRedfishDataSourceBiosSystemIDConfig(creds, "System.Embedded.99"),
+				ExpectError: regexp.MustCompile("Could not find a ComputerSystem with ID System.Embedded.99"),
+			},
+		},
+	})
+}
+
 func testAccRedfishDataSourceBiosConfig(testingInfo TestingServerCredentials) string {
 	return fmt.Sprintf(`
 		
@@ -37,3 +67,44 @@ func testAccRedfishDataSourceBiosConfig(testingInfo TestingServerCredentials) st
 		testingInfo.Endpoint,
 	)
 }
+
+func testAccRedfishDataSourceBiosAliasConfig(testingInfo TestingServerCredentials) string {
+	return fmt.Sprintf(`
+		provider "redfish" {
+		  servers {
+			alias = "server1"
+			user = "%s"
+			password = "%s"
+			endpoint = "https://%s"
+			ssl_insecure = true
+		  }
+		}
+
+		data "redfish_bios" "bios" {
+		  redfish_alias = "server1"
+		}
+		`,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+	)
+}
+
+func testAccRedfishDataSourceBiosSystemIDConfig(testingInfo TestingServerCredentials, systemID string) string {
+	return fmt.Sprintf(`
+		data "redfish_bios" "bios" {
+		  redfish_server {
+			user = "%s"
+			password = "%s"
+			endpoint = "https://%s"
+			ssl_insecure = true
+		  }
+		  system_id = "%s"
+		}
+		`,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+		systemID,
+	)
+}
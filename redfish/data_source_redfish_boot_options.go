@@ -0,0 +1,154 @@
+package redfish
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stmcginnis/gofish"
+	redfishcommon "github.com/stmcginnis/gofish/common"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+func dataSourceRedfishBootOptions() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceRedfishBootOptionsRead,
+		Schema:      getDataSourceRedfishBootOptionsSchema(),
+	}
+}
+
+func getDataSourceRedfishBootOptionsSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"redfish_alias":  redfishAliasSchema(),
+		"redfish_server": redfishServerSchema(),
+		"system_id":      systemIDSchema(false),
+		"boot_options": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: "The boot options of the system, which the references of the boot order refer to",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"odata_id": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "OData ID of the boot option",
+					},
+					"id": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "ID of the boot option",
+					},
+					"boot_option_reference": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "The reference of the boot option in the boot order, such as Boot0003",
+					},
+					"display_name": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "The user-readable name of the boot option, as shown in the BIOS boot menu",
+					},
+					"uefi_device_path": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "The UEFI device path of the device the boot option boots from",
+					},
+					"boot_option_enabled": {
+						Type:        schema.TypeBool,
+						Computed:    true,
+						Description: "Whether the system can boot from the boot option",
+					},
+					"alias": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "The boot source override target the boot option is an alias of, if any",
+					},
+					"related_items": {
+						Type:        schema.TypeList,
+						Computed:    true,
+						Description: "OData IDs of the resources the boot option boots from, such as drives or virtual media",
+						Elem: &schema.Schema{
+							Type: schema.TypeString,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceRedfishBootOptionsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	service, err := NewConfig(m.(*providerMeta).config, d)
+	if err != nil {
+		return diag.Errorf(err.Error())
+	}
+	return readRedfishBootOptions(service, d)
+}
+
+func readRedfishBootOptions(service *gofish.Service, d *schema.ResourceData) diag.Diagnostics {
+	system, err := getSystemResource(service, d.Get("system_id").(string))
+	if err != nil {
+		return redfishDiagnostics("error fetching the system", err, nil)
+	}
+	collectionURI, options, err := getBootOptions(system)
+	if err != nil {
+		return redfishDiagnostics("error fetching the boot options", err, nil)
+	}
+
+	list := make([]map[string]interface{}, 0, len(options))
+	for _, o := range options {
+		list = append(list, map[string]interface{}{
+			"odata_id":              o.ODataID,
+			"id":                    o.ID,
+			"boot_option_reference": o.BootOptionReference,
+			"display_name":          o.DisplayName,
+			"uefi_device_path":      o.UefiDevicePath,
+			"boot_option_enabled":   o.BootOptionEnabled,
+			"alias":                 string(o.Alias),
+			"related_items":         o.RelatedItem.ToStrings(),
+		})
+	}
+	if err := d.Set("boot_options", list); err != nil {
+		return diag.Errorf("error setting boot_options: %s", err)
+	}
+
+	d.SetId(collectionURI)
+	return nil
+}
+
+// bootOption is a boot option with the links to the resources it boots from, which gofish does not decode
+type bootOption struct {
+	redfish.BootOption
+	RelatedItem redfishcommon.Links
+}
+
+// getBootOptions returns the URI of the boot options collection of the system and its members, in the order of the
+// collection. Systems without boot options give an empty URI.
+func getBootOptions(system *redfish.ComputerSystem) (string, []bootOption, error) {
+	var links struct {
+		Boot struct {
+			BootOptions redfishcommon.Link
+		}
+	}
+	if err := getJSON(system.GetClient(), system.ODataID, &links); err != nil {
+		return "", nil, err
+	}
+	collectionURI := links.Boot.BootOptions.String()
+	if collectionURI == "" {
+		return "", nil, nil
+	}
+
+	collection, err := redfishcommon.GetCollection(system.GetClient(), collectionURI)
+	if err != nil {
+		return "", nil, err
+	}
+	options := make([]bootOption, 0, len(collection.ItemLinks))
+	for _, uri := range collection.ItemLinks {
+		var o bootOption
+		if err := getJSON(system.GetClient(), uri, &o); err != nil {
+			return "", nil, err
+		}
+		options = append(options, o)
+	}
+	return collectionURI, options, nil
+}
@@ -0,0 +1,62 @@
+package redfish
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccRedfishBootOptions_fetch(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishDatasourceBootOptionsConfig(creds),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.redfish_boot_options.options", "boot_options.0.boot_option_reference"),
+				),
+			},
+		},
+	})
+}
+
+// Test that the boot options of an emulated iDRAC are read with their details and links
+func TestRedfishBootOptions_emulated(t *testing.T) {
+	_, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishDatasourceBootOptionsConfig(creds),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.redfish_boot_options.options", "id", "/redfish/v1/Systems/System.Embedded.1/BootOptions"),
+					resource.TestCheckResourceAttr("data.redfish_boot_options.options", "boot_options.#", "3"),
+					resource.TestCheckResourceAttr("data.redfish_boot_options.options", "boot_options.1.boot_option_reference", "Boot0004"),
+					resource.TestCheckResourceAttr("data.redfish_boot_options.options", "boot_options.1.display_name", "PXE Device 1: Integrated NIC 1 Port 1 Partition 1"),
+					resource.TestCheckResourceAttr("data.redfish_boot_options.options", "boot_options.1.boot_option_enabled", "true"),
+					resource.TestCheckResourceAttr("data.redfish_boot_options.options", "boot_options.1.related_items.#", "0"),
+					resource.TestCheckResourceAttr("data.redfish_boot_options.options", "boot_options.2.boot_option_enabled", "false"),
+					resource.TestCheckResourceAttr("data.redfish_boot_options.options", "boot_options.2.related_items.0", "/redfish/v1/Systems/System.Embedded.1/VirtualMedia/1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccRedfishDatasourceBootOptionsConfig(testingInfo TestingServerCredentials) string {
+	return fmt.Sprintf(`
+	data "redfish_boot_options" "options" {
+		redfish_server {
+		  user         = "%s"
+		  password     = "%s"
+		  endpoint     = "https://%s"
+		  ssl_insecure = true
+		}
+	  }
+	`,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+	)
+}
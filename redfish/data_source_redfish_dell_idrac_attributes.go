@@ -19,36 +19,9 @@ func dataSourceRedfishDellIdracAttributes() *schema.Resource {
 
 func getDataSourceRedfishDellIdracAttributesSchema() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
-		"redfish_server": {
-			Type:        schema.TypeList,
-			Required:    true,
-			Description: "List of server BMCs and their respective user credentials",
-			Elem: &schema.Resource{
-				Schema: map[string]*schema.Schema{
-					"user": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Description: "User name for login",
-					},
-					"password": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Description: "User password for login",
-						Sensitive:   true,
-					},
-					"endpoint": {
-						Type:        schema.TypeString,
-						Required:    true,
-						Description: "Server BMC IP address or hostname",
-					},
-					"ssl_insecure": {
-						Type:        schema.TypeBool,
-						Optional:    true,
-						Description: "This field indicates whether the SSL/TLS certificate must be verified or not",
-					},
-				},
-			},
-		},
+		"redfish_alias":  redfishAliasSchema(),
+		"redfish_server": redfishServerSchema(),
+		"manager_id":     managerIDSchema(false),
 		"attributes": {
 			Type:        schema.TypeMap,
 			Computed:    true,
@@ -61,7 +34,7 @@ func getDataSourceRedfishDellIdracAttributesSchema() map[string]*schema.Schema {
 }
 
 func dataSourceRedfishDellIdracAttributesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	service, err := NewConfig(m.(*schema.ResourceData), d)
+	service, err := NewConfig(m.(*providerMeta).config, d)
 	if err != nil {
 		return diag.Errorf(err.Error())
 	}
@@ -71,14 +44,14 @@ func dataSourceRedfishDellIdracAttributesRead(ctx context.Context, d *schema.Res
 func readDatasourceRedfishDellIdracAttributes(service *gofish.Service, d *schema.ResourceData) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	// get managers (Dell servers have only the iDRAC)
-	managers, err := service.Managers()
+	// get the manager (Dell servers have only the iDRAC)
+	manager, err := getManagerResource(service, d.Get("manager_id").(string))
 	if err != nil {
 		return diag.Errorf("there was an issue when reading idrac attributes - %s", err)
 	}
 
 	// Get OEM
-	dellManager, err := dell.DellManager(managers[0])
+	dellManager, err := dell.DellManager(manager)
 	if err != nil {
 		return diag.Errorf("there was an issue when reading idrac attributes - %s", err)
 	}
@@ -25,36 +25,8 @@ func dataSourceRedfishFirmwareInventory() *schema.Resource {
 
 func getDataSourceRedfishFirmwareInventorySchema() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
-		"redfish_server": {
-			Type:        schema.TypeList,
-			Required:    true,
-			Description: "List of server BMCs and their respective user credentials",
-			Elem: &schema.Resource{
-				Schema: map[string]*schema.Schema{
-					"user": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Description: "User name for login",
-					},
-					"password": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Description: "User password for login",
-						Sensitive:   true,
-					},
-					"endpoint": {
-						Type:        schema.TypeString,
-						Required:    true,
-						Description: "Server BMC IP address or hostname",
-					},
-					"ssl_insecure": {
-						Type:        schema.TypeBool,
-						Optional:    true,
-						Description: "This field indicates whether the SSL/TLS certificate must be verified or not",
-					},
-				},
-			},
-		},
+		"redfish_alias":  redfishAliasSchema(),
+		"redfish_server": redfishServerSchema(),
 		"odata_id": {
 			Type:        schema.TypeString,
 			Description: "OData ID for the Firmware Inventory resource",
@@ -90,11 +62,11 @@ func getDataSourceRedfishFirmwareInventorySchema() map[string]*schema.Schema {
 }
 
 func dataSourceRedfishFirmwareInventoryRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	service, err := NewConfig(m.(*schema.ResourceData), d)
+	service, err := NewConfig(m.(*providerMeta).config, d)
 	if err != nil {
 		return diag.Errorf(err.Error())
 	}
-	return readRedfishFirmwareInventory(service, d)
+	return readRedfishFirmwareInventory(service, d, m)
 }
 
 func flattenInventoryItems(inventoryItems *[]InventoryItem) []interface{} {
@@ -133,7 +105,7 @@ func getInventoryItems(fwInventories []*redfish.SoftwareInventory) []InventoryIt
 	return inventoryItemList
 }
 
-func readRedfishFirmwareInventory(service *gofish.Service, d *schema.ResourceData) diag.Diagnostics {
+func readRedfishFirmwareInventory(service *gofish.Service, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
 	updateService, err := service.UpdateService()
@@ -164,8 +136,7 @@ func readRedfishFirmwareInventory(service *gofish.Service, d *schema.ResourceDat
 		return diag.Errorf("error setting Firmware Inventory: %s", err)
 	}
 
-	serverConfig := d.Get("redfish_server").([]interface{})
-	endpoint := serverConfig[0].(map[string]interface{})["endpoint"].(string)
+	endpoint := getRedfishServerEndpoint(m.(*providerMeta).config, d)
 	fwResourceID := endpoint + updateService.ODataID
 	d.SetId(fwResourceID)
 
@@ -0,0 +1,238 @@
+package redfish
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stmcginnis/gofish"
+	redfishcommon "github.com/stmcginnis/gofish/common"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+func dataSourceRedfishSecureBootCertificates() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceRedfishSecureBootCertificatesRead,
+		Schema:      getDataSourceRedfishSecureBootCertificatesSchema(),
+	}
+}
+
+func getDataSourceRedfishSecureBootCertificatesSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"redfish_alias":  redfishAliasSchema(),
+		"redfish_server": redfishServerSchema(),
+		"system_id":      systemIDSchema(false),
+		"database_id": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Description: "ID of the Secure Boot database to list the certificates of, such as 'db', 'dbx', 'KEK', 'PK' " +
+				"or the default databases like 'dbDefault' some systems have. Every database is listed when it is not set.",
+		},
+		"certificates": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: "The certificates of the Secure Boot databases",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"odata_id": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "OData ID of the certificate",
+					},
+					"id": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "ID of the certificate",
+					},
+					"database_id": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "ID of the Secure Boot database holding the certificate",
+					},
+					"certificate_type": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "The format of the certificate, such as PEM",
+					},
+					"subject_common_name": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "The common name of the entity the certificate was issued to",
+					},
+					"issuer_common_name": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "The common name of the entity which issued the certificate",
+					},
+					"serial_number": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "The serial number of the certificate",
+					},
+					"valid_not_before": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "The date the certificate becomes valid",
+					},
+					"valid_not_after": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "The date the certificate expires",
+					},
+					"fingerprint": {
+						Type:     schema.TypeString,
+						Computed: true,
+						Description: "The fingerprint of the certificate. It is computed from the certificate with SHA-256 " +
+							"when the system does not give it.",
+					},
+					"fingerprint_hash_algorithm": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "The hash algorithm of the fingerprint, such as TPM_ALG_SHA256",
+					},
+					"uefi_signature_owner": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "The GUID of the owner of the certificate in the UEFI signature list",
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceRedfishSecureBootCertificatesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	service, err := NewConfig(m.(*providerMeta).config, d)
+	if err != nil {
+		return diag.Errorf(err.Error())
+	}
+	return readRedfishSecureBootCertificates(service, d)
+}
+
+func readRedfishSecureBootCertificates(service *gofish.Service, d *schema.ResourceData) diag.Diagnostics {
+	secureBoot, err := getSecureBootResource(service, d.Get("system_id").(string))
+	if err != nil {
+		return redfishDiagnostics("error fetching the secure boot resource", err, nil)
+	}
+	collectionURI, databases, err := getSecureBootDatabases(secureBoot)
+	if err != nil {
+		return redfishDiagnostics("error fetching the secure boot databases", err, nil)
+	}
+
+	databaseID := d.Get("database_id").(string)
+	list := make([]map[string]interface{}, 0)
+	found := false
+	for _, database := range databases {
+		if databaseID != "" && database.DatabaseID != databaseID {
+			continue
+		}
+		found = true
+		certificates, err := redfish.ListReferencedCertificates(secureBoot.GetClient(), database.Certificates.String())
+		if err != nil {
+			return redfishDiagnostics(fmt.Sprintf("error fetching the certificates of the secure boot database %s", database.DatabaseID), err, nil)
+		}
+		for _, certificate := range certificates {
+			c := flattenSecureBootCertificate(certificate)
+			c["odata_id"] = certificate.ODataID
+			c["id"] = certificate.ID
+			c["database_id"] = database.DatabaseID
+			list = append(list, c)
+		}
+	}
+	if databaseID != "" && !found {
+		return diag.Errorf("the system has no secure boot database %s", databaseID)
+	}
+	if err := d.Set("certificates", list); err != nil {
+		return diag.Errorf("error setting certificates: %s", err)
+	}
+
+	d.SetId(collectionURI)
+	return nil
+}
+
+// secureBootDatabase is a database of the keys of secure boot, which gofish does not support
+type secureBootDatabase struct {
+	redfishcommon.Entity
+	// DatabaseID is the name of the UEFI variable of the database, such as db or PK
+	DatabaseID   string `json:"DatabaseId"`
+	Certificates redfishcommon.Link
+}
+
+// getSecureBootDatabases returns the URI of the collection of the secure boot databases and its members
+func getSecureBootDatabases(secureBoot *redfish.SecureBoot) (string, []secureBootDatabase, error) {
+	var links struct {
+		SecureBootDatabases redfishcommon.Link
+	}
+	if err := getJSON(secureBoot.GetClient(), secureBoot.ODataID, &links); err != nil {
+		return "", nil, err
+	}
+	collectionURI := links.SecureBootDatabases.String()
+	if collectionURI == "" {
+		return "", nil, fmt.Errorf("the system does not show its secure boot databases")
+	}
+
+	collection, err := redfishcommon.GetCollection(secureBoot.GetClient(), collectionURI)
+	if err != nil {
+		return "", nil, err
+	}
+	databases := make([]secureBootDatabase, 0, len(collection.ItemLinks))
+	for _, uri := range collection.ItemLinks {
+		var database secureBootDatabase
+		if err := getJSON(secureBoot.GetClient(), uri, &database); err != nil {
+			return "", nil, err
+		}
+		databases = append(databases, database)
+	}
+	return collectionURI, databases, nil
+}
+
+// flattenSecureBootCertificate returns the attributes describing a certificate of a secure boot database
+func flattenSecureBootCertificate(certificate *redfish.Certificate) map[string]interface{} {
+	fingerprint, algorithm := certificate.Fingerprint, certificate.FingerprintHashAlgorithm
+	if fingerprint == "" {
+		fingerprint, algorithm = certificateFingerprint(certificate.CertificateString)
+	}
+	return map[string]interface{}{
+		"certificate_type":           string(certificate.CertificateType),
+		"subject_common_name":        certificate.Subject.CommonName,
+		"issuer_common_name":         certificate.Issuer.CommonName,
+		"serial_number":              certificate.SerialNumber,
+		"valid_not_before":           certificate.ValidNotBefore,
+		"valid_not_after":            certificate.ValidNotAfter,
+		"fingerprint":                fingerprint,
+		"fingerprint_hash_algorithm": algorithm,
+		"uefi_signature_owner":       certificate.UefiSignatureOwner,
+	}
+}
+
+// certificateFingerprint returns the SHA-256 fingerprint of a PEM certificate and its hash algorithm, or empty
+// strings if it is not one
+func certificateFingerprint(certificateString string) (string, string) {
+	block, _ := pem.Decode([]byte(certificateString))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return "", ""
+	}
+	sum := sha256.Sum256(block.Bytes)
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, ":"), "TPM_ALG_SHA256"
+}
+
+// validatePEMCertificate checks that a value holds a PEM encoded X.509 certificate
+func validatePEMCertificate(v interface{}, k string) ([]string, []error) {
+	block, _ := pem.Decode([]byte(v.(string)))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, []error{fmt.Errorf("%s must be a PEM encoded certificate", k)}
+	}
+	if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+		return nil, []error{fmt.Errorf("%s is not a valid certificate: %w", k, err)}
+	}
+	return nil, nil
+}
@@ -0,0 +1,71 @@
+package redfish
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccRedfishSecureBootCertificates_fetch(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishDatasourceSecureBootCertificatesConfig(creds, `database_id = "db"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.redfish_secure_boot_certificates.certificates", "certificates.0.fingerprint"),
+				),
+			},
+		},
+	})
+}
+
+// Test that the certificates of the secure boot databases of an emulated iDRAC are listed, all of them or the ones
+// of a database
+func TestRedfishSecureBootCertificates_emulated(t *testing.T) {
+	_, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishDatasourceSecureBootCertificatesConfig(creds, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.redfish_secure_boot_certificates.certificates", "id", "/redfish/v1/Systems/System.Embedded.1/SecureBoot/SecureBootDatabases"),
+					resource.TestCheckResourceAttr("data.redfish_secure_boot_certificates.certificates", "certificates.#", "3"),
+					resource.TestCheckResourceAttr("data.redfish_secure_boot_certificates.certificates", "certificates.0.database_id", "PK"),
+					resource.TestCheckResourceAttr("data.redfish_secure_boot_certificates.certificates", "certificates.0.subject_common_name", "Emulated Platform Key"),
+				),
+			},
+			{
+				Config: testAccRedfishDatasourceSecureBootCertificatesConfig(creds, `database_id = "db"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.redfish_secure_boot_certificates.certificates", "certificates.#", "1"),
+					resource.TestCheckResourceAttr("data.redfish_secure_boot_certificates.certificates", "certificates.0.subject_common_name", "Emulated UEFI CA"),
+					resource.TestCheckResourceAttr("data.redfish_secure_boot_certificates.certificates", "certificates.0.issuer_common_name", "Emulated UEFI CA"),
+					resource.TestCheckResourceAttr("data.redfish_secure_boot_certificates.certificates", "certificates.0.certificate_type", "PEM"),
+				),
+			},
+		},
+	})
+}
+
+func testAccRedfishDatasourceSecureBootCertificatesConfig(testingInfo TestingServerCredentials, settings string) string {
+	return fmt.Sprintf(`
+	data "redfish_secure_boot_certificates" "certificates" {
+		redfish_server {
+		  user         = "%s"
+		  password     = "%s"
+		  endpoint     = "https://%s"
+		  ssl_insecure = true
+		}
+
+		%s
+	  }
+	`,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+		settings,
+	)
+}
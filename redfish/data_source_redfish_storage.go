@@ -19,36 +19,9 @@ func dataSourceRedfishStorage() *schema.Resource {
 
 func getDataSourceRedfishStorageSchema() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
-		"redfish_server": {
-			Type:        schema.TypeList,
-			Required:    true,
-			Description: "List of server BMCs and their respective user credentials",
-			Elem: &schema.Resource{
-				Schema: map[string]*schema.Schema{
-					"user": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Description: "User name for login",
-					},
-					"password": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Description: "User password for login",
-						Sensitive:   true,
-					},
-					"endpoint": {
-						Type:        schema.TypeString,
-						Required:    true,
-						Description: "Server BMC IP address or hostname",
-					},
-					"ssl_insecure": {
-						Type:        schema.TypeBool,
-						Optional:    true,
-						Description: "This field indicates whether the SSL/TLS certificate must be verified or not",
-					},
-				},
-			},
-		},
+		"redfish_alias":  redfishAliasSchema(),
+		"redfish_server": redfishServerSchema(),
+		"system_id":      systemIDSchema(false),
 		"storage": {
 			Type:        schema.TypeList,
 			Description: "List of storage and disks attached available on this instance",
@@ -75,7 +48,7 @@ func getDataSourceRedfishStorageSchema() map[string]*schema.Schema {
 }
 
 func dataSourceRedfishStorageRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	service, err := NewConfig(m.(*schema.ResourceData), d)
+	service, err := NewConfig(m.(*providerMeta).config, d)
 	if err != nil {
 		return diag.Errorf(err.Error())
 	}
@@ -86,11 +59,11 @@ func readRedfishStorageCollection(service *gofish.Service, d *schema.ResourceDat
 	var diags diag.Diagnostics
 	m := make([]map[string]interface{}, 0) //List where all storage controller will be held
 
-	systems, err := service.Systems()
+	system, err := getSystemResource(service, d.Get("system_id").(string))
 	if err != nil {
 		return diag.Errorf("Error when retrieving systems: %s", err)
 	}
-	storage, err := systems[0].Storage()
+	storage, err := system.Storage()
 	if err != nil {
 		return diag.Errorf("Error when retrieving storage: %s", err)
 	}
@@ -6,7 +6,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/stmcginnis/gofish"
-	"github.com/stmcginnis/gofish/redfish"
 )
 
 func dataSourceRedfishSystemBoot() *schema.Resource {
@@ -18,36 +17,8 @@ func dataSourceRedfishSystemBoot() *schema.Resource {
 
 func getDataSourceRedfishSystemBootSchema() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
-		"redfish_server": {
-			Type:        schema.TypeList,
-			Required:    true,
-			Description: "List of server BMCs and their respective user credentials",
-			Elem: &schema.Resource{
-				Schema: map[string]*schema.Schema{
-					"user": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Description: "User name for login",
-					},
-					"password": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Description: "User password for login",
-						Sensitive:   true,
-					},
-					"endpoint": {
-						Type:        schema.TypeString,
-						Required:    true,
-						Description: "Server BMC IP address or hostname",
-					},
-					"ssl_insecure": {
-						Type:        schema.TypeBool,
-						Optional:    true,
-						Description: "This field indicates whether the SSL/TLS certificate must be verified or not",
-					},
-				},
-			},
-		},
+		"redfish_alias":  redfishAliasSchema(),
+		"redfish_server": redfishServerSchema(),
 		"resource_id": {
 			Type:        schema.TypeString,
 			Optional:    true,
@@ -85,7 +56,7 @@ func getDataSourceRedfishSystemBootSchema() map[string]*schema.Schema {
 }
 
 func dataSourceRedfishSystemBootRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	service, err := NewConfig(m.(*schema.ResourceData), d)
+	service, err := NewConfig(m.(*providerMeta).config, d)
 	if err != nil {
 		return diag.Errorf(err.Error())
 	}
@@ -96,32 +67,12 @@ func dataSourceRedfishSystemBootRead(ctx context.Context, d *schema.ResourceData
 func readRedfishSystemBoot(service *gofish.Service, d *schema.ResourceData) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	systems, err := service.Systems()
+	// get the boot resource, from the first system resource in the collection if resource ID is not provided
+	computerSystem, err := getSystemResource(service, d.Get("resource_id").(string))
 	if err != nil {
 		return diag.Errorf("Error when retrieving systems: %s", err)
 	}
-
-	// get the boot resource
-	var computerSystem *redfish.ComputerSystem
-	var boot redfish.Boot
-	if systemResourceId, ok := d.GetOk("resource_id"); ok {
-		for key := range systems {
-			if systems[key].ID == systemResourceId {
-				computerSystem = systems[key]
-				boot = systems[key].Boot
-				break
-			}
-		}
-
-		if computerSystem == nil {
-			return diag.Errorf("Could not find a ComputerSystem resource with resource ID = %s", systemResourceId)
-		}
-	} else {
-		// use the first system resource in the collection if resource
-		// ID is not provided
-		computerSystem = systems[0]
-		boot = systems[0].Boot
-	}
+	boot := computerSystem.Boot
 
 	if err := d.Set("boot_order", boot.BootOrder); err != nil {
 		return diag.Errorf("error setting BootOrder: %s", err)
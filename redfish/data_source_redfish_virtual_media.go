@@ -20,36 +20,9 @@ func dataSourceRedfishVirtualMedia() *schema.Resource {
 
 func getDataSourceRedfishVirtualMediaSchema() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
-		"redfish_server": {
-			Type:        schema.TypeList,
-			Required:    true,
-			Description: "List of server BMCs and their respective user credentials",
-			Elem: &schema.Resource{
-				Schema: map[string]*schema.Schema{
-					"user": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Description: "User name for login",
-					},
-					"password": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Description: "User password for login",
-						Sensitive:   true,
-					},
-					"endpoint": {
-						Type:        schema.TypeString,
-						Required:    true,
-						Description: "Server BMC IP address or hostname",
-					},
-					"ssl_insecure": {
-						Type:        schema.TypeBool,
-						Optional:    true,
-						Description: "This field indicates whether the SSL/TLS certificate must be verified or not",
-					},
-				},
-			},
-		},
+		"redfish_alias":  redfishAliasSchema(),
+		"redfish_server": redfishServerSchema(),
+		"manager_id":     managerIDSchema(false),
 		"virtual_media": {
 			Type:        schema.TypeList,
 			Description: "List of virtual media available on this instance",
@@ -73,7 +46,7 @@ func getDataSourceRedfishVirtualMediaSchema() map[string]*schema.Schema {
 }
 
 func dataSourceRedfishVirtualMediaRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	service, err := NewConfig(m.(*schema.ResourceData), d)
+	service, err := NewConfig(m.(*providerMeta).config, d)
 	if err != nil {
 		return diag.Errorf(err.Error())
 	}
@@ -83,14 +56,14 @@ func dataSourceRedfishVirtualMediaRead(ctx context.Context, d *schema.ResourceDa
 func readRedfishVirtualMediaCollection(service *gofish.Service, d *schema.ResourceData) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	//Get manager.Since this provider is thought to work with individual servers, should be only one.
-	manager, err := service.Managers()
+	//Get manager
+	manager, err := getManagerResource(service, d.Get("manager_id").(string))
 	if err != nil {
 		return diag.Errorf("Error retrieving the managers: %s", err)
 	}
 
 	//Get virtual media
-	virtualMedia, err := manager[0].VirtualMedia()
+	virtualMedia, err := manager.VirtualMedia()
 	if err != nil {
 		return diag.Errorf("Error retrieving the virtual media instances: %s", err)
 	}
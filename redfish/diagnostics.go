@@ -0,0 +1,93 @@
+package redfish
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/dell/terraform-provider-redfish/common"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// attributePaths maps the properties of a redfish resource to the attributes of the terraform resource setting
+// them, such as UserName to username. It locates the attributes the messages of a redfish error are about. Nested
+// properties are separated with /, like @Redfish.SettingsApplyTime/ApplyTime.
+type attributePaths map[string]string
+
+// path returns the path of the attribute setting the property a RelatedProperties JSON pointer refers to, or nil
+// if no attribute sets it. A pointer into a property set by a map, like #/Attributes/NumLock, leads to the key of
+// the map.
+func (p attributePaths) path(pointer string) cty.Path {
+	property := strings.TrimPrefix(strings.TrimPrefix(pointer, "#"), "/")
+	if attribute, ok := p[property]; ok {
+		return cty.GetAttrPath(attribute)
+	}
+
+	segments := strings.SplitN(property, "/", 2)
+	attribute, ok := p[segments[0]]
+	if !ok {
+		return nil
+	}
+	path := cty.GetAttrPath(attribute)
+	// Indexes of redfish arrays do not match the ones of the terraform lists, the whole list is pointed to then
+	if _, err := strconv.Atoi(segments[1]); err != nil {
+		path = path.IndexString(segments[1])
+	}
+	return path
+}
+
+// redfishDiagnostics translates an error from a redfish service into diagnostics. Each message explaining the error,
+// from the @Message.ExtendedInfo of an error response or from a job which did not complete, makes up a diagnostic
+// with the given summary, whose detail is the message, its ID and its resolution. Diagnostics of messages about
+// properties set by attributes point to the first of them through paths, which can be nil. Messages of a lower
+// severity than others are only warnings. An error without messages makes up a single diagnostic.
+func redfishDiagnostics(summary string, err error, paths attributePaths) diag.Diagnostics {
+	messages := common.Messages(err)
+	if len(messages) == 0 {
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  summary,
+			Detail:   err.Error(),
+		}}
+	}
+
+	critical := false
+	for _, m := range messages {
+		critical = critical || isCritical(m)
+	}
+
+	var diags diag.Diagnostics
+	for _, m := range messages {
+		d := diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  summary,
+			Detail:   messageDetail(m),
+		}
+		if critical && !isCritical(m) {
+			d.Severity = diag.Warning
+		}
+		for _, pointer := range m.RelatedProperties {
+			if d.AttributePath = paths.path(pointer); d.AttributePath != nil {
+				break
+			}
+		}
+		diags = append(diags, d)
+	}
+	return diags
+}
+
+// isCritical tells whether a message is an error, messages without severity being taken as errors
+func isCritical(m common.Message) bool {
+	return m.Severity != "OK" && m.Severity != "Warning"
+}
+
+func messageDetail(m common.Message) string {
+	detail := m.Message
+	if m.MessageID != "" {
+		detail += " (" + m.MessageID + ")"
+	}
+	if m.Resolution != "" && m.Resolution != "None" {
+		detail += "\n" + m.Resolution
+	}
+	return detail
+}
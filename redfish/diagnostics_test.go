@@ -0,0 +1,98 @@
+package redfish
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	redfishcommon "github.com/stmcginnis/gofish/common"
+)
+
+func TestRedfishDiagnostics(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want diag.Diagnostics
+	}{
+		{
+			name: "extended info",
+			err: redfishcommon.ConstructError(400, []byte(`{"error": {"code": "Base.1.12.GeneralError", "message": "A general error has occurred.",
+				"@Message.ExtendedInfo": [
+					{"MessageId": "Base.1.12.PropertyValueNotInList", "Message": "The value Of for the property NumLock is not in the list of acceptable values.",
+					 "Resolution": "Choose a value from the enumeration list.", "Severity": "Warning", "RelatedProperties": ["#/Attributes/NumLock"]},
+					{"MessageId": "IDRAC.2.8.SYS403", "Message": "Unable to apply the configuration changes.", "Resolution": "None", "Severity": "Critical",
+					 "RelatedProperties": ["#/@Redfish.SettingsApplyTime/ApplyTime"]}
+				]}}`)),
+			want: diag.Diagnostics{
+				{
+					Severity:      diag.Warning,
+					Summary:       "error updating bios attributes",
+					Detail:        "The value Of for the property NumLock is not in the list of acceptable values. (Base.1.12.PropertyValueNotInList)\nChoose a value from the enumeration list.",
+					AttributePath: cty.GetAttrPath("attributes").IndexString("NumLock"),
+				},
+				{
+					Severity:      diag.Error,
+					Summary:       "error updating bios attributes",
+					Detail:        "Unable to apply the configuration changes. (IDRAC.2.8.SYS403)",
+					AttributePath: cty.GetAttrPath("settings_apply_time"),
+				},
+			},
+		},
+		{
+			name: "warning only",
+			err: redfishcommon.ConstructError(400, []byte(`{"error": {"@Message.ExtendedInfo": [
+					{"MessageId": "Base.1.12.PropertyUnknown", "Message": "The property Unknown is not in the list of valid properties for the resource.",
+					 "Severity": "Warning", "RelatedProperties": ["#/Unknown"]}]}}`)),
+			want: diag.Diagnostics{
+				{
+					Severity: diag.Error,
+					Summary:  "error updating bios attributes",
+					Detail:   "The property Unknown is not in the list of valid properties for the resource. (Base.1.12.PropertyUnknown)",
+				},
+			},
+		},
+		{
+			name: "no messages",
+			err:  errors.New("connection refused"),
+			want: diag.Diagnostics{
+				{
+					Severity: diag.Error,
+					Summary:  "error updating bios attributes",
+					Detail:   "connection refused",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := redfishDiagnostics("error updating bios attributes", tt.err, biosAttributePaths)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttributePaths(t *testing.T) {
+	tests := []struct {
+		pointer string
+		want    cty.Path
+	}{
+		{pointer: "#/Name", want: cty.GetAttrPath("volume_name")},
+		{pointer: "#/Oem/Dell/DellVolume/DiskCachePolicy", want: cty.GetAttrPath("disk_cache_policy")},
+		{pointer: "#/Drives/1", want: cty.GetAttrPath("drives")},
+		{pointer: "#/Encrypted"},
+		{pointer: "#/Oem/Dell/DellVolume/Unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pointer, func(t *testing.T) {
+			if got := volumeAttributePaths.path(tt.pointer); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package redfish
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// importIDSeparator separates the server from the redfish object in import IDs
+const importIDSeparator = "|"
+
+// importTLSSettings are the settings of the redfish_server block an import ID can give after the redfish object, for
+// endpoints whose certificate is not trusted by default
+var importTLSSettings = []string{"ssl_insecure", "ca_cert_file", "cert_fingerprint_sha256"}
+
+// parseImportID reads an import ID of the form <endpoint or alias>|<redfish object>[|<TLS settings>] and sets the
+// server the imported resource acts on. When the first part is the alias of a server declared in the provider,
+// redfish_alias is set. Otherwise it is taken as the endpoint of the redfish_server block, with the credentials of the
+// provider and the TLS settings given as comma separated name=value pairs, such as ssl_insecure=true.
+// It returns the redfish object, usually an @odata.id.
+func parseImportID(d *schema.ResourceData, m interface{}) (string, error) {
+	parts := strings.SplitN(d.Id(), importIDSeparator, 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", fmt.Errorf("Error. Import ID %s is not valid, it must be <endpoint or alias>%s<redfish object>", d.Id(), importIDSeparator)
+	}
+	server, object := parts[0], parts[1]
+
+	for _, v := range m.(*providerMeta).config.Get("servers").([]interface{}) {
+		if v.(map[string]interface{})["alias"].(string) == server {
+			if len(parts) == 3 {
+				return "", fmt.Errorf("Error. Import ID %s gives TLS settings to the alias %s, which takes the ones of the provider servers block", d.Id(), server)
+			}
+			return object, d.Set("redfish_alias", server)
+		}
+	}
+
+	serverConfig := map[string]interface{}{"endpoint": server}
+	if len(parts) == 3 {
+		for _, setting := range strings.Split(parts[2], ",") {
+			name, value, _ := strings.Cut(setting, "=")
+			switch {
+			case name == "ssl_insecure":
+				insecure, err := strconv.ParseBool(value)
+				if err != nil {
+					return "", fmt.Errorf("Error. Import ID %s is not valid, ssl_insecure must be true or false", d.Id())
+				}
+				serverConfig[name] = insecure
+			case contains(importTLSSettings, name) && value != "":
+				serverConfig[name] = value
+			default:
+				return "", fmt.Errorf("Error. Import ID %s is not valid, the TLS settings it can give are %s, as name=value "+
+					"pairs separated by commas", d.Id(), strings.Join(importTLSSettings, ", "))
+			}
+		}
+	}
+	return object, d.Set("redfish_server", []interface{}{serverConfig})
+}
+
+// odataIDMember returns the ID of the member of collection found in the path of odataID, for instance the system
+// ID System.Embedded.1 of /redfish/v1/Systems/System.Embedded.1/Bios. It returns an empty string if the path does not
+// go through collection.
+func odataIDMember(odataID string, collection string) string {
+	segments := strings.Split(strings.Trim(odataID, "/"), "/")
+	for i := 0; i < len(segments)-1; i++ {
+		if segments[i] == collection {
+			return segments[i+1]
+		}
+	}
+	return ""
+}
+
+// setImportDefaults sets the default value of every attribute of resourceSchema not known after an import, so the
+// first plan does not report differences for attributes which were left to their defaults in the configuration
+func setImportDefaults(d *schema.ResourceData, resourceSchema map[string]*schema.Schema) error {
+	for k, s := range resourceSchema {
+		if s.Default == nil {
+			continue
+		}
+		if _, ok := d.GetOkExists(k); ok {
+			continue
+		}
+		if err := d.Set(k, s.Default); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package redfish
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestParseImportID(t *testing.T) {
+	provider := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"servers": []interface{}{
+			map[string]interface{}{
+				"alias":    "server1",
+				"endpoint": "https://server1",
+			},
+		},
+	})
+
+	tests := []struct {
+		name     string
+		id       string
+		alias    string
+		endpoint string
+		insecure bool
+		object   string
+		errors   bool
+	}{
+		{
+			name:     "alias",
+			id:       "server1|/redfish/v1/AccountService/Accounts/3",
+			alias:    "server1",
+			endpoint: "https://server1",
+			object:   "/redfish/v1/AccountService/Accounts/3",
+		},
+		{
+			name:     "endpoint",
+			id:       "https://server2|/redfish/v1/AccountService/Accounts/3",
+			endpoint: "https://server2",
+			object:   "/redfish/v1/AccountService/Accounts/3",
+		},
+		{
+			name:     "endpoint with TLS settings",
+			id:       "https://server2|/redfish/v1/AccountService/Accounts/3|ssl_insecure=true,cert_fingerprint_sha256=AB:CD",
+			endpoint: "https://server2",
+			insecure: true,
+			object:   "/redfish/v1/AccountService/Accounts/3",
+		},
+		{
+			name:   "unknown TLS setting",
+			id:     "https://server2|/redfish/v1/AccountService/Accounts/3|client_key=key.pem",
+			errors: true,
+		},
+		{
+			name:   "invalid ssl_insecure",
+			id:     "https://server2|/redfish/v1/AccountService/Accounts/3|ssl_insecure=maybe",
+			errors: true,
+		},
+		{
+			name:   "alias with TLS settings",
+			id:     "server1|/redfish/v1/AccountService/Accounts/3|ssl_insecure=true",
+			errors: true,
+		},
+		{
+			name:   "missing object",
+			id:     "https://server2|",
+			errors: true,
+		},
+		{
+			name:   "missing server",
+			id:     "/redfish/v1/AccountService/Accounts/3",
+			errors: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, getResourceRedfishUserAccountSchema(), map[string]interface{}{})
+			d.SetId(tt.id)
+			object, err := parseImportID(d, &providerMeta{config: provider})
+			if tt.errors {
+				if err == nil {
+					t.Errorf("expected an error, got object %s", object)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if object != tt.object {
+				t.Errorf("got object %s, want %s", object, tt.object)
+			}
+			if got := d.Get("redfish_alias").(string); got != tt.alias {
+				t.Errorf("got redfish_alias %q, want %q", got, tt.alias)
+			}
+			if got := getRedfishServerEndpoint(provider, d); got != tt.endpoint {
+				t.Errorf("got endpoint %q, want %q", got, tt.endpoint)
+			}
+			config, err := getServerConfig(provider, d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if config.tls.Insecure != tt.insecure {
+				t.Errorf("got ssl_insecure %t, want %t", config.tls.Insecure, tt.insecure)
+			}
+		})
+	}
+}
+
+func TestOdataIDMember(t *testing.T) {
+	odataID := "/redfish/v1/Systems/System.Embedded.1/Storage/RAID.Integrated.1-1/Volumes/Disk.Virtual.0:RAID.Integrated.1-1"
+	if got := odataIDMember(odataID, "Systems"); got != "System.Embedded.1" {
+		t.Errorf("got system %q, want System.Embedded.1", got)
+	}
+	if got := odataIDMember(odataID, "Storage"); got != "RAID.Integrated.1-1" {
+		t.Errorf("got storage %q, want RAID.Integrated.1-1", got)
+	}
+	if got := odataIDMember(odataID, "Managers"); got != "" {
+		t.Errorf("got manager %q, want an empty string", got)
+	}
+}
@@ -1,46 +1,113 @@
 package redfish
 
 import (
+	"fmt"
+	"time"
+
+	"github.com/dell/terraform-provider-redfish/common"
 	"github.com/dell/terraform-provider-redfish/mutexkv"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 // This is a global MutexKV for use within this plugin
 var redfishMutexKV = mutexkv.NewMutexKV()
 
+// providerMeta is the meta the provider gives to its resources and data sources
+type providerMeta struct {
+	// config is the configuration of the provider
+	config *schema.ResourceData
+	// waits are the times the resources wait for the BMCs
+	waits waitTimes
+}
+
+// waitTimes are the times the resources wait for the BMCs: the intervals in seconds between checks of the jobs and
+// power states, and the times given to the BMCs to settle once they are done
+type waitTimes struct {
+	biosConfigJobCheck    int
+	simpleUpdateJobCheck  int
+	storageVolumeJobCheck int
+	bootResetCheck        int
+	biosSettingsSettle    time.Duration
+	powerSettle           time.Duration
+}
+
+// defaultWaitTimes are the times the resources wait for real BMCs
+var defaultWaitTimes = waitTimes{
+	biosConfigJobCheck:    intervalBiosConfigJobCheckTime,
+	simpleUpdateJobCheck:  intervalSimpleUpdateJobCheckTime,
+	storageVolumeJobCheck: intervalStorageVolumeJobCheckTime,
+	bootResetCheck:        intervalBootResetCheckTime,
+	biosSettingsSettle:    biosSettingsSettleTime,
+	powerSettle:           powerSettleTime,
+}
+
 func Provider() *schema.Provider {
+	return newProvider(defaultWaitTimes)
+}
+
+// newProvider returns the provider, whose resources wait the given times for the BMCs
+func newProvider(waits waitTimes) *schema.Provider {
 	provider := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"user": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Description: "Default value. This field is the user to login against the redfish API",
+				DefaultFunc: schema.EnvDefaultFunc("REDFISH_USER", nil),
+				Description: "Default value. This field is the user to login against the redfish API. It can also be set through the REDFISH_USER environment variable",
 			},
 			"password": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Description: "Default value. This field is the password related to the user given",
+				DefaultFunc: schema.EnvDefaultFunc("REDFISH_PASSWORD", nil),
+				Description: "Default value. This field is the password related to the user given. It can also be set through the REDFISH_PASSWORD environment variable",
+			},
+			"servers": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "List of server BMCs which resources and data sources can refer to through redfish_alias",
+				Elem: &schema.Resource{
+					Schema: providerServerSchema(),
+				},
+			},
+			"retry": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Policy for retrying the requests to the BMCs which fail with a transient error, such as 503 Service Unavailable or the iDRAC not being ready",
+				Elem: &schema.Resource{
+					Schema: providerRetrySchema(),
+				},
 			},
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
-			"redfish_user_account":          resourceRedfishUserAccount(),
-			"redfish_bios":                  resourceRedfishBios(),
-			"redfish_storage_volume":        resourceRedfishStorageVolume(),
-			"redfish_virtual_media":         resourceRedfishVirtualMedia(),
-			"redfish_power":                 resourceRedFishPower(),
-			"redfish_simple_update":         resourceRedfishSimpleUpdate(),
-			"redfish_dell_idrac_attributes": resourceRedfishDellIdracAttributes(),
+			"redfish_user_account":            resourceRedfishUserAccount(),
+			"redfish_bios":                    resourceRedfishBios(),
+			"redfish_bios_password":           resourceRedfishBiosPassword(),
+			"redfish_boot":                    resourceRedfishBoot(),
+			"redfish_secure_boot":             resourceRedfishSecureBoot(),
+			"redfish_secure_boot_certificate": resourceRedfishSecureBootCertificate(),
+			"redfish_storage_controller":      resourceRedfishStorageController(),
+			"redfish_storage_drive_mode":      resourceRedfishStorageDriveMode(),
+			"redfish_storage_hot_spare":       resourceRedfishStorageHotSpare(),
+			"redfish_storage_volume":          resourceRedfishStorageVolume(),
+			"redfish_virtual_media":           resourceRedfishVirtualMedia(),
+			"redfish_power":                   resourceRedFishPower(),
+			"redfish_simple_update":           resourceRedfishSimpleUpdate(),
+			"redfish_dell_idrac_attributes":   resourceRedfishDellIdracAttributes(),
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
-			"redfish_bios":                  dataSourceRedfishBios(),
-			"redfish_virtual_media":         dataSourceRedfishVirtualMedia(),
-			"redfish_storage":               dataSourceRedfishStorage(),
-			"redfish_firmware_inventory":    dataSourceRedfishFirmwareInventory(),
-			"redfish_dell_idrac_attributes": dataSourceRedfishDellIdracAttributes(),
-			"redfish_system_boot":           dataSourceRedfishSystemBoot(),
+			"redfish_bios":                     dataSourceRedfishBios(),
+			"redfish_boot_options":             dataSourceRedfishBootOptions(),
+			"redfish_virtual_media":            dataSourceRedfishVirtualMedia(),
+			"redfish_secure_boot_certificates": dataSourceRedfishSecureBootCertificates(),
+			"redfish_storage":                  dataSourceRedfishStorage(),
+			"redfish_firmware_inventory":       dataSourceRedfishFirmwareInventory(),
+			"redfish_dell_idrac_attributes":    dataSourceRedfishDellIdracAttributes(),
+			"redfish_system_boot":              dataSourceRedfishSystemBoot(),
 		},
 	}
 
@@ -51,17 +118,107 @@ func Provider() *schema.Provider {
 			// We can therefore assume that if it's missing it's 0.10 or 0.11
 			terraformVersion = "0.11+compatible"
 		}
-		return providerConfigure(d, terraformVersion)
+		return providerConfigure(d, terraformVersion, waits)
 	}
 
 	return provider
 }
 
-func providerConfigure(d *schema.ResourceData, terraformVersion string) (interface{}, error) {
-	/*Redfish token issued by iDRAC needs to be revoked when the provider is done.
-	At the moment, the terraform SDK (Provider.StopFunc) is not implemented. To follow up, please refer to this pull request:
-	https://github.com/hashicorp/terraform-plugin-sdk/pull/377
+// providerServerSchema returns the schema of a server declared in the provider, which is the one of the
+// redfish_server block plus the alias used to refer to it
+func providerServerSchema() map[string]*schema.Schema {
+	serverSchema := redfishServerElemSchema()
+	serverSchema["alias"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Required:    true,
+		Description: "Unique name used to refer to this server from redfish_alias",
+	}
+	return serverSchema
+}
+
+// providerRetrySchema returns the schema of the retry block of the provider. Its defaults are the ones of
+// common.DefaultRetryPolicy, which also applies when the block is left out.
+func providerRetrySchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"max_retries": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Default:      common.DefaultRetryPolicy.MaxRetries,
+			ValidateFunc: validation.IntAtLeast(0),
+			Description:  "Number of times a failing request is retried. 0 turns retrying off",
+		},
+		"min_backoff": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      common.DefaultRetryPolicy.MinBackoff.String(),
+			ValidateFunc: validateDuration,
+			Description:  "Wait before the first retry, such as 500ms or 2s. It doubles on every retry",
+		},
+		"max_backoff": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      common.DefaultRetryPolicy.MaxBackoff.String(),
+			ValidateFunc: validateDuration,
+			Description:  "Longest wait between two retries, including the one asked by the BMC through Retry-After",
+		},
+		"jitter": {
+			Type:         schema.TypeFloat,
+			Optional:     true,
+			Default:      common.DefaultRetryPolicy.Jitter,
+			ValidateFunc: validation.FloatBetween(0, 1),
+			Description:  "Fraction of every wait randomly cut off from it, between 0 and 1, so that requests failing together are not retried together",
+		},
+	}
+}
+
+func validateDuration(v interface{}, k string) ([]string, []error) {
+	if _, err := time.ParseDuration(v.(string)); err != nil {
+		return nil, []error{fmt.Errorf("%s is not a valid duration: %w", k, err)}
+	}
+	return nil, nil
+}
+
+// getRetryPolicy returns the retry policy configured in the provider
+func getRetryPolicy(provider *schema.ResourceData) (common.RetryPolicy, error) {
+	blocks := provider.Get("retry").([]interface{})
+	if len(blocks) == 0 || blocks[0] == nil {
+		return common.DefaultRetryPolicy, nil
+	}
+	block := blocks[0].(map[string]interface{})
+
+	// The durations have been validated already
+	minBackoff, _ := time.ParseDuration(block["min_backoff"].(string))
+	maxBackoff, _ := time.ParseDuration(block["max_backoff"].(string))
+	policy := common.RetryPolicy{
+		MaxRetries: block["max_retries"].(int),
+		MinBackoff: minBackoff,
+		MaxBackoff: maxBackoff,
+		Jitter:     block["jitter"].(float64),
+	}
+	if err := policy.Validate(); err != nil {
+		return policy, fmt.Errorf("Error. Invalid retry policy: %w", err)
+	}
+	return policy, nil
+}
+
+func providerConfigure(d *schema.ResourceData, terraformVersion string, waits waitTimes) (interface{}, error) {
+	/*Redfish sessions are created lazily by NewConfig and cached per endpoint and user. Since the terraform SDK
+	does not notify providers when they are done, they are deleted from the BMCs by CloseSessions once the plugin
+	server stops (see main.go).
 	*/
 
-	return d, nil
+	aliases := make(map[string]bool)
+	for _, v := range d.Get("servers").([]interface{}) {
+		alias := v.(map[string]interface{})["alias"].(string)
+		if aliases[alias] {
+			return nil, fmt.Errorf("Error. Server alias %s is declared more than once", alias)
+		}
+		aliases[alias] = true
+	}
+
+	if _, err := getRetryPolicy(d); err != nil {
+		return nil, err
+	}
+
+	return &providerMeta{config: d, waits: waits}, nil
 }
@@ -1,9 +1,16 @@
 package redfish
 
 import (
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"testing"
 
+	"github.com/dell/terraform-provider-redfish/internal/emulator"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 // TestingServerCredentials Struct used to store the credentials we pass for testing. This allows us to pass testing
@@ -18,6 +25,13 @@ type TestingServerCredentials struct {
 
 var testAccProviders map[string]*schema.Provider
 var testAccProvider *schema.Provider
+
+// testAccProviderFactories is used instead of testAccProviders by tests configuring the provider block, which
+// testAccProviders would declare a second time. Every provider it makes is a new one, so the provider block of a
+// test is not replaced by the one of a test running in parallel.
+var testAccProviderFactories = map[string]func() (*schema.Provider, error){
+	"redfish": func() (*schema.Provider, error) { return Provider(), nil },
+}
 var creds TestingServerCredentials
 
 func init() {
@@ -36,3 +50,95 @@ func init() {
 	}
 
 }
+
+func TestProvider(t *testing.T) {
+	if err := Provider().InternalValidate(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+// emulatedWaitTimes are the times the resources wait for emulated iDRACs, which complete their jobs at once
+var emulatedWaitTimes = waitTimes{
+	biosConfigJobCheck:    1,
+	simpleUpdateJobCheck:  1,
+	storageVolumeJobCheck: 1,
+	bootResetCheck:        1,
+}
+
+// testAccEmulatedProviderFactories is used by the tests against emulated iDRACs, whose providers wait
+// emulatedWaitTimes for them
+var testAccEmulatedProviderFactories = map[string]func() (*schema.Provider, error){
+	"redfish": func() (*schema.Provider, error) { return newProvider(emulatedWaitTimes), nil },
+}
+
+// newEmulatedServer starts an emulated iDRAC for the duration of the test and returns the credentials to reach it.
+// It makes the test parallel. The test is run by testAccEmulatedProviderFactories.
+func newEmulatedServer(t *testing.T) (*emulator.Server, TestingServerCredentials) {
+	t.Parallel()
+
+	server := emulator.NewServer()
+	t.Cleanup(server.Close)
+
+	return server, TestingServerCredentials{
+		Username: emulator.Username,
+		Password: emulator.Password,
+		Endpoint: strings.TrimPrefix(server.URL, "https://"),
+		Insecure: true,
+	}
+}
+
+// testAccRedfishProviderRetryConfig returns a provider block retrying failing requests maxRetries times, with the
+// short waits suiting emulated iDRACs
+func testAccRedfishProviderRetryConfig(maxRetries int) string {
+	return fmt.Sprintf(`
+		provider "redfish" {
+		  retry {
+			max_retries = %d
+			min_backoff = "10ms"
+			max_backoff = "100ms"
+		  }
+		}
+		`,
+		maxRetries,
+	)
+}
+
+// checkEmulatedResource checks a property of a resource of an emulated iDRAC. property is the path to the property
+// with / as separator, such as Attributes/NumLock, where indexes pick the elements of lists, such as Boot/BootOrder/0.
+func checkEmulatedResource(server *emulator.Server, uri string, property string, want interface{}) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		r := server.Resource(uri)
+		if r == nil {
+			return fmt.Errorf("resource %s does not exist", uri)
+		}
+		var got interface{} = r
+		for _, p := range strings.Split(property, "/") {
+			if list, ok := got.([]interface{}); ok {
+				i, err := strconv.Atoi(p)
+				if err != nil || i >= len(list) {
+					return fmt.Errorf("%s of %s has no element %s", property, uri, p)
+				}
+				got = list[i]
+				continue
+			}
+			object, _ := got.(map[string]interface{})
+			got = object[p]
+		}
+		if got != want {
+			return fmt.Errorf("%s of %s is %v, want %v", property, uri, got, want)
+		}
+		return nil
+	}
+}
+
+// checkEmulatedResourceOf checks a property of the resource of an emulated iDRAC managed by the Terraform resource
+// name, whose ID is the @odata.id of the resource
+func checkEmulatedResourceOf(server *emulator.Server, name string, property string, want interface{}) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource %s not found in the state", name)
+		}
+		return checkEmulatedResource(server, rs.Primary.ID, property, want)(s)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/dell/terraform-provider-redfish/common"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -14,17 +15,35 @@ import (
 	"log"
 	"net/url"
 	"path"
-	"strconv"
+	"sort"
 	"strings"
 	"time"
 )
 
 const (
+	// biosConfigJobType is the JobType of the Dell jobs applying bios settings
+	biosConfigJobType = "BIOSConfiguration"
+
 	defaultBiosConfigServerResetTimeout int = 120
 	defaultBiosConfigJobTimeout         int = 1200
 	intervalBiosConfigJobCheckTime      int = 10
+	// biosSettingsSettleTime is the time the BMC needs to show the new bios attributes once the job has completed
+	biosSettingsSettleTime = 30 * time.Second
+	// defaultBiosConfigTimeout bounds the whole update, reset of the server and bios config job included
+	defaultBiosConfigTimeout = 30 * time.Minute
+
+	// The values of on_destroy
+	biosDestroyForget          = "forget"
+	biosDestroyRestorePrevious = "restore_previous"
+	biosDestroyResetDefaults   = "reset_defaults"
 )
 
+// biosAttributePaths locates the attributes setting the properties of bios settings
+var biosAttributePaths = attributePaths{
+	"Attributes":                           "attributes",
+	"@Redfish.SettingsApplyTime/ApplyTime": "settings_apply_time",
+}
+
 func resourceRedfishBios() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceRedfishBiosUpdate,
@@ -33,46 +52,28 @@ func resourceRedfishBios() *schema.Resource {
 		DeleteContext: resourceRedfishBiosDelete,
 		Schema:        getResourceRedfishBiosSchema(),
 		CustomizeDiff: resourceRedfishBiosCustomizeDiff,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultBiosConfigTimeout),
+			Update: schema.DefaultTimeout(defaultBiosConfigTimeout),
+			Delete: schema.DefaultTimeout(defaultBiosConfigTimeout),
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceRedfishBiosImport,
+		},
 	}
 }
 
 func getResourceRedfishBiosSchema() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
-		"redfish_server": {
-			Type:        schema.TypeList,
-			Required:    true,
-			Description: "List of server BMCs and their respective user credentials",
-			Elem: &schema.Resource{
-				Schema: map[string]*schema.Schema{
-					"user": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Description: "User name for login",
-					},
-					"password": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Description: "User password for login",
-						Sensitive:   true,
-					},
-					"endpoint": {
-						Type:        schema.TypeString,
-						Required:    true,
-						Description: "Server BMC IP address or hostname",
-					},
-					"ssl_insecure": {
-						Type:        schema.TypeBool,
-						Optional:    true,
-						Description: "This field indicates whether the SSL/TLS certificate must be verified or not",
-					},
-				},
-			},
-		},
+		"redfish_alias":  redfishAliasSchema(),
+		"redfish_server": redfishServerSchema(),
+		"system_id":      systemIDSchema(true),
 		"attributes": {
-			Type:        schema.TypeMap,
-			Optional:    true,
-			Computed:    true,
-			Description: "Bios attributes",
+			Type:     schema.TypeMap,
+			Optional: true,
+			Computed: true,
+			Description: "Bios attributes. Their names and values are checked against the BIOS attribute registry of the " +
+				"system during the plan, and integer and boolean values are sent with their proper type.",
 			Elem: &schema.Schema{
 				Type: schema.TypeString,
 			},
@@ -80,17 +81,91 @@ func getResourceRedfishBiosSchema() map[string]*schema.Schema {
 		"settings_apply_time": {
 			Type:     schema.TypeString,
 			Optional: true,
-			Description: "The time when the BIOS settings can be applied. Applicable value is 'OnReset' only. " +
-				"In upcoming releases other apply time values will be supported. Default is \"OnReset\".",
+			Description: "The time when the BIOS settings are applied. Applicable values are 'OnReset', 'Immediate', " +
+				"'AtMaintenanceWindowStart' and 'InMaintenanceWindowOnReset', among the ones the system supports. " +
+				"With 'OnReset' the provider resets the system to apply the settings, unless stage_only is set. " +
+				"Settings staged by a previous apply are only applied by a later one on Dell iDRACs, whose job queue holds their job. " +
+				"With the maintenance window apply times the settings are staged, to be applied in the window given by " +
+				"maintenance_window_start_time and maintenance_window_duration. Default is \"OnReset\".",
 			ValidateFunc: validation.StringInSlice([]string{
 				string(redfishcommon.OnResetApplyTime),
+				string(redfishcommon.ImmediateApplyTime),
+				string(redfishcommon.AtMaintenanceWindowStartApplyTime),
+				string(redfishcommon.InMaintenanceWindowOnResetApplyTime),
 			}, false),
 			Default: string(redfishcommon.OnResetApplyTime),
 		},
+		"maintenance_window_start_time": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Description: "The start of the maintenance window the BIOS settings are applied in, as an RFC 3339 date " +
+				"and time such as 2023-11-04T22:00:00-05:00. Required with the maintenance window apply times.",
+			ValidateFunc: validation.IsRFC3339Time,
+			RequiredWith: []string{"maintenance_window_duration"},
+		},
+		"maintenance_window_duration": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Description:  "The duration in seconds of the maintenance window the BIOS settings are applied in.",
+			ValidateFunc: validation.IntAtLeast(1),
+			RequiredWith: []string{"maintenance_window_start_time"},
+		},
+		"stage_only": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Description: "Stage the BIOS settings applied 'OnReset' without resetting the system, for them to be applied " +
+				"by the next reset done outside of terraform. Settings already staged are not staged again. Default is false.",
+			Default: false,
+		},
+		"settings_pending": {
+			Type:        schema.TypeBool,
+			Computed:    true,
+			Description: "Whether BIOS settings are staged, waiting to be applied by a reset of the system or in a maintenance window.",
+		},
+		"pending_attributes": {
+			Type:     schema.TypeMap,
+			Computed: true,
+			Description: "Bios attributes staged with other values than their current ones, which a reset of the system " +
+				"or a maintenance window is to apply.",
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"cancel_pending_settings": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Description: "Cancel the pending BIOS settings which do not match the attributes of the resource, such as the " +
+				"ones left by a failed reset, deleting the scheduled bios config job. It requires a Dell iDRAC, whose job queue " +
+				"holds the job. Default is false.",
+			Default: false,
+		},
+		"on_destroy": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Description: "What destroying the resource does to the BIOS. 'forget' only removes the resource from the state, " +
+				"'restore_previous' applies again the values previous_attributes holds, and 'reset_defaults' runs the " +
+				"Bios.ResetBios action, which resets every attribute to its default value, and resets the system unless " +
+				"stage_only is set. Default is \"forget\".",
+			ValidateFunc: validation.StringInSlice([]string{
+				biosDestroyForget,
+				biosDestroyRestorePrevious,
+				biosDestroyResetDefaults,
+			}, false),
+			Default: biosDestroyForget,
+		},
+		"previous_attributes": {
+			Type:     schema.TypeMap,
+			Computed: true,
+			Description: "The values the attributes changed by the resource had before it first changed them, which " +
+				"on_destroy = \"restore_previous\" applies again.",
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
 		"reset_type": {
 			Type:     schema.TypeString,
 			Optional: true,
-			Description: "Reset type to apply on the computer system after the BIOS settings are applied. " +
+			Description: "Reset type to apply on the computer system for the BIOS settings applied 'OnReset' to take effect. " +
 				"Applicable values are 'ForceRestart', " +
 				"'GracefulRestart', and 'PowerCycle'." +
 				"Default = \"GracefulRestart\". ",
@@ -102,54 +177,125 @@ func getResourceRedfishBiosSchema() map[string]*schema.Schema {
 			Default: string(redfish.GracefulRestartResetType),
 		},
 		"reset_timeout": {
-			Type:        schema.TypeInt,
-			Optional:    true,
-			Description: "reset_timeout is the time in seconds that the provider waits for the server to be reset before timing out.",
-			Default:     defaultBiosConfigServerResetTimeout,
+			Type:     schema.TypeInt,
+			Optional: true,
+			Description: "reset_timeout is the time in seconds that the provider waits for the server to be reset before timing out. " +
+				"Deprecated, use the timeouts block instead.",
+			Default:    defaultBiosConfigServerResetTimeout,
+			Deprecated: "Use the create and update timeouts of the timeouts block instead",
 		},
 		"bios_job_timeout": {
-			Type:        schema.TypeInt,
-			Optional:    true,
-			Description: "bios_job_timeout is the time in seconds that the provider waits for the bios update job to be completed before timing out.",
-			Default:     defaultBiosConfigJobTimeout,
+			Type:     schema.TypeInt,
+			Optional: true,
+			Description: "bios_job_timeout is the time in seconds that the provider waits for the bios update job to be completed before timing out. " +
+				"Deprecated, use the timeouts block instead.",
+			Default:    defaultBiosConfigJobTimeout,
+			Deprecated: "Use the create and update timeouts of the timeouts block instead",
 		},
 	}
 }
 
-func resourceRedfishBiosCustomizeDiff(_ context.Context, diff *schema.ResourceDiff, v interface{}) error {
-	if diff.Id() == "" {
+// resourceRedfishBiosCustomizeDiff checks the configured attributes against the attribute registry of the bios, so
+// that unknown attributes and values the bios does not accept fail the plan. It keeps the attributes which are not
+// configured in the planned state, and clears the diff when the configured values only differ in how they are written,
+// like 060 and 60. The check is left to the apply when the server or the attributes are only known then.
+func resourceRedfishBiosCustomizeDiff(_ context.Context, diff *schema.ResourceDiff, m interface{}) error {
+	if err := checkBiosApplyTime(diff); err != nil {
+		return err
+	}
+	// Cancelling pending settings which do not match the attributes is a change, the pending attributes left being
+	// known once the settings are applied
+	if diff.Get("cancel_pending_settings").(bool) && diff.NewValueKnown("attributes") &&
+		len(stalePendingBiosAttributes(diff.Get("pending_attributes").(map[string]interface{}), diff.Get("attributes").(map[string]interface{}))) != 0 {
+		if err := diff.SetNewComputed("pending_attributes"); err != nil {
+			return err
+		}
+		if err := diff.SetNewComputed("settings_pending"); err != nil {
+			return err
+		}
+	}
+	if (diff.Id() != "" && !diff.HasChange("attributes")) || !diff.NewValueKnown("attributes") || !serverConfigKnown(diff) {
+		return nil
+	}
+	o, n := diff.GetChange("attributes")
+	oldAttribs := o.(map[string]interface{})
+	newAttribs := n.(map[string]interface{})
+	if len(newAttribs) == 0 {
 		return nil
 	}
 
-	if diff.HasChange("attributes") {
-		o, n := diff.GetChange("attributes")
-		oldAttribs := o.(map[string]interface{})
-		newAttribs := n.(map[string]interface{})
+	service, err := NewConfig(m.(*providerMeta).config, diff)
+	if err != nil {
+		return err
+	}
+	bios, err := getBiosResource(service, diff.Get("system_id").(string))
+	if err != nil {
+		return fmt.Errorf("error fetching bios resource: %w", err)
+	}
+	registry, err := common.GetAttributeRegistry(service, bios.AttributeRegistry)
+	if err != nil {
+		return fmt.Errorf("error fetching the bios attribute registry: %w", err)
+	}
 
-		sameAttribs := biosAttributesMatch(oldAttribs, newAttribs)
+	current := make(map[string]string)
+	for k, v := range oldAttribs {
+		current[k] = v.(string)
+	}
+	attrsToPatch, err := biosAttributesToPatch(registry, newAttribs, current)
+	if err != nil {
+		return err
+	}
 
-		if sameAttribs {
-			log.Printf("[DEBUG] Bios attributes have not changed. clearing diff")
-			if err := diff.Clear("attributes"); err != nil {
-				return err
-			}
-		} else {
-			// Update the attributes value pairs
-			for k, v := range newAttribs {
-				oldAttribs[k] = v
-			}
+	if diff.Id() == "" {
+		return nil
+	}
+	if len(attrsToPatch) == 0 {
+		log.Printf("[DEBUG] Bios attributes have not changed. clearing diff")
+		return diff.Clear("attributes")
+	}
+	// Update the attributes value pairs
+	for k, v := range newAttribs {
+		oldAttribs[k] = v
+	}
+	if err := diff.SetNew("attributes", oldAttribs); err != nil {
+		return err
+	}
+	// The settings are pending until applied, whenever that is
+	if err := diff.SetNewComputed("pending_attributes"); err != nil {
+		return err
+	}
+	if err := diff.SetNewComputed("previous_attributes"); err != nil {
+		return err
+	}
+	return diff.SetNewComputed("settings_pending")
+}
 
-			if err := diff.SetNew("attributes", oldAttribs); err != nil {
-				return err
-			}
-		}
+// checkBiosApplyTime checks that a maintenance window is given with the apply times which need one and only then, and
+// that stage_only is only set with the apply time the provider resets the system for
+func checkBiosApplyTime(diff *schema.ResourceDiff) error {
+	if !diff.NewValueKnown("settings_apply_time") || !diff.NewValueKnown("maintenance_window_start_time") {
+		return nil
 	}
+	applyTime := diff.Get("settings_apply_time").(string)
+	_, window := diff.GetOk("maintenance_window_start_time")
+	inWindow := applyTime == string(redfishcommon.AtMaintenanceWindowStartApplyTime) ||
+		applyTime == string(redfishcommon.InMaintenanceWindowOnResetApplyTime)
 
+	if inWindow && !window {
+		return fmt.Errorf("maintenance_window_start_time and maintenance_window_duration are required when settings_apply_time is %s", applyTime)
+	}
+	if !inWindow && window {
+		return fmt.Errorf("maintenance_window_start_time can only be set when settings_apply_time is %s or %s",
+			redfishcommon.AtMaintenanceWindowStartApplyTime, redfishcommon.InMaintenanceWindowOnResetApplyTime)
+	}
+	if diff.Get("stage_only").(bool) && applyTime != string(redfishcommon.OnResetApplyTime) {
+		return fmt.Errorf("stage_only can only be set when settings_apply_time is %s", redfishcommon.OnResetApplyTime)
+	}
 	return nil
 }
 
 func resourceRedfishBiosRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	service, err := NewConfig(m.(*schema.ResourceData), d)
+	service, err := NewConfig(m.(*providerMeta).config, d)
 	if err != nil {
 		return diag.Errorf(err.Error())
 	}
@@ -157,32 +303,110 @@ func resourceRedfishBiosRead(ctx context.Context, d *schema.ResourceData, m inte
 }
 
 func resourceRedfishBiosUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	service, err := NewConfig(m.(*schema.ResourceData), d)
+	service, err := NewConfig(m.(*providerMeta).config, d)
 	if err != nil {
 		return diag.Errorf(err.Error())
 	}
-	return updateRedfishBiosResource(service, d)
+	return updateRedfishBiosResource(ctx, service, d, m)
 }
 
+// resourceRedfishBiosDelete reverts the BIOS as on_destroy asks, before removing the resource from the state
 func resourceRedfishBiosDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	var diags diag.Diagnostics
+	onDestroy := d.Get("on_destroy").(string)
+	if onDestroy == biosDestroyForget {
+		d.SetId("")
+		return nil
+	}
+
+	service, err := NewConfig(m.(*providerMeta).config, d)
+	if err != nil {
+		return diag.Errorf(err.Error())
+	}
+
+	redfishMutexKV.Lock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
+	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
+
+	bios, err := getBiosResource(service, d.Get("system_id").(string))
+	if err != nil {
+		return redfishDiagnostics("error fetching bios resource", err, nil)
+	}
+
+	if onDestroy == biosDestroyRestorePrevious {
+		diags := restorePreviousBiosAttributes(ctx, service, d, m.(*providerMeta).waits, bios)
+		if diags.HasError() {
+			return diags
+		}
+		d.SetId("")
+		return diags
+	}
+
+	log.Printf("[DEBUG] %s: Resetting the bios attributes to their defaults", d.Id())
+	if err := bios.ResetBios(); err != nil {
+		return redfishDiagnostics("error resetting the bios to its defaults", err, nil)
+	}
+	// The bios resets its attributes while the system starts again
+	if !d.Get("stage_only").(bool) {
+		resetTimeout := deprecatedTimeout(d, "reset_timeout", defaultBiosConfigServerResetTimeout)
+		_, diags := PowerOperation(ctx, d.Get("reset_type").(string), resetTimeout, m.(*providerMeta).waits.biosConfigJobCheck, service, d.Get("system_id").(string))
+		if diags.HasError() {
+			return diags
+		}
+		if err := common.SleepWithContext(ctx, m.(*providerMeta).waits.biosSettingsSettle); err != nil {
+			return diag.Errorf("Error waiting for the bios attributes to be reset: %s", err)
+		}
+	}
 	d.SetId("")
-	return diags
+	return nil
+}
+
+// restorePreviousBiosAttributes applies again the values of previous_attributes which the attributes no longer have
+func restorePreviousBiosAttributes(ctx context.Context, service *gofish.Service, d *schema.ResourceData, waits waitTimes, bios *redfish.Bios) diag.Diagnostics {
+	attributes := make(map[string]string)
+	if err := copyBiosAttributes(bios, attributes); err != nil {
+		return diag.Errorf("error fetching bios attributes: %s", err)
+	}
+	registry, err := common.GetAttributeRegistry(service, bios.AttributeRegistry)
+	if err != nil {
+		return redfishDiagnostics("error fetching the bios attribute registry", err, nil)
+	}
+
+	previous := d.Get("previous_attributes").(map[string]interface{})
+	attrsPayload, err := biosAttributesToPatch(registry, previous, attributes)
+	if err != nil {
+		return diag.Errorf("error getting BIOS attributes to restore: %s", err)
+	}
+	log.Printf("[DEBUG] %s: Restoring the bios attributes %v", d.Id(), attrsPayload)
+	return applyBiosAttributes(ctx, service, d, waits, bios, attrsPayload, previous)
+}
+
+// resourceRedfishBiosImport imports the BIOS settings of a system from an ID such as
+// https://my-server-1.myawesomecompany.org|/redfish/v1/Systems/System.Embedded.1/Bios
+func resourceRedfishBiosImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	odataID, err := parseImportID(d, m)
+	if err != nil {
+		return nil, err
+	}
+	d.SetId(odataID)
+	if err := d.Set("system_id", odataIDMember(odataID, "Systems")); err != nil {
+		return nil, err
+	}
+	if err := setImportDefaults(d, getResourceRedfishBiosSchema()); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
 }
 
-func updateRedfishBiosResource(service *gofish.Service, d *schema.ResourceData) diag.Diagnostics {
+func updateRedfishBiosResource(ctx context.Context, service *gofish.Service, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] Beginning update")
 	var diags diag.Diagnostics
 
 	// Lock the mutex to avoid race conditions with other resources
-	redfishMutexKV.Lock(getRedfishServerEndpoint(d))
-	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(d))
+	redfishMutexKV.Lock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
+	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
 
-	resetType := d.Get("reset_type")
-
-	bios, err := getBiosResource(service)
+	bios, err := getBiosResource(service, d.Get("system_id").(string))
 	if err != nil {
-		return diag.Errorf("error fetching bios resource: %s", err)
+		return redfishDiagnostics("error fetching bios resource", err, nil)
 	}
 
 	attributes := make(map[string]string)
@@ -191,39 +415,35 @@ func updateRedfishBiosResource(service *gofish.Service, d *schema.ResourceData)
 		return diag.Errorf("error fetching bios attributes: %s", err)
 	}
 
-	attrsPayload, err := getBiosAttrsToPatch(d, attributes)
+	registry, err := common.GetAttributeRegistry(service, bios.AttributeRegistry)
 	if err != nil {
-		return diag.Errorf("error getting BIOS attributes to patch: %s", err)
+		return redfishDiagnostics("error fetching the bios attribute registry", err, nil)
 	}
 
-	resetTimeout := d.Get("reset_timeout")
-
-	biosConfigJobTimeout := d.Get("bios_job_timeout")
-
-	log.Printf("[DEBUG] resetTimeout is set to %d  and Bios Config Job timeout is set to %d", resetTimeout.(int), biosConfigJobTimeout.(int))
+	attrsPayload, err := biosAttributesToPatch(registry, d.Get("attributes").(map[string]interface{}), attributes)
+	if err != nil {
+		return diag.Errorf("error getting BIOS attributes to patch: %s", err)
+	}
 
-	var biosTaskURI string
-	if len(attrsPayload) != 0 {
-		biosTaskURI, err = patchBiosAttributes(d, bios, attrsPayload)
-		if err != nil {
-			return diag.Errorf("error updating bios attributes: %s", err)
+	// The values the attributes had before the resource first changed them are the ones restored on destroy. The plan
+	// leaves previous_attributes unknown, so they are taken from the state.
+	previousState, _ := d.GetChange("previous_attributes")
+	previous := make(map[string]interface{})
+	for k, v := range previousState.(map[string]interface{}) {
+		previous[k] = v
+	}
+	for k := range attrsPayload {
+		if _, ok := previous[k]; !ok {
+			previous[k] = attributes[k]
 		}
+	}
 
-		// reboot the server
-		_, diags := PowerOperation(resetType.(string), resetTimeout.(int), intervalBiosConfigJobCheckTime, service)
-		if diags.HasError() {
-			// TODO: handle this scenario
-			return diag.Errorf("there was an issue restarting the server")
-		}
+	if err := d.Set("previous_attributes", previous); err != nil {
+		return diag.Errorf("error setting previous bios attributes: %s", err)
+	}
 
-		// wait for the bios config job to finish
-		err = common.WaitForJobToFinish(service, biosTaskURI, intervalBiosConfigJobCheckTime, biosConfigJobTimeout.(int))
-		if err != nil {
-			return diag.Errorf("Error waiting for Bios config monitor task (%s) to be completed: %s", biosTaskURI, err)
-		}
-		time.Sleep(30 * time.Second)
-	} else {
-		log.Printf("[DEBUG] BIOS attributes are already set")
+	if diags := applyBiosAttributes(ctx, service, d, m.(*providerMeta).waits, bios, attrsPayload, d.Get("attributes").(map[string]interface{})); diags.HasError() {
+		return diags
 	}
 
 	if err = d.Set("attributes", attributes); err != nil {
@@ -238,14 +458,94 @@ func updateRedfishBiosResource(service *gofish.Service, d *schema.ResourceData)
 	return diags
 }
 
+// applyBiosAttributes stages attrsPayload in the bios settings, unless they are already, and resets the system to
+// apply them as settings_apply_time and stage_only ask. Pending settings which do not match the configured attributes
+// are cancelled first when cancel_pending_settings is set.
+func applyBiosAttributes(ctx context.Context, service *gofish.Service, d *schema.ResourceData, waits waitTimes, bios *redfish.Bios, attrsPayload map[string]interface{}, configured map[string]interface{}) diag.Diagnostics {
+	resetTimeout := deprecatedTimeout(d, "reset_timeout", defaultBiosConfigServerResetTimeout)
+
+	biosConfigJobTimeout := deprecatedTimeout(d, "bios_job_timeout", defaultBiosConfigJobTimeout)
+
+	log.Printf("[DEBUG] resetTimeout is set to %d  and Bios Config Job timeout is set to %d", resetTimeout, biosConfigJobTimeout)
+
+	settings, err := getBiosSettings(bios)
+	if err != nil {
+		return redfishDiagnostics("error fetching bios settings", err, nil)
+	}
+	pending := make(map[string]interface{})
+	for k, v := range settings.Attributes {
+		pending[k] = fmt.Sprintf("%v", v)
+	}
+	if d.Get("cancel_pending_settings").(bool) && len(stalePendingBiosAttributes(pending, configured)) != 0 {
+		if err := cancelPendingBiosSettings(service, d.Get("system_id").(string), bios); err != nil {
+			return redfishDiagnostics("error cancelling pending bios settings", err, nil)
+		}
+		pending = map[string]interface{}{}
+	}
+
+	// Values staged by a previous apply which has not reset the system are not staged again
+	staged := len(attrsPayload) != 0
+	for k, v := range attrsPayload {
+		staged = staged && pending[k] == fmt.Sprintf("%v", v)
+	}
+
+	applyTime := d.Get("settings_apply_time").(string)
+	resetSystem := applyTime == string(redfishcommon.OnResetApplyTime) && !d.Get("stage_only").(bool)
+
+	var biosTaskURI string
+	apply := len(attrsPayload) != 0
+	if staged {
+		log.Printf("[DEBUG] BIOS attributes are already staged")
+		// The job of a previous apply whose reset failed still has to be run
+		if biosTaskURI, err = getScheduledBiosJob(service, d.Get("system_id").(string)); err != nil {
+			return redfishDiagnostics("error fetching the scheduled bios config job", err, nil)
+		}
+		apply = biosTaskURI != ""
+	} else if apply {
+		biosTaskURI, err = patchBiosAttributes(d, bios, attrsPayload)
+		if err != nil {
+			return redfishDiagnostics("error updating bios attributes", err, biosAttributePaths)
+		}
+	}
+
+	if apply {
+		if resetSystem {
+			// reboot the server
+			_, diags := PowerOperation(ctx, d.Get("reset_type").(string), resetTimeout, waits.biosConfigJobCheck, service, d.Get("system_id").(string))
+			if diags.HasError() {
+				// TODO: handle this scenario
+				return diags
+			}
+		}
+
+		// Settings applied in a maintenance window or by a reset done outside of terraform are left to their job
+		if !resetSystem && applyTime != string(redfishcommon.ImmediateApplyTime) {
+			log.Printf("[DEBUG] BIOS settings staged, the config job %s applies them %s", biosTaskURI, applyTime)
+		} else {
+			// wait for the bios config job to finish
+			err = common.WaitForJobToFinish(ctx, service, biosTaskURI, waits.biosConfigJobCheck, biosConfigJobTimeout)
+			if err != nil {
+				return redfishDiagnostics(fmt.Sprintf("Error waiting for Bios config monitor task (%s) to be completed", biosTaskURI), err, biosAttributePaths)
+			}
+			if err := common.SleepWithContext(ctx, waits.biosSettingsSettle); err != nil {
+				return diag.Errorf("Error waiting for the bios attributes to be updated: %s", err)
+			}
+		}
+	} else if !staged {
+		log.Printf("[DEBUG] BIOS attributes are already set")
+	}
+
+	return nil
+}
+
 func readRedfishBiosResource(service *gofish.Service, d *schema.ResourceData) diag.Diagnostics {
 
 	log.Printf("[DEBUG] %s: Beginning read", d.Id())
 	var diags diag.Diagnostics
 
-	bios, err := getBiosResource(service)
+	bios, err := getBiosResource(service, d.Get("system_id").(string))
 	if err != nil {
-		return diag.Errorf("error fetching BIOS resource: %s", err)
+		return redfishDiagnostics("error fetching BIOS resource", err, nil)
 	}
 
 	attributes := make(map[string]string)
@@ -258,6 +558,43 @@ func readRedfishBiosResource(service *gofish.Service, d *schema.ResourceData) di
 		return diag.Errorf("error setting bios attributes: %s", err)
 	}
 
+	settings, err := getBiosSettings(bios)
+	if err != nil {
+		return redfishDiagnostics("error fetching BIOS settings", err, nil)
+	}
+	pending := make(map[string]string)
+	if err := copyBiosAttributes(settings, pending); err != nil {
+		return diag.Errorf("error fetching pending BIOS attributes: %s", err)
+	}
+	for k, v := range pending {
+		if attributes[k] == v {
+			delete(pending, k)
+		}
+	}
+	if err := d.Set("pending_attributes", pending); err != nil {
+		return diag.Errorf("error setting pending bios attributes: %s", err)
+	}
+	if err := d.Set("settings_pending", len(pending) != 0); err != nil {
+		return diag.Errorf("error setting settings_pending: %s", err)
+	}
+
+	// Settings staged on purpose are applied later, the others should have been applied already
+	if len(pending) != 0 && d.Get("settings_apply_time") == string(redfishcommon.OnResetApplyTime) && !d.Get("stage_only").(bool) {
+		names := make([]string, 0, len(pending))
+		for k := range pending {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "BIOS settings are pending",
+			Detail: fmt.Sprintf("The BIOS attributes %s are staged with other values than their current ones, the next "+
+				"reset of the system applies them. Set cancel_pending_settings to cancel the ones which do not match "+
+				"the attributes of the resource.", strings.Join(names, ", ")),
+			AttributePath: cty.GetAttrPath("pending_attributes"),
+		})
+	}
+
 	log.Printf("[DEBUG] %s: Read finished successfully", d.Id())
 
 	return diags
@@ -301,16 +638,17 @@ func patchBiosAttributes(d *schema.ResourceData, bios *redfish.Bios, attributes
 			return "", err
 		}
 
-		payload["@Redfish.SettingsApplyTime"] = map[string]interface{}{
+		settingsApplyTimePayload := map[string]interface{}{
 			"ApplyTime": settingsApplyTime.(string),
 		}
+		if startTime, ok := d.GetOk("maintenance_window_start_time"); ok {
+			settingsApplyTimePayload["MaintenanceWindowStartTime"] = startTime.(string)
+			settingsApplyTimePayload["MaintenanceWindowDurationInSeconds"] = d.Get("maintenance_window_duration").(int)
+		}
+		payload["@Redfish.SettingsApplyTime"] = settingsApplyTimePayload
 	}
 
-	oDataURI, err := url.Parse(bios.ODataID)
-	oDataURI.Path = path.Join(oDataURI.Path, "Settings")
-	settingsObjectURI := oDataURI.String()
-
-	resp, err := bios.GetClient().Patch(settingsObjectURI, payload)
+	resp, err := bios.GetClient().Patch(biosSettingsURI(bios), payload)
 	if err != nil {
 		log.Printf("[DEBUG] error sending the patch request: %s", err)
 		return "", err
@@ -328,9 +666,84 @@ func patchBiosAttributes(d *schema.ResourceData, bios *redfish.Bios, attributes
 	return "", nil
 }
 
-func getBiosResource(service *gofish.Service) (*redfish.Bios, error) {
+// biosSettingsURI returns the URI of the settings resource of the bios, where changes to its attributes are staged
+func biosSettingsURI(bios *redfish.Bios) string {
+	settingsURI, _ := url.Parse(bios.ODataID)
+	settingsURI.Path = path.Join(settingsURI.Path, "Settings")
+	return settingsURI.String()
+}
+
+// getBiosSettings fetches the settings resource of the bios, whose attributes are the ones staged to be applied by a
+// bios config job
+func getBiosSettings(bios *redfish.Bios) (*redfish.Bios, error) {
+	return redfish.GetBios(bios.GetClient(), biosSettingsURI(bios))
+}
+
+// stalePendingBiosAttributes returns the names of the pending attributes whose staged value is not the one of the
+// configured attributes
+func stalePendingBiosAttributes(pending map[string]interface{}, configured map[string]interface{}) []string {
+	var stale []string
+	for k, v := range pending {
+		if configured[k] != v {
+			stale = append(stale, k)
+		}
+	}
+	return stale
+}
+
+// cancelPendingBiosSettings deletes the scheduled bios config jobs, and clears the bios settings they were to apply
+// with the DellManager.ClearPending action. Both are only found on Dell iDRACs.
+func cancelPendingBiosSettings(service *gofish.Service, systemID string, bios *redfish.Bios) error {
+	jobsURI, err := getDellJobQueue(service, systemID)
+	if err != nil {
+		return err
+	}
+	if jobsURI == "" {
+		return errors.New("cancel_pending_settings requires a Dell job queue, which the manager of the system does not have")
+	}
+	jobs, err := common.ListDellJobs(service, jobsURI)
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		if job.JobType == biosConfigJobType && job.JobState == "Scheduled" {
+			log.Printf("[DEBUG] Deleting the bios config job %s", job.ID)
+			if err := common.DeleteDellJob(service, jobsURI, job.ID); err != nil {
+				return err
+			}
+		}
+	}
 
-	system, err := getSystemResource(service)
+	resp, err := bios.GetClient().Post(biosSettingsURI(bios)+"/Actions/Oem/DellManager.ClearPending", map[string]interface{}{})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// getScheduledBiosJob returns the URI of the task of the bios config job waiting for the system to be reset, or an
+// empty string if there is none. Only Dell iDRACs have a job queue to find it in, there is none on the other systems.
+func getScheduledBiosJob(service *gofish.Service, systemID string) (string, error) {
+	jobsURI, err := getDellJobQueue(service, systemID)
+	if err != nil || jobsURI == "" {
+		return "", err
+	}
+	jobs, err := common.ListDellJobs(service, jobsURI)
+	if err != nil {
+		return "", err
+	}
+	for _, job := range jobs {
+		if job.JobType == biosConfigJobType && job.JobState == "Scheduled" {
+			return getDellJobTask(service, job.ID)
+		}
+	}
+	return "", nil
+}
+
+func getBiosResource(service *gofish.Service, systemID string) (*redfish.Bios, error) {
+
+	system, err := getSystemResource(service, systemID)
 	if err != nil {
 		log.Printf("[ERROR]: Failed to get system resource: %s", err)
 		return nil, err
@@ -345,72 +758,36 @@ func getBiosResource(service *gofish.Service) (*redfish.Bios, error) {
 	return bios, nil
 }
 
-func getBiosAttrsToPatch(d *schema.ResourceData, attributes map[string]string) (map[string]interface{}, error) {
-
-	attrs := make(map[string]interface{})
+// biosAttributesToPatch returns the configured attributes whose value differs from the current one, converted to the
+// type the registry gives them. It fails listing every changed attribute which is not in the registry, cannot be
+// written, or is given a value the registry does not allow.
+func biosAttributesToPatch(registry *common.AttributeRegistry, configured map[string]interface{}, current map[string]string) (map[string]interface{}, error) {
 	attrsToPatch := make(map[string]interface{})
+	var errs []string
 
-	if v, ok := d.GetOk("attributes"); ok {
-		attrs = v.(map[string]interface{})
-	}
-
-	for key, newVal := range attrs {
-		if oldVal, ok := attributes[key]; ok {
-			// check if the original value is an integer
-			// if yes, then we need to convert accordingly
-			if intOldVal, err := strconv.Atoi(attributes[key]); err == nil {
-				intNewVal, err := strconv.Atoi(newVal.(string))
-				if err != nil {
-					return attrsToPatch, err
-				}
-
-				// Add to patch list if attribute value has changed
-				if intNewVal != intOldVal {
-					attrsToPatch[key] = intNewVal
-				}
-			} else {
-				if newVal != oldVal {
-					attrsToPatch[key] = newVal
-				}
-			}
-
-		} else {
-			err := fmt.Errorf("BIOS attribute %s not found", key)
-			return attrsToPatch, err
+	for key, newVal := range configured {
+		if oldVal, ok := current[key]; ok && newVal == oldVal {
+			continue
+		}
+		attribute := registry.Attribute(key)
+		if attribute == nil {
+			errs = append(errs, fmt.Sprintf("BIOS attribute %s not found in the attribute registry %s", key, registry.ID))
+			continue
+		}
+		value, err := attribute.Convert(newVal.(string))
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		// Current values are written the way copyBiosAttributes does, 060 being the same as 60 for integers
+		if oldVal, ok := current[key]; !ok || fmt.Sprintf("%v", value) != oldVal {
+			attrsToPatch[key] = value
 		}
 	}
 
-	return attrsToPatch, nil
-}
-
-func biosAttributesMatch(oldAttribs, newAttribs map[string]interface{}) bool {
-	log.Printf("[DEBUG] Begin biosAttributesMatch")
-
-	for key, newVal := range newAttribs {
-		log.Printf("[DEBUG] attribute: %v, newVal: %v", key, newVal)
-		if oldVal, ok := oldAttribs[key]; ok {
-			log.Printf("[DEBUG] found attribute: %v, oldVal: %v", key, oldVal)
-			// check if the original value is an integer
-			// if yes, then we need to convert accordingly
-			if intOldVal, err := strconv.Atoi(oldVal.(string)); err == nil {
-				intNewVal, err := strconv.Atoi(newVal.(string))
-				if err != nil {
-					return false
-				}
-
-				if intNewVal != intOldVal {
-					return false
-				}
-			} else {
-				if newVal != oldVal {
-					return false
-				}
-			}
-		} else {
-			// attribute not found in the current state
-			log.Printf("[DEBUG] attribute %v not found in the current state", key)
-			return false
-		}
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return nil, fmt.Errorf("invalid BIOS attributes:\n%s", strings.Join(errs, "\n"))
 	}
-	return true
+	return attrsToPatch, nil
 }
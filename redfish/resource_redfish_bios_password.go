@@ -0,0 +1,293 @@
+package redfish
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"path"
+
+	"github.com/dell/terraform-provider-redfish/common"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/stmcginnis/gofish"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// biosPasswordAttributePaths locates the attributes setting the parameters of the Bios.ChangePassword action
+var biosPasswordAttributePaths = attributePaths{
+	"PasswordName": "password_name",
+	"OldPassword":  "old_password",
+	"NewPassword":  "new_password",
+}
+
+func resourceRedfishBiosPassword() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceRedfishBiosPasswordCreate,
+		ReadContext:   resourceRedfishBiosPasswordRead,
+		UpdateContext: resourceRedfishBiosPasswordUpdate,
+		DeleteContext: resourceRedfishBiosPasswordDelete,
+		Schema:        getResourceRedfishBiosPasswordSchema(),
+		CustomizeDiff: resourceRedfishBiosPasswordCustomizeDiff,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultBiosConfigTimeout),
+			Update: schema.DefaultTimeout(defaultBiosConfigTimeout),
+		},
+	}
+}
+
+func getResourceRedfishBiosPasswordSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"redfish_alias":  redfishAliasSchema(),
+		"redfish_server": redfishServerSchema(),
+		"system_id":      systemIDSchema(true),
+		"password_name": {
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+			Description: "The name of the BIOS password to set, one of the Password attributes of the BIOS attribute " +
+				"registry, such as 'SetupPassword' for the BIOS setup password or 'SysPassword' for the system boot " +
+				"password on iDRAC.",
+			ValidateFunc: validation.StringIsNotEmpty,
+		},
+		"old_password": {
+			Type:      schema.TypeString,
+			Optional:  true,
+			Sensitive: true,
+			Description: "The password set before the resource is created, if any. Later changes of new_password are " +
+				"made with the password the resource last set.",
+		},
+		"new_password": {
+			Type:      schema.TypeString,
+			Required:  true,
+			Sensitive: true,
+			Description: "The password to set. Changing it rotates the password. It is checked against the length and " +
+				"the expression the BIOS attribute registry gives the password during the plan.",
+		},
+		"stage_only": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Description: "Stage the password in a BIOS config job without resetting the system, for it to be set at the " +
+				"next reset done outside of Terraform. new_password cannot be changed again before. Default is false.",
+			Default: false,
+		},
+		"reset_type": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Description: "Reset type to apply on the computer system for the BIOS config job to set the password. " +
+				"Applicable values are 'ForceRestart', 'GracefulRestart', and 'PowerCycle'. " +
+				"Default = \"GracefulRestart\". ",
+			ValidateFunc: validation.StringInSlice([]string{
+				string(redfish.ForceRestartResetType),
+				string(redfish.GracefulRestartResetType),
+				string(redfish.PowerCycleResetType),
+			}, false),
+			Default: string(redfish.GracefulRestartResetType),
+		},
+	}
+}
+
+// resourceRedfishBiosPasswordCustomizeDiff checks the password name and the new password against the attribute
+// registry of the bios, so that a password the bios would refuse fails the plan
+func resourceRedfishBiosPasswordCustomizeDiff(_ context.Context, diff *schema.ResourceDiff, m interface{}) error {
+	if !diff.HasChanges("password_name", "new_password") || !diff.NewValueKnown("password_name") ||
+		!diff.NewValueKnown("new_password") || !serverConfigKnown(diff) {
+		return nil
+	}
+
+	service, err := NewConfig(m.(*providerMeta).config, diff)
+	if err != nil {
+		return err
+	}
+	bios, err := getBiosResource(service, diff.Get("system_id").(string))
+	if err != nil {
+		return fmt.Errorf("error fetching bios resource: %w", err)
+	}
+	registry, err := common.GetAttributeRegistry(service, bios.AttributeRegistry)
+	if err != nil {
+		return fmt.Errorf("error fetching the bios attribute registry: %w", err)
+	}
+
+	name := diff.Get("password_name").(string)
+	attribute := registry.Attribute(name)
+	if attribute == nil || attribute.Type != "Password" {
+		return fmt.Errorf("%s is not a password of the attribute registry %s", name, registry.ID)
+	}
+	// The errors of Password attributes only give the constraints the password breaks, never the password
+	if _, err := attribute.Convert(diff.Get("new_password").(string)); err != nil {
+		return fmt.Errorf("invalid new_password: %w", err)
+	}
+	return nil
+}
+
+func resourceRedfishBiosPasswordCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	service, err := NewConfig(m.(*providerMeta).config, d)
+	if err != nil {
+		return diag.Errorf(err.Error())
+	}
+
+	// Lock the mutex to avoid race conditions with other resources
+	redfishMutexKV.Lock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
+	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
+
+	bios, err := getBiosResource(service, d.Get("system_id").(string))
+	if err != nil {
+		return redfishDiagnostics("error fetching bios resource", err, nil)
+	}
+
+	jobsURI, err := getBiosPasswordJobQueue(service, d.Get("system_id").(string))
+	if err != nil {
+		return redfishDiagnostics("error fetching the job queue of the manager", err, nil)
+	}
+
+	name := d.Get("password_name").(string)
+	if err := changeBiosPassword(bios, name, d.Get("old_password").(string), d.Get("new_password").(string)); err != nil {
+		return redfishDiagnostics("error setting the bios password", err, biosPasswordAttributePaths)
+	}
+
+	// The password is changed, a failure to apply it taints the resource
+	d.SetId(path.Join(bios.ODataID, name))
+	if diags := applyBiosPassword(ctx, service, d, m.(*providerMeta).waits, bios, jobsURI); diags.HasError() {
+		return diags
+	}
+	return resourceRedfishBiosPasswordRead(ctx, d, m)
+}
+
+// resourceRedfishBiosPasswordRead checks that the bios can still be reached. Passwords are never shown, so the ones
+// in the state are kept.
+func resourceRedfishBiosPasswordRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	service, err := NewConfig(m.(*providerMeta).config, d)
+	if err != nil {
+		return diag.Errorf(err.Error())
+	}
+	bios, err := getBiosResource(service, d.Get("system_id").(string))
+	if err != nil {
+		return redfishDiagnostics("error fetching bios resource", err, nil)
+	}
+	if err := d.Set("system_id", odataIDMember(bios.ODataID, "Systems")); err != nil {
+		return diag.Errorf("error setting system_id: %s", err)
+	}
+	return nil
+}
+
+func resourceRedfishBiosPasswordUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if !d.HasChange("new_password") {
+		return resourceRedfishBiosPasswordRead(ctx, d, m)
+	}
+
+	service, err := NewConfig(m.(*providerMeta).config, d)
+	if err != nil {
+		return diag.Errorf(err.Error())
+	}
+
+	// Lock the mutex to avoid race conditions with other resources
+	redfishMutexKV.Lock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
+	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
+
+	bios, err := getBiosResource(service, d.Get("system_id").(string))
+	if err != nil {
+		return redfishDiagnostics("error fetching bios resource", err, nil)
+	}
+
+	jobsURI, err := getBiosPasswordJobQueue(service, d.Get("system_id").(string))
+	if err != nil {
+		return redfishDiagnostics("error fetching the job queue of the manager", err, nil)
+	}
+
+	// The password set last is the one to change
+	o, n := d.GetChange("new_password")
+	if err := changeBiosPassword(bios, d.Get("password_name").(string), o.(string), n.(string)); err != nil {
+		// The state keeps the password which is still set, for the next apply to change it
+		d.Partial(true)
+		return redfishDiagnostics("error rotating the bios password", err, biosPasswordAttributePaths)
+	}
+	if diags := applyBiosPassword(ctx, service, d, m.(*providerMeta).waits, bios, jobsURI); diags.HasError() {
+		// The scheduled job still applies the new password, the next apply resets the system for it
+		d.Partial(true)
+		return diags
+	}
+	return resourceRedfishBiosPasswordRead(ctx, d, m)
+}
+
+// resourceRedfishBiosPasswordDelete removes the resource from the state, leaving the password set
+func resourceRedfishBiosPasswordDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	d.SetId("")
+	return nil
+}
+
+// changeBiosPassword runs the Bios.ChangePassword action, at the target the bios gives it. Unlike the ChangePassword
+// method of gofish, it allows an empty old password, which sets a password on a bios without one.
+func changeBiosPassword(bios *redfish.Bios, name, oldPassword, newPassword string) error {
+	var actions struct {
+		Actions struct {
+			ChangePassword struct {
+				Target string
+			} `json:"#Bios.ChangePassword"`
+		}
+	}
+	if err := getJSON(bios.GetClient(), bios.ODataID, &actions); err != nil {
+		return err
+	}
+	target := actions.Actions.ChangePassword.Target
+	if target == "" {
+		return fmt.Errorf("the bios %s has no Bios.ChangePassword action", bios.ODataID)
+	}
+
+	log.Printf("[DEBUG] Changing the bios password %s", name)
+	resp, err := bios.GetClient().Post(target, map[string]interface{}{
+		"PasswordName": name,
+		"OldPassword":  oldPassword,
+		"NewPassword":  newPassword,
+	})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// getBiosPasswordJobQueue returns the job queue of the manager of the system, where the bios config job setting the
+// password is created. Only Dell iDRACs have one, the other BMCs are refused before the password is changed.
+func getBiosPasswordJobQueue(service *gofish.Service, systemID string) (string, error) {
+	jobsURI, err := getDellJobQueue(service, systemID)
+	if err != nil {
+		return "", err
+	}
+	if jobsURI == "" {
+		return "", errors.New("OnReset staging requires a Dell job queue, which the manager of the system does not have")
+	}
+	return jobsURI, nil
+}
+
+// applyBiosPassword has a bios config job of the job queue at jobsURI set the password the Bios.ChangePassword action
+// left pending, and resets the system for the job to run unless stage_only is set. A bios config job already
+// scheduled, like the one of settings staged by the bios resource, sets it as well.
+func applyBiosPassword(ctx context.Context, service *gofish.Service, d *schema.ResourceData, waits waitTimes, bios *redfish.Bios, jobsURI string) diag.Diagnostics {
+	jobURI, err := getScheduledBiosJob(service, d.Get("system_id").(string))
+	if err != nil {
+		return redfishDiagnostics("error fetching the scheduled bios config job", err, nil)
+	}
+	if jobURI == "" {
+		jobID, err := common.CreateDellJob(service, jobsURI, biosSettingsURI(bios))
+		if err != nil {
+			return redfishDiagnostics("error creating the bios config job setting the password", err, nil)
+		}
+		if jobURI, err = getDellJobTask(service, jobID); err != nil {
+			return redfishDiagnostics("error fetching the task of the bios config job setting the password", err, nil)
+		}
+	}
+
+	if d.Get("stage_only").(bool) {
+		log.Printf("[DEBUG] BIOS password staged, the config job %s sets it at the next reset", jobURI)
+		return nil
+	}
+	_, diags := PowerOperation(ctx, d.Get("reset_type").(string), 0, waits.biosConfigJobCheck, service, d.Get("system_id").(string))
+	if diags.HasError() {
+		return diags
+	}
+	if err := common.WaitForJobToFinish(ctx, service, jobURI, waits.biosConfigJobCheck, 0); err != nil {
+		return redfishDiagnostics(fmt.Sprintf("Error waiting for the bios config job (%s) to be completed", jobURI), err, nil)
+	}
+	return nil
+}
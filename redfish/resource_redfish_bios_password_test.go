@@ -0,0 +1,137 @@
+package redfish
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// Test to set and rotate the system password
+func TestAccRedfishBiosPassword_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceBiosPasswordConfig(creds, "SysPassword", "", "Passw0rd!"),
+			},
+			{
+				Config: testAccRedfishResourceBiosPasswordConfig(creds, "SysPassword", "", "Other0ne!"),
+			},
+		},
+	})
+}
+
+// Test that passwords are set, rotated with the password set last, and checked against the registry
+func TestRedfishBiosPassword_emulated(t *testing.T) {
+	_, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccRedfishResourceBiosPasswordConfig(creds, "NumLock", "", "Passw0rd!"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("NumLock is not a password of the attribute registry"),
+			},
+			{
+				Config:      testAccRedfishResourceBiosPasswordConfig(creds, "SysPassword", "", "Passw0rd!0123456789abcdefghijklmnopqrstuvwxyz"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("invalid new_password"),
+			},
+			{
+				Config: testAccRedfishResourceBiosPasswordConfig(creds, "SysPassword", "", "Passw0rd!"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_bios_password.password", "id", "/redfish/v1/Systems/System.Embedded.1/Bios/SysPassword"),
+					resource.TestCheckResourceAttr("redfish_bios_password.password", "system_id", "System.Embedded.1"),
+				),
+			},
+			{
+				// The emulated bios refuses the change unless it is given the password set by the previous step
+				Config: testAccRedfishResourceBiosPasswordConfig(creds, "SysPassword", "", "Other0ne!"),
+			},
+		},
+	})
+}
+
+// Test that a wrong old password fails the apply on the parameter it is about
+func TestRedfishBiosPassword_emulatedWrongOldPassword(t *testing.T) {
+	_, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccRedfishResourceBiosPasswordConfig(creds, "SetupPassword", "wrong", "Passw0rd!"),
+				ExpectError: regexp.MustCompile(`(?s)old_password.*The value for the parameter OldPassword`),
+			},
+		},
+	})
+}
+
+// Test that stage_only leaves the password to a scheduled bios config job
+func TestRedfishBiosPassword_emulatedStageOnly(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceBiosPasswordConfigSettings(creds, "SysPassword", "", "Passw0rd!", `stage_only = true`),
+				Check: resource.ComposeTestCheckFunc(
+					checkEmulatedResource(server, "/redfish/v1/Managers/iDRAC.Embedded.1/Jobs", "Members@odata.count", float64(1)),
+					func(*terraform.State) error {
+						job, _ := server.Resource("/redfish/v1/Managers/iDRAC.Embedded.1/Jobs")["Members"].([]interface{})[0].(map[string]interface{})
+						return checkEmulatedResource(server, job["@odata.id"].(string), "JobState", "Scheduled")(nil)
+					},
+				),
+			},
+		},
+	})
+}
+
+// Test that a manager without a Dell job queue is refused before the password is changed
+func TestRedfishBiosPassword_emulatedNoJobQueue(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	server.Modify("/redfish/v1/Managers/iDRAC.Embedded.1", map[string]interface{}{
+		"Links": map[string]interface{}{"Oem": map[string]interface{}{"Dell": map[string]interface{}{"Jobs": nil}}},
+	})
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccRedfishResourceBiosPasswordConfig(creds, "SysPassword", "", "Passw0rd!"),
+				ExpectError: regexp.MustCompile("OnReset staging requires a Dell job queue"),
+			},
+		},
+	})
+}
+
+func testAccRedfishResourceBiosPasswordConfig(testingInfo TestingServerCredentials, name, oldPassword, newPassword string) string {
+	return testAccRedfishResourceBiosPasswordConfigSettings(testingInfo, name, oldPassword, newPassword, "")
+}
+
+func testAccRedfishResourceBiosPasswordConfigSettings(testingInfo TestingServerCredentials, name, oldPassword, newPassword, settings string) string {
+	return fmt.Sprintf(`
+		resource "redfish_bios_password" "password" {
+		  redfish_server {
+			user = "%s"
+			password = "%s"
+			endpoint = "https://%s"
+			ssl_insecure = true
+		  }
+
+		  password_name = "%s"
+		  old_password = "%s"
+		  new_password = "%s"
+		  %s
+		}
+		`,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+		name,
+		oldPassword,
+		newPassword,
+		settings,
+	)
+}
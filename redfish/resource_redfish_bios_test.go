@@ -2,7 +2,9 @@ package redfish
 
 import (
 	"fmt"
+	"github.com/dell/terraform-provider-redfish/internal/emulator"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"net/http"
 	"regexp"
 	"testing"
 )
@@ -68,6 +70,351 @@ func TestAccRedfishBios_basic_InvalidAttributes(t *testing.T) {
 	})
 }
 
+// Test to set bios attributes against an emulated iDRAC, the change being applied by a job once the server resets
+func TestRedfishBios_emulated(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceBiosConfigOn(creds),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_bios.bios", "attributes.NumLock", "On"),
+				),
+			},
+			{
+				Config: testAccRedfishResourceBiosConfigOff(creds),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_bios.bios", "attributes.NumLock", "Off"),
+					checkEmulatedResource(server, "/redfish/v1/Systems/System.Embedded.1/Bios", "Attributes/NumLock", "Off"),
+				),
+			},
+		},
+	})
+}
+
+// Test that a bios config job ending in an exception is reported with the message of the job
+func TestRedfishBios_emulatedJobException(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	server.Inject(emulator.Fault{
+		Method:   http.MethodPatch,
+		URI:      "/redfish/v1/Systems/System.Embedded.1/Bios/Settings",
+		JobState: emulator.JobException,
+	})
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccRedfishResourceBiosConfigOff(creds),
+				ExpectError: regexp.MustCompile(`Unable to complete the job. \(IDRAC.2.8.SUP0518\)`),
+			},
+		},
+	})
+}
+
+// Test that the intermittent "iDRAC is not ready" error does not stop the wait for the bios config job
+func TestRedfishBios_emulatedNotReady(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	server.Inject(emulator.NotReady("/redfish/v1/TaskService/Tasks/*", 2))
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishProviderRetryConfig(2) + testAccRedfishResourceBiosConfigOff(creds),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_bios.bios", "attributes.NumLock", "Off"),
+				),
+			},
+		},
+	})
+}
+
+// Test that attributes the bios attribute registry does not allow fail the plan, before the server is locked
+func TestRedfishBios_emulatedInvalidAttributes(t *testing.T) {
+	_, creds := newEmulatedServer(t)
+	tests := []struct {
+		attributes string
+		err        string
+	}{
+		{attributes: `"NumLok" = "On"`, err: `BIOS attribute NumLok not found in the attribute registry`},
+		{attributes: `"SystemModelName" = "PowerEdge R650"`, err: `attribute SystemModelName is read only`},
+		{attributes: `"AcPwrRcvryUserDelay" = "30"`, err: `attribute AcPwrRcvryUserDelay must be between 60 and 600, got 30`},
+		{attributes: `"AcPwrRcvryUserDelay" = "soon"`, err: `attribute AcPwrRcvryUserDelay must be an integer`},
+		{attributes: `"NumLock" = "Of"`, err: `attribute NumLock must be one of On, Off, got "Of"`},
+	}
+
+	var steps []resource.TestStep
+	for _, tt := range tests {
+		steps = append(steps, resource.TestStep{
+			Config:      testAccRedfishResourceBiosConfigAttributes(creds, tt.attributes),
+			PlanOnly:    true,
+			ExpectError: regexp.MustCompile(regexp.QuoteMeta(tt.err)),
+		})
+	}
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps:             steps,
+	})
+}
+
+// Test that integer attributes are sent as integers, and that the way they are written makes no diff
+func TestRedfishBios_emulatedIntegerAttribute(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceBiosConfigAttributes(creds, `"AcPwrRcvryUserDelay" = "120"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_bios.bios", "attributes.AcPwrRcvryUserDelay", "120"),
+					checkEmulatedResource(server, "/redfish/v1/Systems/System.Embedded.1/Bios", "Attributes/AcPwrRcvryUserDelay", float64(120)),
+				),
+			},
+			{
+				Config:   testAccRedfishResourceBiosConfigAttributes(creds, `"AcPwrRcvryUserDelay" = "0120"`),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+// Test that stage_only stages the bios settings without resetting the system, and does not stage them twice
+func TestRedfishBios_emulatedStageOnly(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceBiosConfigSettings(creds, `stage_only = true`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_bios.bios", "settings_pending", "true"),
+					resource.TestCheckResourceAttr("redfish_bios.bios", "attributes.NumLock", "On"),
+					checkEmulatedResource(server, "/redfish/v1/Systems/System.Embedded.1/Bios/Settings", "Attributes/NumLock", "Off"),
+				),
+				// NumLock stays On until the system is reset
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccRedfishResourceBiosConfigSettings(creds, `stage_only = true`),
+				Check: resource.ComposeTestCheckFunc(
+					checkEmulatedResource(server, "/redfish/v1/TaskService/Tasks", "Members@odata.count", float64(1)),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+// Test that bios settings applied in a maintenance window are staged with the window
+func TestRedfishBios_emulatedMaintenanceWindow(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceBiosConfigSettings(creds, `
+					settings_apply_time = "AtMaintenanceWindowStart"
+					maintenance_window_start_time = "2023-11-04T22:00:00-05:00"
+					maintenance_window_duration = 3600`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_bios.bios", "settings_pending", "true"),
+					checkEmulatedResource(server, "/redfish/v1/Systems/System.Embedded.1/Bios", "Attributes/NumLock", "On"),
+					checkEmulatedResource(server, "/redfish/v1/Systems/System.Embedded.1/Bios/Settings", "Attributes/NumLock", "Off"),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+// Test that settings applied OnReset leave no pending settings, and that apply times are checked
+func TestRedfishBios_emulatedApplyTime(t *testing.T) {
+	_, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccRedfishResourceBiosConfigSettings(creds, `settings_apply_time = "InMaintenanceWindowOnReset"`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("maintenance_window_start_time and maintenance_window_duration are required"),
+			},
+			{
+				Config: testAccRedfishResourceBiosConfigSettings(creds, `
+					maintenance_window_start_time = "2023-11-04T22:00:00-05:00"
+					maintenance_window_duration = 3600`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("maintenance_window_start_time can only be set when settings_apply_time is"),
+			},
+			{
+				Config: testAccRedfishResourceBiosConfigSettings(creds, `
+					settings_apply_time = "Immediate"
+					stage_only = true`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("stage_only can only be set when settings_apply_time is OnReset"),
+			},
+			{
+				Config:      testAccRedfishResourceBiosConfigSettings(creds, `settings_apply_time = "Immediate"`),
+				ExpectError: regexp.MustCompile(`"Immediate" is not allowed as settings apply time`),
+			},
+			{
+				Config: testAccRedfishResourceBiosConfigSettings(creds, `settings_apply_time = "OnReset"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_bios.bios", "attributes.NumLock", "Off"),
+					resource.TestCheckResourceAttr("redfish_bios.bios", "settings_pending", "false"),
+				),
+			},
+		},
+	})
+}
+
+// Test that pending settings are reported, and that applying them again runs the job left by a stage only apply
+func TestRedfishBios_emulatedPendingSettings(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceBiosConfigSettings(creds, `stage_only = true`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_bios.bios", "pending_attributes.%", "1"),
+					resource.TestCheckResourceAttr("redfish_bios.bios", "pending_attributes.NumLock", "Off"),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccRedfishResourceBiosConfigOff(creds),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_bios.bios", "attributes.NumLock", "Off"),
+					resource.TestCheckResourceAttr("redfish_bios.bios", "pending_attributes.%", "0"),
+					resource.TestCheckResourceAttr("redfish_bios.bios", "settings_pending", "false"),
+					checkEmulatedResource(server, "/redfish/v1/TaskService/Tasks", "Members@odata.count", float64(1)),
+				),
+			},
+		},
+	})
+}
+
+// Test that cancel_pending_settings cancels the pending settings which do not match the attributes
+func TestRedfishBios_emulatedCancelPendingSettings(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:             testAccRedfishResourceBiosConfigSettings(creds, `stage_only = true`),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				// The pending NumLock does not change the current one, it is only reported
+				Config:   testAccRedfishResourceBiosConfigCancelPending(creds, false),
+				PlanOnly: true,
+			},
+			{
+				Config: testAccRedfishResourceBiosConfigCancelPending(creds, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_bios.bios", "attributes.NumLock", "On"),
+					resource.TestCheckResourceAttr("redfish_bios.bios", "pending_attributes.%", "0"),
+					checkEmulatedResource(server, "/redfish/v1/Systems/System.Embedded.1/Bios/Settings", "Attributes/NumLock", nil),
+					checkEmulatedResource(server, "/redfish/v1/Managers/iDRAC.Embedded.1/Jobs", "Members@odata.count", float64(0)),
+				),
+			},
+		},
+	})
+}
+
+// Test that cancel_pending_settings fails clearly when the manager has no Dell job queue to cancel the job in
+func TestRedfishBios_emulatedCancelPendingSettingsNoJobQueue(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:             testAccRedfishResourceBiosConfigSettings(creds, `stage_only = true`),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				PreConfig: func() {
+					server.Modify("/redfish/v1/Managers/iDRAC.Embedded.1", map[string]interface{}{
+						"Links": map[string]interface{}{"Oem": map[string]interface{}{"Dell": map[string]interface{}{"Jobs": nil}}},
+					})
+				},
+				Config:      testAccRedfishResourceBiosConfigCancelPending(creds, true),
+				ExpectError: regexp.MustCompile("cancel_pending_settings requires a Dell job queue"),
+			},
+		},
+	})
+}
+
+// Test that destroying the resource with restore_previous applies again the values the attributes had before
+func TestRedfishBios_emulatedRestorePrevious(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		CheckDestroy:      checkEmulatedResource(server, "/redfish/v1/Systems/System.Embedded.1/Bios", "Attributes/NumLock", "On"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceBiosConfigSettings(creds, `on_destroy = "restore_previous"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_bios.bios", "attributes.NumLock", "Off"),
+					resource.TestCheckResourceAttr("redfish_bios.bios", "previous_attributes.%", "1"),
+					resource.TestCheckResourceAttr("redfish_bios.bios", "previous_attributes.NumLock", "On"),
+				),
+			},
+		},
+	})
+}
+
+// Test that the values the attributes had before the resource first changed them are kept across changes and
+// restored on destroy
+func TestRedfishBios_emulatedRestorePreviousAfterChange(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	biosURI := "/redfish/v1/Systems/System.Embedded.1/Bios"
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			checkEmulatedResource(server, biosURI, "Attributes/NumLock", "On"),
+			checkEmulatedResource(server, biosURI, "Attributes/ProcVirtualization", "Enabled"),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceBiosConfigAttributesSettings(creds, `
+					"NumLock" = "Off"`, `on_destroy = "restore_previous"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_bios.bios", "previous_attributes.NumLock", "On"),
+				),
+			},
+			{
+				Config: testAccRedfishResourceBiosConfigAttributesSettings(creds, `
+					"NumLock"            = "Off"
+					"ProcVirtualization" = "Disabled"`, `on_destroy = "restore_previous"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_bios.bios", "previous_attributes.%", "2"),
+					resource.TestCheckResourceAttr("redfish_bios.bios", "previous_attributes.NumLock", "On"),
+					resource.TestCheckResourceAttr("redfish_bios.bios", "previous_attributes.ProcVirtualization", "Enabled"),
+					checkEmulatedResource(server, biosURI, "Attributes/ProcVirtualization", "Disabled"),
+				),
+			},
+		},
+	})
+}
+
+// Test that destroying the resource with reset_defaults resets the attributes to their defaults
+func TestRedfishBios_emulatedResetDefaults(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		CheckDestroy:      checkEmulatedResource(server, "/redfish/v1/Systems/System.Embedded.1/Bios", "Attributes/NumLock", "On"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceBiosConfigSettings(creds, `on_destroy = "reset_defaults"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_bios.bios", "attributes.NumLock", "Off"),
+				),
+			},
+		},
+	})
+}
+
 func testAccRedfishResourceBiosConfigOn(testingInfo TestingServerCredentials) string {
 	return fmt.Sprintf(`
 
@@ -163,3 +510,108 @@ func testAccRedfishResourceBiosConfigInvalidAttributes(testingInfo TestingServer
 		testingInfo.Endpoint,
 	)
 }
+
+func testAccRedfishResourceBiosConfigAttributes(testingInfo TestingServerCredentials, attributes string) string {
+	return fmt.Sprintf(`
+
+		resource "redfish_bios" "bios"  {
+		
+		  redfish_server {
+			user = "%s"
+			password = "%s"
+			endpoint = "https://%s"
+			ssl_insecure = true
+		  }
+
+		  attributes = {
+			%s
+		  }
+		  reset_type = "ForceRestart"
+		}
+		`,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+		attributes,
+	)
+}
+
+func testAccRedfishResourceBiosConfigAttributesSettings(testingInfo TestingServerCredentials, attributes, settings string) string {
+	return fmt.Sprintf(`
+
+		resource "redfish_bios" "bios"  {
+		
+		  redfish_server {
+			user = "%s"
+			password = "%s"
+			endpoint = "https://%s"
+			ssl_insecure = true
+		  }
+
+		  attributes = {
+			%s
+		  }
+		  reset_type = "ForceRestart"
+		  %s
+		}
+		`,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+		attributes,
+		settings,
+	)
+}
+
+func testAccRedfishResourceBiosConfigSettings(testingInfo TestingServerCredentials, settings string) string {
+	return fmt.Sprintf(`
+
+		resource "redfish_bios" "bios"  {
+		
+		  redfish_server {
+			user = "%s"
+			password = "%s"
+			endpoint = "https://%s"
+			ssl_insecure = true
+		  }
+
+		  attributes = {
+			"NumLock" = "Off"
+		  }
+		  reset_type = "ForceRestart"
+		  %s
+		}
+		`,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+		settings,
+	)
+}
+
+func testAccRedfishResourceBiosConfigCancelPending(testingInfo TestingServerCredentials, cancel bool) string {
+	return fmt.Sprintf(`
+
+		resource "redfish_bios" "bios"  {
+		
+		  redfish_server {
+			user = "%s"
+			password = "%s"
+			endpoint = "https://%s"
+			ssl_insecure = true
+		  }
+
+		  attributes = {
+			"NumLock" = "On"
+		  }
+		  reset_type = "ForceRestart"
+		  stage_only = true
+		  cancel_pending_settings = %t
+		}
+		`,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+		cancel,
+	)
+}
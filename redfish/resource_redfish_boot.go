@@ -0,0 +1,350 @@
+package redfish
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/stmcginnis/gofish"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// defaultBootTimeout bounds the change of the boot settings, reset of the system included
+const defaultBootTimeout = 10 * time.Minute
+
+// intervalBootResetCheckTime is the time in seconds between checks of the power state of the system being reset
+const intervalBootResetCheckTime = 10
+
+// bootAttributePaths locates the attributes setting the boot properties of systems
+var bootAttributePaths = attributePaths{
+	"Boot/BootOrder":                    "boot_order",
+	"Boot/BootSourceOverrideEnabled":    "boot_source_override_enabled",
+	"Boot/BootSourceOverrideTarget":     "boot_source_override_target",
+	"Boot/BootSourceOverrideMode":       "boot_source_override_mode",
+	"Boot/UefiTargetBootSourceOverride": "uefi_target_boot_source_override",
+}
+
+// bootOverrideAttributes are the attributes making up the boot source override, which are sent together
+var bootOverrideAttributes = []string{
+	"boot_source_override_enabled",
+	"boot_source_override_target",
+	"boot_source_override_mode",
+	"uefi_target_boot_source_override",
+}
+
+func resourceRedfishBoot() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceRedfishBootUpdate,
+		ReadContext:   resourceRedfishBootRead,
+		UpdateContext: resourceRedfishBootUpdate,
+		DeleteContext: resourceRedfishBootDelete,
+		Schema:        getResourceRedfishBootSchema(),
+		CustomizeDiff: resourceRedfishBootCustomizeDiff,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultBootTimeout),
+			Update: schema.DefaultTimeout(defaultBootTimeout),
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceRedfishBootImport,
+		},
+	}
+}
+
+func getResourceRedfishBootSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"redfish_alias":  redfishAliasSchema(),
+		"redfish_server": redfishServerSchema(),
+		"system_id":      systemIDSchema(true),
+		"boot_order": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Computed: true,
+			Description: "The persistent boot order, as BootOptionReference strings such as Boot0003. It must list " +
+				"every boot option of the system, which the redfish_boot_options data source reads.",
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"boot_source_override_enabled": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+			Description: "The state of the boot source override. Applicable values are 'Once', 'Continuous' and " +
+				"'Disabled'. A system uses a 'Once' override at its next boot only, after which the state of the " +
+				"resource keeps the override until it is changed.",
+			ValidateFunc: validation.StringInSlice([]string{
+				string(redfish.OnceBootSourceOverrideEnabled),
+				string(redfish.ContinuousBootSourceOverrideEnabled),
+				string(redfish.DisabledBootSourceOverrideEnabled),
+			}, false),
+		},
+		"boot_source_override_target": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+			Description: "The boot source to use instead of the boot order when the override is enabled, such as 'Pxe', " +
+				"'Cd', 'Hdd', 'BiosSetup' or 'UefiTarget'. It is checked against the allowable values of the system " +
+				"during the plan.",
+		},
+		"boot_source_override_mode": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+			Description: "The BIOS boot mode to use when the system boots from the boot source override target. " +
+				"Applicable values are 'UEFI' and 'Legacy'.",
+			ValidateFunc: validation.StringInSlice([]string{
+				string(redfish.UEFIBootSourceOverrideMode),
+				string(redfish.LegacyBootSourceOverrideMode),
+			}, false),
+		},
+		"uefi_target_boot_source_override": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    true,
+			Description: "The UEFI device path of the device to boot from when boot_source_override_target is 'UefiTarget'.",
+		},
+		"reset_system": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Description: "Reset the system once its boot settings are changed, so that a boot source override takes " +
+				"effect at once. Default is false.",
+			Default: false,
+		},
+		"reset_type": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Description: "Reset type of the system when reset_system is set. Applicable values are 'ForceRestart', " +
+				"'GracefulRestart', and 'PowerCycle'. Default is \"GracefulRestart\".",
+			ValidateFunc: validation.StringInSlice([]string{
+				string(redfish.ForceRestartResetType),
+				string(redfish.GracefulRestartResetType),
+				string(redfish.PowerCycleResetType),
+			}, false),
+			Default: string(redfish.GracefulRestartResetType),
+		},
+	}
+}
+
+// resourceRedfishBootCustomizeDiff checks the boot settings against the system during the plan: the boot order must
+// list its boot options, and the override values must be among the allowable values it announces. Settings only
+// known during the apply, like the ones left out of the configuration, are not checked.
+func resourceRedfishBootCustomizeDiff(_ context.Context, diff *schema.ResourceDiff, m interface{}) error {
+	checked := func(k string) bool {
+		_, ok := diff.GetOk(k)
+		return ok && diff.HasChange(k) && diff.NewValueKnown(k)
+	}
+	if (!checked("boot_order") && !checked("boot_source_override_enabled") && !checked("boot_source_override_target") &&
+		!checked("boot_source_override_mode")) || !serverConfigKnown(diff) {
+		return nil
+	}
+
+	service, err := NewConfig(m.(*providerMeta).config, diff)
+	if err != nil {
+		return err
+	}
+	system, err := getSystemResource(service, diff.Get("system_id").(string))
+	if err != nil {
+		return fmt.Errorf("error fetching the system: %w", err)
+	}
+	allowable, err := getBootAllowableValues(system)
+	if err != nil {
+		return fmt.Errorf("error fetching the boot settings of the system: %w", err)
+	}
+
+	if checked("boot_order") {
+		if err := checkBootOrder(diff.Get("boot_order").([]interface{}), system.Boot.BootOrder); err != nil {
+			return err
+		}
+	}
+	for k, values := range map[string][]string{
+		"boot_source_override_enabled": allowable.Boot.Enabled,
+		"boot_source_override_target":  allowable.Boot.Target,
+		"boot_source_override_mode":    allowable.Boot.Mode,
+	} {
+		if !checked(k) || len(values) == 0 {
+			continue
+		}
+		if v := diff.Get(k).(string); !contains(values, v) {
+			return fmt.Errorf("%s must be one of %s, got %s", k, strings.Join(values, ", "), v)
+		}
+	}
+	return nil
+}
+
+// checkBootOrder checks that order lists every boot option of the current boot order once
+func checkBootOrder(order []interface{}, current []string) error {
+	seen := make(map[string]bool)
+	for _, v := range order {
+		option := v.(string)
+		if !contains(current, option) {
+			return fmt.Errorf("boot_order lists %s, which is not a boot option of the system: %s", option, strings.Join(current, ", "))
+		}
+		if seen[option] {
+			return fmt.Errorf("boot_order lists %s more than once", option)
+		}
+		seen[option] = true
+	}
+	if len(seen) != len(current) {
+		return fmt.Errorf("boot_order must list every boot option of the system: %s", strings.Join(current, ", "))
+	}
+	return nil
+}
+
+func resourceRedfishBootRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	service, err := NewConfig(m.(*providerMeta).config, d)
+	if err != nil {
+		return diag.Errorf(err.Error())
+	}
+	return readRedfishBoot(service, d)
+}
+
+func resourceRedfishBootUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	service, err := NewConfig(m.(*providerMeta).config, d)
+	if err != nil {
+		return diag.Errorf(err.Error())
+	}
+	return updateRedfishBoot(ctx, service, d, m)
+}
+
+// resourceRedfishBootDelete removes the resource from the state, leaving the boot settings as they are
+func resourceRedfishBootDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	d.SetId("")
+	return nil
+}
+
+// resourceRedfishBootImport imports the boot settings of a system from an ID such as
+// https://my-server-1.myawesomecompany.org|/redfish/v1/Systems/System.Embedded.1
+func resourceRedfishBootImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	odataID, err := parseImportID(d, m)
+	if err != nil {
+		return nil, err
+	}
+	d.SetId(odataID)
+	if err := d.Set("system_id", odataIDMember(odataID, "Systems")); err != nil {
+		return nil, err
+	}
+	if err := setImportDefaults(d, getResourceRedfishBootSchema()); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func updateRedfishBoot(ctx context.Context, service *gofish.Service, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Lock the mutex to avoid race conditions with other resources
+	redfishMutexKV.Lock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
+	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
+
+	system, err := getSystemResource(service, d.Get("system_id").(string))
+	if err != nil {
+		return redfishDiagnostics("error fetching the system", err, nil)
+	}
+
+	// On creation the configured attributes are changed, on update the ones whose configuration changed. The
+	// override is sent whole, since the system may have used up a one time override whose target is changed.
+	changed := func(k string) bool {
+		_, ok := d.GetOk(k)
+		return ok && (d.IsNewResource() || d.HasChange(k))
+	}
+	boot := make(map[string]interface{})
+	if changed("boot_order") {
+		boot["BootOrder"] = d.Get("boot_order")
+	}
+	if d.IsNewResource() || d.HasChanges(bootOverrideAttributes...) {
+		for _, k := range bootOverrideAttributes {
+			if v, ok := d.GetOk(k); ok {
+				boot[bootProperty(k)] = v
+			}
+		}
+	}
+
+	if len(boot) != 0 {
+		log.Printf("[DEBUG] Changing the boot settings of %s to %v", system.ODataID, boot)
+		resp, err := system.GetClient().Patch(system.ODataID, map[string]interface{}{"Boot": boot})
+		if err != nil {
+			return redfishDiagnostics("error changing the boot settings", err, bootAttributePaths)
+		}
+		resp.Body.Close()
+
+		if d.Get("reset_system").(bool) {
+			_, diags := PowerOperation(ctx, d.Get("reset_type").(string), 0, m.(*providerMeta).waits.bootResetCheck, service, d.Get("system_id").(string))
+			if diags.HasError() {
+				return diags
+			}
+		}
+	}
+
+	d.SetId(system.ODataID)
+	return readRedfishBoot(service, d)
+}
+
+func readRedfishBoot(service *gofish.Service, d *schema.ResourceData) diag.Diagnostics {
+	system, err := getSystemResource(service, d.Get("system_id").(string))
+	if err != nil {
+		return redfishDiagnostics("error fetching the system", err, nil)
+	}
+	boot := system.Boot
+
+	if err := d.Set("system_id", system.ID); err != nil {
+		return diag.Errorf("error setting system_id: %s", err)
+	}
+	if err := d.Set("boot_order", boot.BootOrder); err != nil {
+		return diag.Errorf("error setting boot_order: %s", err)
+	}
+
+	// Once the system has booted with a one time override it disables it, which is not a change to revert
+	if d.Get("boot_source_override_enabled") == string(redfish.OnceBootSourceOverrideEnabled) &&
+		boot.BootSourceOverrideEnabled == redfish.DisabledBootSourceOverrideEnabled {
+		log.Printf("[DEBUG] The one time boot source override of %s has been used", system.ODataID)
+		return nil
+	}
+	for _, k := range bootOverrideAttributes {
+		var v interface{}
+		switch k {
+		case "boot_source_override_enabled":
+			v = string(boot.BootSourceOverrideEnabled)
+		case "boot_source_override_target":
+			v = string(boot.BootSourceOverrideTarget)
+		case "boot_source_override_mode":
+			v = string(boot.BootSourceOverrideMode)
+		case "uefi_target_boot_source_override":
+			v = boot.UefiTargetBootSourceOverride
+		}
+		if err := d.Set(k, v); err != nil {
+			return diag.Errorf("error setting %s: %s", k, err)
+		}
+	}
+	return nil
+}
+
+// bootProperty returns the boot property an override attribute sets, such as BootSourceOverrideTarget for
+// boot_source_override_target
+func bootProperty(attribute string) string {
+	for property, a := range bootAttributePaths {
+		if a == attribute {
+			return strings.TrimPrefix(property, "Boot/")
+		}
+	}
+	return ""
+}
+
+// bootAllowableValues holds the values the boot properties of a system accept, which gofish does not decode
+type bootAllowableValues struct {
+	Boot struct {
+		Enabled []string `json:"BootSourceOverrideEnabled@Redfish.AllowableValues"`
+		Target  []string `json:"BootSourceOverrideTarget@Redfish.AllowableValues"`
+		Mode    []string `json:"BootSourceOverrideMode@Redfish.AllowableValues"`
+	}
+}
+
+func getBootAllowableValues(system *redfish.ComputerSystem) (*bootAllowableValues, error) {
+	var allowable bootAllowableValues
+	if err := getJSON(system.GetClient(), system.ODataID, &allowable); err != nil {
+		return nil, err
+	}
+	return &allowable, nil
+}
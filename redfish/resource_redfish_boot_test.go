@@ -0,0 +1,136 @@
+package redfish
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// Test to boot once from PXE
+func TestAccRedfishBoot_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceBootConfig(creds, `
+					boot_source_override_enabled = "Once"
+					boot_source_override_target = "Pxe"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_boot.boot", "boot_source_override_target", "Pxe"),
+				),
+			},
+		},
+	})
+}
+
+// Test to change the boot order and override the boot source of an emulated iDRAC
+func TestRedfishBoot_emulated(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceBootConfig(creds, `
+					boot_order = ["Boot0005", "Boot0003", "Boot0004"]
+					boot_source_override_enabled = "Continuous"
+					boot_source_override_target = "Hdd"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_boot.boot", "id", "/redfish/v1/Systems/System.Embedded.1"),
+					resource.TestCheckResourceAttr("redfish_boot.boot", "boot_order.0", "Boot0005"),
+					resource.TestCheckResourceAttr("redfish_boot.boot", "boot_source_override_mode", "UEFI"),
+					checkEmulatedResource(server, "/redfish/v1/Systems/System.Embedded.1", "Boot/BootOrder/0", "Boot0005"),
+					checkEmulatedResource(server, "/redfish/v1/Systems/System.Embedded.1", "Boot/BootSourceOverrideTarget", "Hdd"),
+				),
+			},
+			{
+				Config: testAccRedfishResourceBootConfig(creds, `
+					boot_source_override_enabled = "Continuous"
+					boot_source_override_target = "Cd"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_boot.boot", "boot_order.0", "Boot0005"),
+					checkEmulatedResource(server, "/redfish/v1/Systems/System.Embedded.1", "Boot/BootSourceOverrideTarget", "Cd"),
+				),
+			},
+		},
+	})
+}
+
+// Test that a one time override resetting the system is used up, without the next plan overriding the boot again
+func TestRedfishBoot_emulatedOnce(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceBootConfig(creds, `
+					boot_source_override_enabled = "Once"
+					boot_source_override_target = "Pxe"
+					reset_system = true
+					reset_type = "ForceRestart"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_boot.boot", "boot_source_override_enabled", "Once"),
+					resource.TestCheckResourceAttr("redfish_boot.boot", "boot_source_override_target", "Pxe"),
+					checkEmulatedResource(server, "/redfish/v1/Systems/System.Embedded.1", "Boot/BootSourceOverrideEnabled", "Disabled"),
+					checkEmulatedResource(server, "/redfish/v1/Systems/System.Embedded.1", "Boot/BootSourceOverrideTarget", "None"),
+				),
+			},
+			{
+				// Changing the target overrides the boot once more
+				Config: testAccRedfishResourceBootConfig(creds, `
+					boot_source_override_enabled = "Once"
+					boot_source_override_target = "BiosSetup"`),
+				Check: resource.ComposeTestCheckFunc(
+					checkEmulatedResource(server, "/redfish/v1/Systems/System.Embedded.1", "Boot/BootSourceOverrideEnabled", "Once"),
+					checkEmulatedResource(server, "/redfish/v1/Systems/System.Embedded.1", "Boot/BootSourceOverrideTarget", "BiosSetup"),
+				),
+			},
+		},
+	})
+}
+
+// Test that the boot settings are checked against the system during the plan
+func TestRedfishBoot_emulatedInvalid(t *testing.T) {
+	_, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccRedfishResourceBootConfig(creds, `boot_source_override_target = "Network"`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("boot_source_override_target must be one of None, Pxe"),
+			},
+			{
+				Config:      testAccRedfishResourceBootConfig(creds, `boot_order = ["Boot0004", "Boot0003"]`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("boot_order must list every boot option of the system"),
+			},
+			{
+				Config:      testAccRedfishResourceBootConfig(creds, `boot_order = ["Boot0004", "Boot0003", "Boot0009"]`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("boot_order lists Boot0009, which is not a boot option of the system"),
+			},
+		},
+	})
+}
+
+func testAccRedfishResourceBootConfig(testingInfo TestingServerCredentials, settings string) string {
+	return fmt.Sprintf(`
+		resource "redfish_boot" "boot" {
+		  redfish_server {
+			user = "%s"
+			password = "%s"
+			endpoint = "https://%s"
+			ssl_insecure = true
+		  }
+
+		  %s
+		}
+		`,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+		settings,
+	)
+}
@@ -12,6 +12,9 @@ import (
 	"github.com/stmcginnis/gofish"
 )
 
+// idracAttributePaths locates the attributes setting the properties of the iDRAC attributes
+var idracAttributePaths = attributePaths{"Attributes": "attributes"}
+
 func resourceRedfishDellIdracAttributes() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceRedfishDellIdracAttributesCreate,
@@ -19,41 +22,17 @@ func resourceRedfishDellIdracAttributes() *schema.Resource {
 		UpdateContext: resourceRedfishDellIdracAttributesUpdate,
 		DeleteContext: resourceRedfishDellIdracAttributesDelete,
 		Schema:        getResourceRedfishDellIdracAttributesSchema(),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceRedfishDellIdracAttributesImport,
+		},
 	}
 }
 
 func getResourceRedfishDellIdracAttributesSchema() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
-		"redfish_server": {
-			Type:        schema.TypeList,
-			Required:    true,
-			Description: "List of server BMCs and their respective user credentials",
-			Elem: &schema.Resource{
-				Schema: map[string]*schema.Schema{
-					"user": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Description: "User name for login",
-					},
-					"password": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Description: "User password for login",
-						Sensitive:   true,
-					},
-					"endpoint": {
-						Type:        schema.TypeString,
-						Required:    true,
-						Description: "Server BMC IP address or hostname",
-					},
-					"ssl_insecure": {
-						Type:        schema.TypeBool,
-						Optional:    true,
-						Description: "This field indicates whether the SSL/TLS certificate must be verified or not",
-					},
-				},
-			},
-		},
+		"redfish_alias":  redfishAliasSchema(),
+		"redfish_server": redfishServerSchema(),
+		"manager_id":     managerIDSchema(true),
 		"attributes": {
 			Type:     schema.TypeMap,
 			Required: true,
@@ -68,7 +47,7 @@ func getResourceRedfishDellIdracAttributesSchema() map[string]*schema.Schema {
 }
 
 func resourceRedfishDellIdracAttributesCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	service, err := NewConfig(m.(*schema.ResourceData), d)
+	service, err := NewConfig(m.(*providerMeta).config, d)
 	if err != nil {
 		return diag.Errorf(err.Error())
 	}
@@ -76,7 +55,7 @@ func resourceRedfishDellIdracAttributesCreate(ctx context.Context, d *schema.Res
 }
 
 func resourceRedfishDellIdracAttributesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	service, err := NewConfig(m.(*schema.ResourceData), d)
+	service, err := NewConfig(m.(*providerMeta).config, d)
 	if err != nil {
 		return diag.Errorf(err.Error())
 	}
@@ -84,7 +63,7 @@ func resourceRedfishDellIdracAttributesRead(ctx context.Context, d *schema.Resou
 }
 
 func resourceRedfishDellIdracAttributesUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	service, err := NewConfig(m.(*schema.ResourceData), d)
+	service, err := NewConfig(m.(*providerMeta).config, d)
 	if err != nil {
 		return diag.Errorf(err.Error())
 	}
@@ -95,13 +74,27 @@ func resourceRedfishDellIdracAttributesUpdate(ctx context.Context, d *schema.Res
 }
 
 func resourceRedfishDellIdracAttributesDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	service, err := NewConfig(m.(*schema.ResourceData), d)
+	service, err := NewConfig(m.(*providerMeta).config, d)
 	if err != nil {
 		return diag.Errorf(err.Error())
 	}
 	return deleteRedfishDellIdracAttributes(ctx, service, d, m)
 }
 
+// resourceRedfishDellIdracAttributesImport imports the iDRAC attributes from an ID such as
+// https://my-server-1.myawesomecompany.org|/redfish/v1/Managers/iDRAC.Embedded.1/Oem/Dell/DellAttributes/iDRAC.Embedded.1
+func resourceRedfishDellIdracAttributesImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	odataID, err := parseImportID(d, m)
+	if err != nil {
+		return nil, err
+	}
+	d.SetId(odataID)
+	if err := d.Set("manager_id", odataIDMember(odataID, "Managers")); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
 func updateRedfishDellIdracAttributes(ctx context.Context, service *gofish.Service, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
@@ -111,41 +104,41 @@ func updateRedfishDellIdracAttributes(ctx context.Context, service *gofish.Servi
 	// get managerAttributeRegistry to check parameters before posting them to redfish
 	managerAttributeRegistry, err := getManagerAttributeRegistry(service)
 	if err != nil {
-		return diag.Errorf("there was an issue when creating/updating idrac attributes - %s", err)
+		return redfishDiagnostics("there was an issue when creating/updating idrac attributes", err, idracAttributePaths)
 	}
 
 	// Set right attributes to patch (values from map are all string. It needs int and string)
 	attributesToPatch, err := setManagerAttributesRightType(attributesTf, managerAttributeRegistry)
 	if err != nil {
-		return diag.Errorf("there was an issue when creating/updating idrac attributes - %s", err)
+		return redfishDiagnostics("there was an issue when creating/updating idrac attributes", err, idracAttributePaths)
 	}
 
 	// Check that all attributes passed are compliant with the API
 	err = checkManagerAttributes(managerAttributeRegistry, attributesToPatch)
 	if err != nil {
-		return diag.Errorf("there was an issue when creating/updating idrac attributes - %s", err)
+		return redfishDiagnostics("there was an issue when creating/updating idrac attributes", err, idracAttributePaths)
 	}
 
-	// get managers (Dell servers have only the iDRAC)
-	managers, err := service.Managers()
+	// get the manager (Dell servers have only the iDRAC)
+	manager, err := getManagerResource(service, d.Get("manager_id").(string))
 	if err != nil {
-		return diag.Errorf("there was an issue when creating/updating idrac attributes - %s", err)
+		return redfishDiagnostics("there was an issue when creating/updating idrac attributes", err, idracAttributePaths)
 	}
 
 	// Get OEM
-	dellManager, err := dell.DellManager(managers[0])
+	dellManager, err := dell.DellManager(manager)
 	if err != nil {
-		return diag.Errorf("there was an issue when creating/updating idrac attributes - %s", err)
+		return redfishDiagnostics("there was an issue when creating/updating idrac attributes", err, idracAttributePaths)
 	}
 
 	// Get Dell attributes
 	dellAttributes, err := dellManager.DellAttributes()
 	if err != nil {
-		return diag.Errorf("there was an issue when creating/updating idrac attributes - %s", err)
+		return redfishDiagnostics("there was an issue when creating/updating idrac attributes", err, idracAttributePaths)
 	}
 	idracAttributes, err := getIdracAttributes(dellAttributes)
 	if err != nil {
-		return diag.Errorf("there was an issue when creating/updating idrac attributes - %s", err)
+		return redfishDiagnostics("there was an issue when creating/updating idrac attributes", err, idracAttributePaths)
 	}
 
 	// Set the body to send
@@ -159,7 +152,7 @@ func updateRedfishDellIdracAttributes(ctx context.Context, service *gofish.Servi
 
 	response, err := service.GetClient().Patch(idracAttributes.ODataID, patchBody)
 	if err != nil {
-		return diag.Errorf("there was an issue when creating/updating idrac attributes - %s", err)
+		return redfishDiagnostics("there was an issue when creating/updating idrac attributes", err, idracAttributePaths)
 	}
 	response.Body.Close()
 
@@ -172,26 +165,26 @@ func updateRedfishDellIdracAttributes(ctx context.Context, service *gofish.Servi
 func readRedfishDellIdracAttributes(ctx context.Context, service *gofish.Service, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	// get managers (Dell servers have only the iDRAC)
-	managers, err := service.Managers()
+	// get the manager (Dell servers have only the iDRAC)
+	manager, err := getManagerResource(service, d.Get("manager_id").(string))
 	if err != nil {
-		return diag.Errorf("there was an issue when reading idrac attributes - %s", err)
+		return redfishDiagnostics("there was an issue when reading idrac attributes", err, idracAttributePaths)
 	}
 
 	// Get OEM
-	dellManager, err := dell.DellManager(managers[0])
+	dellManager, err := dell.DellManager(manager)
 	if err != nil {
-		return diag.Errorf("there was an issue when reading idrac attributes - %s", err)
+		return redfishDiagnostics("there was an issue when reading idrac attributes", err, idracAttributePaths)
 	}
 
 	// Get Dell attributes
 	dellAttributes, err := dellManager.DellAttributes()
 	if err != nil {
-		return diag.Errorf("there was an issue when reading idrac attributes - %s", err)
+		return redfishDiagnostics("there was an issue when reading idrac attributes", err, idracAttributePaths)
 	}
 	idracAttributes, err := getIdracAttributes(dellAttributes)
 	if err != nil {
-		return diag.Errorf("there was an issue when reading idrac attributes - %s", err)
+		return redfishDiagnostics("there was an issue when reading idrac attributes", err, idracAttributePaths)
 	}
 
 	// Get config attributes
@@ -208,6 +201,15 @@ func readRedfishDellIdracAttributes(ctx context.Context, service *gofish.Service
 		}
 	}
 
+	// Nothing is known about the attributes managed by the resource after an import, so all of them are read
+	if len(oldAttr) == 0 {
+		for k, v := range idracAttributes.Attributes {
+			if v != nil {
+				readAttributes[k] = fmt.Sprintf("%v", v)
+			}
+		}
+	}
+
 	err = d.Set("attributes", readAttributes)
 	if err != nil {
 		return diag.Errorf("there was an issue when setting read attributes - %s", err)
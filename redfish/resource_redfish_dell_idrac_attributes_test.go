@@ -37,6 +37,26 @@ func TestAccRedfishIDRACAttributes_invalidAttribute(t *testing.T) {
 	})
 }
 
+// Test to set iDRAC attributes against an emulated iDRAC
+func TestRedfishIDRACAttributes_emulated(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceIDracAttributesConfig(creds),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_dell_idrac_attributes.idrac", "attributes.Users.3.UserName", "mike"),
+					checkEmulatedResource(server, "/redfish/v1/Managers/iDRAC.Embedded.1/Oem/Dell/DellAttributes/iDRAC.Embedded.1",
+						"Attributes/Time.1.Timezone", "CST6CDT"),
+					checkEmulatedResource(server, "/redfish/v1/Managers/iDRAC.Embedded.1/Oem/Dell/DellAttributes/iDRAC.Embedded.1",
+						"Attributes/Users.3.Privilege", float64(511)),
+				),
+			},
+		},
+	})
+}
+
 func testAccRedfishResourceIDracAttributesConfig(testingInfo TestingServerCredentials) string {
 	return fmt.Sprintf(`
 	resource "redfish_dell_idrac_attributes" "idrac" {
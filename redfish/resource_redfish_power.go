@@ -2,6 +2,8 @@ package redfish
 
 import (
 	"context"
+	"fmt"
+	"github.com/dell/terraform-provider-redfish/common"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -18,14 +20,26 @@ func resourceRedFishPower() *schema.Resource {
 		DeleteContext: resourceRedfishPowerDelete,
 		Schema:        getResourceRedfishPowerSchema(),
 		CustomizeDiff: CheckPowerDiff(),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceRedfishPowerImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultPowerTimeout),
+			Update: schema.DefaultTimeout(defaultPowerTimeout),
+		},
 	}
 }
 
 const (
 	defaultMaximumPowerConfigServerTimeout int = 120
 	intervalPowerConfigJobCheckTime        int = 10
+	// defaultPowerTimeout bounds the power operation, until the system reaches the power state it leads to
+	defaultPowerTimeout = 10 * time.Minute
 )
 
+// powerSettleTime is the time given to the system to settle after a power operation
+const powerSettleTime = 10 * time.Second
+
 // Custom function for calculating power state. Given some desired_power_action, we know what the expected power
 // state should be after the action is applied. Instead of marking the value of power_state as unknown during
 // PlanResourceChange, we can calculate exactly what the end power_state should be.
@@ -55,36 +69,9 @@ func CheckPowerDiff(funcs ...schema.CustomizeDiffFunc) schema.CustomizeDiffFunc
 
 func getResourceRedfishPowerSchema() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
-		"redfish_server": {
-			Type:        schema.TypeList,
-			Required:    true,
-			Description: "List of server BMCs and their respective user credentials",
-			Elem: &schema.Resource{
-				Schema: map[string]*schema.Schema{
-					"user": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Description: "User name for login",
-					},
-					"password": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Description: "User password for login",
-						Sensitive:   true,
-					},
-					"endpoint": {
-						Type:        schema.TypeString,
-						Required:    true,
-						Description: "Server BMC IP address or hostname",
-					},
-					"ssl_insecure": {
-						Type:        schema.TypeBool,
-						Optional:    true,
-						Description: "This field indicates whether the SSL/TLS certificate must be verified or not",
-					},
-				},
-			},
-		},
+		"redfish_alias":  redfishAliasSchema(),
+		"redfish_server": redfishServerSchema(),
+		"system_id":      systemIDSchema(true),
 		"desired_power_action": {
 			Type:     schema.TypeString,
 			Required: true,
@@ -105,9 +92,10 @@ func getResourceRedfishPowerSchema() map[string]*schema.Schema {
 		"maximum_wait_time": {
 			Type:     schema.TypeInt,
 			Optional: true,
-			Description: "The maximum amount of time to wait for the server to enter the correct power state before" +
-				"giving up in seconds",
-			Default: defaultMaximumPowerConfigServerTimeout,
+			Description: "The maximum amount of time to wait for the server to enter the correct power state before " +
+				"giving up in seconds. Deprecated, use the timeouts block instead.",
+			Default:    defaultMaximumPowerConfigServerTimeout,
+			Deprecated: "Use the create and update timeouts of the timeouts block instead",
 		},
 		"check_interval": {
 			Type:        schema.TypeInt,
@@ -129,15 +117,15 @@ func resourceRedfishPowerRead(ctx context.Context, d *schema.ResourceData, m int
 	log.Printf("[DEBUG] %s: Beginning read", d.Id())
 	var diags diag.Diagnostics
 
-	service, err := NewConfig(m.(*schema.ResourceData), d)
+	service, err := NewConfig(m.(*providerMeta).config, d)
 	if err != nil {
 		return diag.Errorf(err.Error())
 	}
 
-	system, err := getSystemResource(service)
+	system, err := getSystemResource(service, d.Get("system_id").(string))
 	if err != nil {
 		log.Printf("[ERROR]: Failed to identify system: %s", err)
-		return diag.Errorf(err.Error())
+		return redfishDiagnostics("Failed to identify system", err, nil)
 	}
 
 	if err := d.Set("power_state", system.PowerState); err != nil {
@@ -163,8 +151,8 @@ func resourceRedfishPowerUpdate(ctx context.Context, d *schema.ResourceData, m i
 	var diags diag.Diagnostics
 
 	// Lock the mutex to avoid race conditions with other resources
-	redfishMutexKV.Lock(getRedfishServerEndpoint(d))
-	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(d))
+	redfishMutexKV.Lock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
+	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
 
 	resetType, ok := d.GetOk("desired_power_action")
 
@@ -174,28 +162,30 @@ func resourceRedfishPowerUpdate(ctx context.Context, d *schema.ResourceData, m i
 	}
 
 	// Takes the m interface and feeds it the user input data d. You can then reference it with X.GetOk("user")
-	service, err := NewConfig(m.(*schema.ResourceData), d)
+	service, err := NewConfig(m.(*providerMeta).config, d)
 
 	if err != nil {
 		return diag.Errorf(err.Error())
 	}
 
-	system, err := getSystemResource(service)
+	system, err := getSystemResource(service, d.Get("system_id").(string))
 	if err != nil {
 		log.Printf("[ERROR]: Failed to identify system: %s", err)
-		return diag.Errorf(err.Error())
+		return redfishDiagnostics("Failed to identify system", err, nil)
 	}
 
 	d.SetId(system.SerialNumber + "_power")
 
-	maxTimeout := d.Get("maximum_wait_time")
+	maxTimeout := deprecatedTimeout(d, "maximum_wait_time", defaultMaximumPowerConfigServerTimeout)
 
 	checkInterval := d.Get("check_interval")
 
-	powerState, diags := PowerOperation(resetType.(string), maxTimeout.(int), checkInterval.(int), service)
+	powerState, diags := PowerOperation(ctx, resetType.(string), maxTimeout, checkInterval.(int), service, d.Get("system_id").(string))
 
 	// time to allow changes to get reflected
-	time.Sleep(10 * time.Second)
+	if err := common.SleepWithContext(ctx, m.(*providerMeta).waits.powerSettle); err != nil {
+		return append(diags, diag.Errorf("Error waiting for the power state to be reflected: %s", err)...)
+	}
 
 	if (resetType == "ForceRestart" || resetType == "GracefulRestart" || resetType == "PowerCycle" || resetType == "Nmi") && powerState == "On" {
 		powerState = "Reset_On"
@@ -214,3 +204,38 @@ func resourceRedfishPowerDelete(ctx context.Context, d *schema.ResourceData, m i
 
 	return diags
 }
+
+// resourceRedfishPowerImport imports the power state of a system from an ID such as
+// https://my-server-1.myawesomecompany.org|/redfish/v1/Systems/System.Embedded.1. The desired power action is set to
+// the one leading to the current power state.
+func resourceRedfishPowerImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	odataID, err := parseImportID(d, m)
+	if err != nil {
+		return nil, err
+	}
+
+	service, err := NewConfig(m.(*providerMeta).config, d)
+	if err != nil {
+		return nil, err
+	}
+	system, err := redfish.GetComputerSystem(service.GetClient(), odataID)
+	if err != nil {
+		return nil, fmt.Errorf("Error when retrieving system %s: %s", odataID, err)
+	}
+
+	d.SetId(system.SerialNumber + "_power")
+	if err := d.Set("system_id", system.ID); err != nil {
+		return nil, err
+	}
+	desiredPowerAction := redfish.OnResetType
+	if system.PowerState == redfish.OffPowerState {
+		desiredPowerAction = redfish.ForceOffResetType
+	}
+	if err := d.Set("desired_power_action", string(desiredPowerAction)); err != nil {
+		return nil, err
+	}
+	if err := setImportDefaults(d, getResourceRedfishPowerSchema()); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
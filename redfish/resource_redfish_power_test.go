@@ -2,7 +2,9 @@ package redfish
 
 import (
 	"fmt"
+	"github.com/dell/terraform-provider-redfish/internal/emulator"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"net/http"
 	"os"
 	"regexp"
 	"testing"
@@ -119,6 +121,94 @@ func TestAccRedfishPower_Invalid(t *testing.T) {
 		},
 	})
 }
+
+// Test to change the power state of an emulated iDRAC
+func TestRedfishPower_emulated(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourcePowerConfig(creds, "ForceOff", 120, 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_power.system_power", "power_state", "Off"),
+					checkEmulatedResource(server, "/redfish/v1/Systems/System.Embedded.1", "PowerState", "Off"),
+				),
+			},
+			{
+				Config: testAccRedfishResourcePowerConfig(creds, "On", 120, 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_power.system_power", "power_state", "On"),
+					checkEmulatedResource(server, "/redfish/v1/Systems/System.Embedded.1", "PowerState", "On"),
+				),
+			},
+			{
+				Config: testAccRedfishResourcePowerConfig(creds, "ForceRestart", 120, 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_power.system_power", "power_state", "Reset_On"),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+// Test that an error of the reset action is reported
+func TestRedfishPower_emulatedResetError(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	server.Inject(emulator.Fault{
+		Method:     http.MethodPost,
+		URI:        "/redfish/v1/Systems/System.Embedded.1/Actions/ComputerSystem.Reset",
+		StatusCode: http.StatusInternalServerError,
+		Message:    "Unable to reset the server.",
+	})
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccRedfishResourcePowerConfig(creds, "ForceOff", 120, 1),
+				ExpectError: regexp.MustCompile("Unable to reset the server."),
+			},
+		},
+	})
+}
+
+// Test that the timeouts block bounds the wait for the power state when maximum_wait_time is not set
+func TestRedfishPower_emulatedTimeout(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	// The reset is accepted but never carried out
+	server.Inject(emulator.Fault{
+		Method:     http.MethodPost,
+		URI:        "/redfish/v1/Systems/System.Embedded.1/Actions/ComputerSystem.Reset",
+		StatusCode: http.StatusNoContent,
+	})
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+				resource "redfish_power" "system_power" {
+				  redfish_server {
+					user = "%s"
+					password = "%s"
+					endpoint = "https://%s"
+					ssl_insecure = true
+				  }
+
+				  desired_power_action = "ForceOff"
+				  check_interval = 1
+
+				  timeouts {
+					create = "3s"
+				  }
+				}
+				`, creds.Username, creds.Password, creds.Endpoint),
+				ExpectError: regexp.MustCompile("did not reach the Off power state"),
+			},
+		},
+	})
+}
+
 func testAccRedfishResourcePowerConfig(testingInfo TestingServerCredentials,
 	desiredPowerAction string,
 	maximumWaitTime int,
@@ -0,0 +1,242 @@
+package redfish
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/dell/terraform-provider-redfish/common"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/stmcginnis/gofish"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// defaultSecureBootTimeout bounds the change of the secure boot settings, reset of the system and config job included
+const defaultSecureBootTimeout = 30 * time.Minute
+
+// secureBootAttributePaths locates the attributes setting the properties of secure boot
+var secureBootAttributePaths = attributePaths{
+	"SecureBootEnable": "secure_boot_enable",
+	"SecureBootMode":   "secure_boot_mode",
+	"ResetKeysType":    "reset_keys",
+}
+
+func resourceRedfishSecureBoot() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceRedfishSecureBootUpdate,
+		ReadContext:   resourceRedfishSecureBootRead,
+		UpdateContext: resourceRedfishSecureBootUpdate,
+		DeleteContext: resourceRedfishSecureBootDelete,
+		Schema:        getResourceRedfishSecureBootSchema(),
+		CustomizeDiff: resourceRedfishSecureBootCustomizeDiff,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultSecureBootTimeout),
+			Update: schema.DefaultTimeout(defaultSecureBootTimeout),
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceRedfishSecureBootImport,
+		},
+	}
+}
+
+func getResourceRedfishSecureBootSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"redfish_alias":  redfishAliasSchema(),
+		"redfish_server": redfishServerSchema(),
+		"system_id":      systemIDSchema(true),
+		"secure_boot_enable": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Computed: true,
+			Description: "Whether UEFI Secure Boot is enabled. It is enforced from the next boot, once the system has " +
+				"a platform key.",
+		},
+		"secure_boot_mode": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+			Description: "The Secure Boot mode of the system. Applicable values are 'DeployedMode', 'UserMode' and " +
+				"'AuditMode', among the ones the system supports. The system is in 'SetupMode' while it has no platform " +
+				"key, which the DeleteAllKeys and DeletePK key resets lead to.",
+			ValidateFunc: validation.StringInSlice([]string{
+				string(redfish.DeployedModeSecureBootModeType),
+				string(redfish.UserModeSecureBootModeType),
+				string(redfish.AuditModeSecureBootModeType),
+			}, false),
+		},
+		"reset_keys": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Description: "Reset of the keys of the Secure Boot databases, run when the resource is created with it and " +
+				"every time it changes to another value. Applicable values are 'ResetAllKeysToDefault', which restores " +
+				"the keys of the manufacturer, 'DeleteAllKeys' and 'DeletePK', which deletes the platform key.",
+			ValidateFunc: validation.StringInSlice([]string{
+				string(redfish.ResetAllKeysToDefaultResetKeysType),
+				string(redfish.DeleteAllKeysResetKeysType),
+				string(redfish.DeletePKResetKeysType),
+			}, false),
+		},
+		"secure_boot_current_boot": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Whether Secure Boot was enforced during the current boot of the system, 'Enabled' or 'Disabled'.",
+		},
+		"reset_type": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Description: "Reset type to apply on the computer system for the Secure Boot changes to take effect. " +
+				"Applicable values are 'ForceRestart', 'GracefulRestart', and 'PowerCycle'. " +
+				"Default = \"GracefulRestart\". ",
+			ValidateFunc: validation.StringInSlice([]string{
+				string(redfish.ForceRestartResetType),
+				string(redfish.GracefulRestartResetType),
+				string(redfish.PowerCycleResetType),
+			}, false),
+			Default: string(redfish.GracefulRestartResetType),
+		},
+	}
+}
+
+// resourceRedfishSecureBootCustomizeDiff refuses a Secure Boot mode along with a key reset which leaves the system
+// in setup mode, since the mode would never be the configured one, and plans the boot after a change as unknown
+func resourceRedfishSecureBootCustomizeDiff(_ context.Context, diff *schema.ResourceDiff, m interface{}) error {
+	// The mode is computed, only the configured one conflicts with the key reset
+	resetKeys := diff.Get("reset_keys").(string)
+	config := diff.GetRawConfig()
+	modeSet := !config.IsNull() && config.IsKnown() && !config.GetAttr("secure_boot_mode").IsNull()
+	if modeSet && diff.HasChange("reset_keys") && resetKeys != "" && resetKeys != string(redfish.ResetAllKeysToDefaultResetKeysType) {
+		return fmt.Errorf("secure_boot_mode cannot be set when reset_keys is %s, which puts the system in %s",
+			resetKeys, redfish.SetupModeSecureBootModeType)
+	}
+	if diff.Id() != "" && diff.HasChanges("secure_boot_enable", "secure_boot_mode", "reset_keys") {
+		return diff.SetNewComputed("secure_boot_current_boot")
+	}
+	return nil
+}
+
+func resourceRedfishSecureBootRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	service, err := NewConfig(m.(*providerMeta).config, d)
+	if err != nil {
+		return diag.Errorf(err.Error())
+	}
+	return readRedfishSecureBoot(service, d)
+}
+
+func resourceRedfishSecureBootUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	service, err := NewConfig(m.(*providerMeta).config, d)
+	if err != nil {
+		return diag.Errorf(err.Error())
+	}
+	return updateRedfishSecureBoot(ctx, service, d, m)
+}
+
+// resourceRedfishSecureBootDelete removes the resource from the state, leaving Secure Boot as it is
+func resourceRedfishSecureBootDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	d.SetId("")
+	return nil
+}
+
+// resourceRedfishSecureBootImport imports the Secure Boot settings of a system from an ID such as
+// https://my-server-1.myawesomecompany.org|/redfish/v1/Systems/System.Embedded.1/SecureBoot
+func resourceRedfishSecureBootImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	odataID, err := parseImportID(d, m)
+	if err != nil {
+		return nil, err
+	}
+	d.SetId(odataID)
+	if err := d.Set("system_id", odataIDMember(odataID, "Systems")); err != nil {
+		return nil, err
+	}
+	if err := setImportDefaults(d, getResourceRedfishSecureBootSchema()); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func updateRedfishSecureBoot(ctx context.Context, service *gofish.Service, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Lock the mutex to avoid race conditions with other resources
+	redfishMutexKV.Lock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
+	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
+
+	secureBoot, err := getSecureBootResource(service, d.Get("system_id").(string))
+	if err != nil {
+		return redfishDiagnostics("error fetching the secure boot resource", err, nil)
+	}
+
+	reset := false
+	if resetKeys, ok := d.GetOk("reset_keys"); ok && (d.IsNewResource() || d.HasChange("reset_keys")) {
+		log.Printf("[DEBUG] Resetting the secure boot keys of %s with %s", secureBoot.ODataID, resetKeys)
+		if err := secureBoot.ResetKeys(redfish.ResetKeysType(resetKeys.(string))); err != nil {
+			return redfishDiagnostics("error resetting the secure boot keys", err, secureBootAttributePaths)
+		}
+		reset = true
+	}
+
+	// Only the configured settings which differ from the current ones are changed
+	config := d.GetRawConfig()
+	payload := make(map[string]interface{})
+	if !config.GetAttr("secure_boot_enable").IsNull() && d.Get("secure_boot_enable").(bool) != secureBoot.SecureBootEnable {
+		payload["SecureBootEnable"] = d.Get("secure_boot_enable")
+	}
+	if !config.GetAttr("secure_boot_mode").IsNull() && d.Get("secure_boot_mode").(string) != string(secureBoot.SecureBootMode) {
+		payload["SecureBootMode"] = d.Get("secure_boot_mode")
+	}
+
+	var jobURI string
+	if len(payload) != 0 {
+		if jobURI, err = patchSettingsObject(secureBoot.GetClient(), secureBoot.ODataID, payload); err != nil {
+			return redfishDiagnostics("error changing the secure boot settings", err, secureBootAttributePaths)
+		}
+		reset = true
+	}
+
+	// The keys are reset and the settings applied while the system starts again
+	if reset {
+		_, diags := PowerOperation(ctx, d.Get("reset_type").(string), 0, m.(*providerMeta).waits.biosConfigJobCheck, service, d.Get("system_id").(string))
+		if diags.HasError() {
+			return diags
+		}
+		if jobURI != "" {
+			if err := common.WaitForJobToFinish(ctx, service, jobURI, m.(*providerMeta).waits.biosConfigJobCheck, 0); err != nil {
+				return redfishDiagnostics(fmt.Sprintf("Error waiting for the secure boot config job (%s) to be completed", jobURI), err, secureBootAttributePaths)
+			}
+		}
+		if err := common.SleepWithContext(ctx, m.(*providerMeta).waits.biosSettingsSettle); err != nil {
+			return diag.Errorf("Error waiting for the secure boot settings to be updated: %s", err)
+		}
+	}
+
+	d.SetId(secureBoot.ODataID)
+	return readRedfishSecureBoot(service, d)
+}
+
+func readRedfishSecureBoot(service *gofish.Service, d *schema.ResourceData) diag.Diagnostics {
+	secureBoot, err := getSecureBootResource(service, d.Get("system_id").(string))
+	if err != nil {
+		return redfishDiagnostics("error fetching the secure boot resource", err, nil)
+	}
+
+	values := map[string]interface{}{
+		"system_id":                odataIDMember(secureBoot.ODataID, "Systems"),
+		"secure_boot_enable":       secureBoot.SecureBootEnable,
+		"secure_boot_mode":         string(secureBoot.SecureBootMode),
+		"secure_boot_current_boot": string(secureBoot.SecureBootCurrentBoot),
+	}
+	for k, v := range values {
+		if err := d.Set(k, v); err != nil {
+			return diag.Errorf("error setting %s: %s", k, err)
+		}
+	}
+	return nil
+}
+
+func getSecureBootResource(service *gofish.Service, systemID string) (*redfish.SecureBoot, error) {
+	system, err := getSystemResource(service, systemID)
+	if err != nil {
+		return nil, err
+	}
+	return system.SecureBoot()
+}
@@ -0,0 +1,213 @@
+package redfish
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/stmcginnis/gofish"
+	redfishcommon "github.com/stmcginnis/gofish/common"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// secureBootCertificateAttributePaths locates the attributes setting the properties of the certificates enrolled
+var secureBootCertificateAttributePaths = attributePaths{
+	"CertificateString":  "certificate_string",
+	"UefiSignatureOwner": "uefi_signature_owner",
+}
+
+func resourceRedfishSecureBootCertificate() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceRedfishSecureBootCertificateCreate,
+		ReadContext:   resourceRedfishSecureBootCertificateRead,
+		UpdateContext: resourceRedfishSecureBootCertificateUpdate,
+		DeleteContext: resourceRedfishSecureBootCertificateDelete,
+		Schema:        getResourceRedfishSecureBootCertificateSchema(),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceRedfishSecureBootCertificateImport,
+		},
+	}
+}
+
+func getResourceRedfishSecureBootCertificateSchema() map[string]*schema.Schema {
+	s := map[string]*schema.Schema{
+		"redfish_alias":  redfishAliasSchema(),
+		"redfish_server": redfishServerSchema(),
+		"system_id":      systemIDSchema(true),
+		"database_id": {
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+			Description: "ID of the Secure Boot database the certificate is enrolled in. Applicable values are 'db', " +
+				"the signatures allowed to boot, 'dbx', the forbidden ones, 'KEK', the keys allowed to change them, and " +
+				"'PK', the platform key, which the system only has one of.",
+			ValidateFunc: validation.StringInSlice([]string{"db", "dbx", "KEK", "PK"}, false),
+		},
+		"certificate_string": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ForceNew:     true,
+			Description:  "The PEM encoded certificate to enroll. Changing it enrolls the new certificate in place of the old one.",
+			ValidateFunc: validatePEMCertificate,
+		},
+		"uefi_signature_owner": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    true,
+			ForceNew:    true,
+			Description: "The GUID of the owner of the certificate in the UEFI signature list, chosen by the system when not set.",
+		},
+	}
+	for k, v := range getDataSourceRedfishSecureBootCertificatesSchema()["certificates"].Elem.(*schema.Resource).Schema {
+		if _, ok := s[k]; !ok && k != "odata_id" && k != "id" {
+			s[k] = v
+		}
+	}
+	return s
+}
+
+func resourceRedfishSecureBootCertificateCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	service, err := NewConfig(m.(*providerMeta).config, d)
+	if err != nil {
+		return diag.Errorf(err.Error())
+	}
+
+	// Lock the mutex to avoid race conditions with other resources
+	redfishMutexKV.Lock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
+	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
+
+	secureBoot, err := getSecureBootResource(service, d.Get("system_id").(string))
+	if err != nil {
+		return redfishDiagnostics("error fetching the secure boot resource", err, nil)
+	}
+	_, databases, err := getSecureBootDatabases(secureBoot)
+	if err != nil {
+		return redfishDiagnostics("error fetching the secure boot databases", err, nil)
+	}
+	databaseID := d.Get("database_id").(string)
+	var certificatesURI string
+	for _, database := range databases {
+		if database.DatabaseID == databaseID {
+			certificatesURI = database.Certificates.String()
+		}
+	}
+	if certificatesURI == "" {
+		return diag.Errorf("the system has no secure boot database %s", databaseID)
+	}
+
+	payload := map[string]interface{}{
+		"CertificateString": d.Get("certificate_string"),
+		"CertificateType":   string(redfish.PEMCertificateType),
+	}
+	if owner, ok := d.GetOk("uefi_signature_owner"); ok {
+		payload["UefiSignatureOwner"] = owner
+	}
+	log.Printf("[DEBUG] Enrolling a certificate in the secure boot database %s", databaseID)
+	uri, err := enrollSecureBootCertificate(secureBoot.GetClient(), certificatesURI, payload)
+	if err != nil {
+		return redfishDiagnostics(fmt.Sprintf("error enrolling the certificate in the secure boot database %s", databaseID), err, secureBootCertificateAttributePaths)
+	}
+
+	d.SetId(uri)
+	return readRedfishSecureBootCertificate(service, d)
+}
+
+func resourceRedfishSecureBootCertificateRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	service, err := NewConfig(m.(*providerMeta).config, d)
+	if err != nil {
+		return diag.Errorf(err.Error())
+	}
+	return readRedfishSecureBootCertificate(service, d)
+}
+
+// resourceRedfishSecureBootCertificateUpdate only takes the changes of the server settings, the certificate being
+// enrolled again when it changes
+func resourceRedfishSecureBootCertificateUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return resourceRedfishSecureBootCertificateRead(ctx, d, m)
+}
+
+// resourceRedfishSecureBootCertificateDelete deletes the certificate from its secure boot database
+func resourceRedfishSecureBootCertificateDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	service, err := NewConfig(m.(*providerMeta).config, d)
+	if err != nil {
+		return diag.Errorf(err.Error())
+	}
+
+	redfishMutexKV.Lock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
+	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
+
+	log.Printf("[DEBUG] Deleting the secure boot certificate %s", d.Id())
+	resp, err := service.GetClient().Delete(d.Id())
+	if err != nil {
+		if e, ok := err.(*redfishcommon.Error); !ok || e.HTTPReturnedStatusCode != http.StatusNotFound {
+			return redfishDiagnostics("error deleting the secure boot certificate", err, nil)
+		}
+	} else {
+		resp.Body.Close()
+	}
+	d.SetId("")
+	return nil
+}
+
+// resourceRedfishSecureBootCertificateImport imports a certificate of a secure boot database from an ID such as
+// https://my-server-1.myawesomecompany.org|/redfish/v1/Systems/System.Embedded.1/SecureBoot/SecureBootDatabases/db/Certificates/SecureBoot.Cert.1
+func resourceRedfishSecureBootCertificateImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	odataID, err := parseImportID(d, m)
+	if err != nil {
+		return nil, err
+	}
+	d.SetId(odataID)
+	if err := d.Set("system_id", odataIDMember(odataID, "Systems")); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func readRedfishSecureBootCertificate(service *gofish.Service, d *schema.ResourceData) diag.Diagnostics {
+	certificate, err := redfish.GetCertificate(service.GetClient(), d.Id())
+	if err != nil {
+		if e, ok := err.(*redfishcommon.Error); ok && e.HTTPReturnedStatusCode == http.StatusNotFound {
+			log.Printf("[DEBUG] The secure boot certificate %s no longer exists", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return redfishDiagnostics("error fetching the secure boot certificate", err, nil)
+	}
+
+	values := flattenSecureBootCertificate(certificate)
+	values["system_id"] = odataIDMember(certificate.ODataID, "Systems")
+	values["database_id"] = odataIDMember(certificate.ODataID, "SecureBootDatabases")
+	// Systems may write the certificate otherwise than it was enrolled, the configured one is kept once known
+	if d.Get("certificate_string") == "" {
+		values["certificate_string"] = certificate.CertificateString
+	}
+	for k, v := range values {
+		if err := d.Set(k, v); err != nil {
+			return diag.Errorf("error setting %s: %s", k, err)
+		}
+	}
+	return nil
+}
+
+// enrollSecureBootCertificate posts a certificate to the certificate collection of a secure boot database and returns
+// the URI of the certificate created, given by the Location header or else by the body of the response
+func enrollSecureBootCertificate(client redfishcommon.Client, certificatesURI string, payload map[string]interface{}) (string, error) {
+	resp, err := client.Post(certificatesURI, payload)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if location, err := resp.Location(); err == nil {
+		return location.EscapedPath(), nil
+	}
+	var created redfishcommon.Entity
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil || created.ODataID == "" {
+		return "", fmt.Errorf("the system did not give the URI of the certificate enrolled in %s", certificatesURI)
+	}
+	return created.ODataID, nil
+}
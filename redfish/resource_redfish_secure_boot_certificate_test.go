@@ -0,0 +1,98 @@
+package redfish
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/dell/terraform-provider-redfish/internal/emulator"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+const emulatedDbCertificatesURI = "/redfish/v1/Systems/System.Embedded.1/SecureBoot/SecureBootDatabases/db/Certificates"
+
+// Test to enroll a self-signed certificate in the db database, deleting it afterwards
+func TestAccRedfishSecureBootCertificate_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceSecureBootCertificateConfig(creds, "db", emulator.NewCertificate("Example Signing Key")),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("redfish_secure_boot_certificate.certificate", "fingerprint"),
+				),
+			},
+		},
+	})
+}
+
+// Test to enroll a certificate in the db database of an emulated iDRAC, replace it and delete it
+func TestRedfishSecureBootCertificate_emulated(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		CheckDestroy: func(*terraform.State) error {
+			return checkEmulatedResource(server, emulatedDbCertificatesURI, "Members@odata.count", float64(1))(nil)
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceSecureBootCertificateConfig(creds, "db", emulator.NewCertificate("Example Signing Key")),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("redfish_secure_boot_certificate.certificate", "id", regexp.MustCompile("^"+emulatedDbCertificatesURI+"/")),
+					resource.TestCheckResourceAttr("redfish_secure_boot_certificate.certificate", "system_id", "System.Embedded.1"),
+					resource.TestCheckResourceAttr("redfish_secure_boot_certificate.certificate", "subject_common_name", "Example Signing Key"),
+					resource.TestCheckResourceAttr("redfish_secure_boot_certificate.certificate", "fingerprint_hash_algorithm", "TPM_ALG_SHA256"),
+					resource.TestMatchResourceAttr("redfish_secure_boot_certificate.certificate", "fingerprint", regexp.MustCompile("^([0-9A-F]{2}:){31}[0-9A-F]{2}$")),
+					checkEmulatedResource(server, emulatedDbCertificatesURI, "Members@odata.count", float64(2)),
+				),
+			},
+			{
+				Config: testAccRedfishResourceSecureBootCertificateConfig(creds, "db", emulator.NewCertificate("Rotated Signing Key")),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_secure_boot_certificate.certificate", "subject_common_name", "Rotated Signing Key"),
+					checkEmulatedResource(server, emulatedDbCertificatesURI, "Members@odata.count", float64(2)),
+				),
+			},
+		},
+	})
+}
+
+// Test that a certificate which is not PEM encoded fails the plan
+func TestRedfishSecureBootCertificate_emulatedInvalid(t *testing.T) {
+	_, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccRedfishResourceSecureBootCertificateConfig(creds, "db", "not a certificate"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("certificate_string must be a PEM encoded certificate"),
+			},
+		},
+	})
+}
+
+func testAccRedfishResourceSecureBootCertificateConfig(testingInfo TestingServerCredentials, databaseID, certificate string) string {
+	return fmt.Sprintf(`
+		resource "redfish_secure_boot_certificate" "certificate" {
+		  redfish_server {
+			user = "%s"
+			password = "%s"
+			endpoint = "https://%s"
+			ssl_insecure = true
+		  }
+
+		  database_id = "%s"
+		  certificate_string = <<-EOT
+%s
+EOT
+		}
+		`,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+		databaseID,
+		certificate,
+	)
+}
@@ -0,0 +1,93 @@
+package redfish
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// Test to enable Secure Boot
+func TestAccRedfishSecureBoot_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceSecureBootConfig(creds, `secure_boot_enable = true`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_secure_boot.secure_boot", "secure_boot_enable", "true"),
+				),
+			},
+		},
+	})
+}
+
+// Test to enable Secure Boot on an emulated iDRAC, and then delete its platform key
+func TestRedfishSecureBoot_emulated(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceSecureBootConfig(creds, `
+					secure_boot_enable = true
+					secure_boot_mode = "UserMode"
+					reset_type = "ForceRestart"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_secure_boot.secure_boot", "id", "/redfish/v1/Systems/System.Embedded.1/SecureBoot"),
+					resource.TestCheckResourceAttr("redfish_secure_boot.secure_boot", "secure_boot_current_boot", "Enabled"),
+					checkEmulatedResource(server, "/redfish/v1/Systems/System.Embedded.1/SecureBoot", "SecureBootEnable", true),
+					checkEmulatedResource(server, "/redfish/v1/Systems/System.Embedded.1/SecureBoot", "SecureBootMode", "UserMode"),
+				),
+			},
+			{
+				Config: testAccRedfishResourceSecureBootConfig(creds, `
+					secure_boot_enable = true
+					reset_keys = "DeletePK"
+					reset_type = "ForceRestart"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_secure_boot.secure_boot", "secure_boot_mode", "SetupMode"),
+					resource.TestCheckResourceAttr("redfish_secure_boot.secure_boot", "secure_boot_current_boot", "Disabled"),
+				),
+			},
+		},
+	})
+}
+
+// Test that a mode cannot be set along with a key reset leaving the system in setup mode
+func TestRedfishSecureBoot_emulatedInvalid(t *testing.T) {
+	_, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceSecureBootConfig(creds, `
+					secure_boot_mode = "DeployedMode"
+					reset_keys = "DeleteAllKeys"`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("secure_boot_mode cannot be set when reset_keys is DeleteAllKeys"),
+			},
+		},
+	})
+}
+
+func testAccRedfishResourceSecureBootConfig(testingInfo TestingServerCredentials, settings string) string {
+	return fmt.Sprintf(`
+		resource "redfish_secure_boot" "secure_boot" {
+		  redfish_server {
+			user = "%s"
+			password = "%s"
+			endpoint = "https://%s"
+			ssl_insecure = true
+		  }
+
+		  %s
+		}
+		`,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+		settings,
+	)
+}
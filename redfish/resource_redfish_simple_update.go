@@ -16,14 +16,23 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
+// intervalSimpleUpdateJobCheckTime is the time in seconds between checks of update jobs
+const intervalSimpleUpdateJobCheckTime = 10
+
 const (
-	defaultSimpleUpdateResetTimeout  int = 120
-	defaultSimpleUpdateJobTimeout    int = 1200
-	intervalSimpleUpdateJobCheckTime int = 10
+	// defaultSimpleUpdateTimeout bounds the whole update, upload of the package and reset of the server included
+	defaultSimpleUpdateTimeout = 30 * time.Minute
 )
 
+// simpleUpdateAttributePaths locates the attributes setting the parameters of the SimpleUpdate action
+var simpleUpdateAttributePaths = attributePaths{
+	"ImageURI":         "target_firmware_image",
+	"TransferProtocol": "transfer_protocol",
+}
+
 func resourceRedfishSimpleUpdate() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceRedfishSimpleUpdateCreate,
@@ -31,41 +40,21 @@ func resourceRedfishSimpleUpdate() *schema.Resource {
 		UpdateContext: resourceRedfishSimpleUpdateUpdate,
 		DeleteContext: resourceRedfishSimpleUpdateDelete,
 		Schema:        getResourceRedfishSimpleUpdateSchema(),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceRedfishSimpleUpdateImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultSimpleUpdateTimeout),
+			Update: schema.DefaultTimeout(defaultSimpleUpdateTimeout),
+		},
 	}
 }
 
 func getResourceRedfishSimpleUpdateSchema() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
-		"redfish_server": {
-			Type:        schema.TypeList,
-			Required:    true,
-			Description: "List of server BMCs and their respective user credentials",
-			Elem: &schema.Resource{
-				Schema: map[string]*schema.Schema{
-					"user": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Description: "User name for login",
-					},
-					"password": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Description: "User password for login",
-						Sensitive:   true,
-					},
-					"endpoint": {
-						Type:        schema.TypeString,
-						Required:    true,
-						Description: "Server BMC IP address or hostname",
-					},
-					"ssl_insecure": {
-						Type:        schema.TypeBool,
-						Optional:    true,
-						Description: "This field indicates whether the SSL/TLS certificate must be verified or not",
-					},
-				},
-			},
-		},
+		"redfish_alias":  redfishAliasSchema(),
+		"redfish_server": redfishServerSchema(),
+		"system_id":      systemIDSchema(true),
 		"transfer_protocol": {
 			Type:     schema.TypeString,
 			Required: true,
@@ -105,14 +94,18 @@ func getResourceRedfishSimpleUpdateSchema() map[string]*schema.Schema {
 			}, false),
 		},
 		"reset_timeout": {
-			Type:        schema.TypeInt,
-			Optional:    true,
-			Description: "reset_timeout is the time in seconds that the provider waits for the server to be reset before timing out.",
+			Type:     schema.TypeInt,
+			Optional: true,
+			Description: "reset_timeout is the time in seconds that the provider waits for the server to be reset before timing out. " +
+				"Deprecated, use the timeouts block instead.",
+			Deprecated: "Use the create and update timeouts of the timeouts block instead",
 		},
 		"simple_update_job_timeout": {
-			Type:        schema.TypeInt,
-			Optional:    true,
-			Description: "simple_update_job_timeout is the time in seconds that the provider waits for the simple update job to be completed before timing out.",
+			Type:     schema.TypeInt,
+			Optional: true,
+			Description: "simple_update_job_timeout is the time in seconds that the provider waits for the simple update job to be completed before timing out. " +
+				"Deprecated, use the timeouts block instead.",
+			Deprecated: "Use the create and update timeouts of the timeouts block instead",
 		},
 		"software_id": {
 			Type:        schema.TypeString,
@@ -128,7 +121,7 @@ func getResourceRedfishSimpleUpdateSchema() map[string]*schema.Schema {
 }
 
 func resourceRedfishSimpleUpdateCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	service, err := NewConfig(m.(*schema.ResourceData), d)
+	service, err := NewConfig(m.(*providerMeta).config, d)
 	if err != nil {
 		return diag.Errorf(err.Error())
 	}
@@ -136,7 +129,7 @@ func resourceRedfishSimpleUpdateCreate(ctx context.Context, d *schema.ResourceDa
 }
 
 func resourceRedfishSimpleUpdateRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	service, err := NewConfig(m.(*schema.ResourceData), d)
+	service, err := NewConfig(m.(*providerMeta).config, d)
 	if err != nil {
 		return diag.Errorf(err.Error())
 	}
@@ -144,7 +137,7 @@ func resourceRedfishSimpleUpdateRead(ctx context.Context, d *schema.ResourceData
 }
 
 func resourceRedfishSimpleUpdateUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	service, err := NewConfig(m.(*schema.ResourceData), d)
+	service, err := NewConfig(m.(*providerMeta).config, d)
 	if err != nil {
 		return diag.Errorf(err.Error())
 	}
@@ -155,18 +148,31 @@ func resourceRedfishSimpleUpdateUpdate(ctx context.Context, d *schema.ResourceDa
 }
 
 func resourceRedfishSimpleUpdateDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	service, err := NewConfig(m.(*schema.ResourceData), d)
+	service, err := NewConfig(m.(*providerMeta).config, d)
 	if err != nil {
 		return diag.Errorf(err.Error())
 	}
 	return deleteRedfishSimpleUpdate(service, d)
 }
 
+// resourceRedfishSimpleUpdateImport imports an installed firmware from an ID such as
+// https://my-server-1.myawesomecompany.org|/redfish/v1/UpdateService/FirmwareInventory/Installed-159-1.15.0.
+// How the package was transferred cannot be read back, so the firmware image in the configuration is applied on the
+// next apply.
+func resourceRedfishSimpleUpdateImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	odataID, err := parseImportID(d, m)
+	if err != nil {
+		return nil, err
+	}
+	d.SetId(odataID)
+	return []*schema.ResourceData{d}, nil
+}
+
 func readRedfishSimpleUpdate(service *gofish.Service, d *schema.ResourceData) diag.Diagnostics {
 	var diags diag.Diagnostics
 
 	// Try to get software inventory
-	_, err := redfish.GetSoftwareInventory(service.GetClient(), d.Id())
+	swInventory, err := redfish.GetSoftwareInventory(service.GetClient(), d.Id())
 	if err != nil {
 		_, ok := err.(*redfishcommon.Error)
 		if !ok {
@@ -174,8 +180,12 @@ func readRedfishSimpleUpdate(service *gofish.Service, d *schema.ResourceData) di
 		}
 		// the firmware package previously applied has changed, trigger update
 		d.SetId("")
+		return diags
 	}
 
+	d.Set("software_id", swInventory.SoftwareID)
+	d.Set("version", swInventory.Version)
+
 	return diags
 }
 
@@ -183,26 +193,26 @@ func updateRedfishSimpleUpdate(ctx context.Context, service *gofish.Service, d *
 	var diags diag.Diagnostics
 
 	// Lock the mutex to avoid race conditions with other resources
-	redfishMutexKV.Lock(getRedfishServerEndpoint(d))
-	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(d))
+	redfishMutexKV.Lock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
+	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
 
 	transferProtocol := d.Get("transfer_protocol").(string)
 	targetFirmwareImage := d.Get("target_firmware_image").(string)
 	resetType := d.Get("reset_type").(string)
 
 	// Check if chosen reset type is supported before doing anything else
-	systems, err := service.Systems()
+	system, err := getSystemResource(service, d.Get("system_id").(string))
 	if err != nil {
-		return diag.Errorf("Couldn't retrieve allowed reset types from systems - %s", err)
+		return redfishDiagnostics("Couldn't retrieve allowed reset types from systems", err, nil)
 	}
-	if ok := checkResetType(resetType, systems[0].SupportedResetTypes); !ok {
+	if ok := checkResetType(resetType, system.SupportedResetTypes); !ok {
 		return diag.Errorf("reset type %s is not available in this redfish implementation", resetType)
 	}
 
 	// Get update service from root
 	updateService, err := service.UpdateService()
 	if err != nil {
-		return diag.Errorf("error while retrieving UpdateService - %s", err)
+		return redfishDiagnostics("error while retrieving UpdateService", err, nil)
 	}
 
 	//Check if the transfer protocol is available in the redfish instance
@@ -216,21 +226,21 @@ func updateRedfishSimpleUpdate(ctx context.Context, service *gofish.Service, d *
 	}
 
 	if transferProtocol == "NFS" {
-		err := pullUpdate(service, d, resetType)
+		err := pullUpdate(ctx, service, d, m.(*providerMeta).waits, resetType)
 		if err != nil {
-			return diag.Errorf(" %s", err)
+			return redfishDiagnostics("there was an issue when updating the firmware", err, simpleUpdateAttributePaths)
 		}
 	} else if transferProtocol == "HTTP" || transferProtocol == "HTTPS" {
 		if strings.HasPrefix(targetFirmwareImage, "http") {
-			err := pullUpdate(service, d, resetType)
+			err := pullUpdate(ctx, service, d, m.(*providerMeta).waits, resetType)
 			if err != nil {
-				return diag.Errorf(" %s", err)
+				return redfishDiagnostics("there was an issue when updating the firmware", err, simpleUpdateAttributePaths)
 			}
 		} else {
 			// Get ETag from FW inventory
 			response, err := service.GetClient().Get(updateService.FirmwareInventory)
 			if err != nil {
-				diag.Errorf("error while retrieving Etag from FirmwareInventory")
+				return redfishDiagnostics("error while retrieving Etag from FirmwareInventory", err, nil)
 			}
 			response.Body.Close()
 			etag := response.Header.Get("ETag")
@@ -255,7 +265,7 @@ func updateRedfishSimpleUpdate(ctx context.Context, service *gofish.Service, d *
 			// Upload FW Package to FW inventory
 			response, err = service.GetClient().PostMultipartWithHeaders(updateService.HTTPPushURI, payload, customHeaders)
 			if err != nil {
-				return diag.Errorf("there was an issue when uploading FW package to redfish - %s", err)
+				return redfishDiagnostics("there was an issue when uploading FW package to redfish", err, nil)
 			}
 			response.Body.Close()
 			packageLocation := response.Header.Get("Location")
@@ -263,7 +273,7 @@ func updateRedfishSimpleUpdate(ctx context.Context, service *gofish.Service, d *
 			// Get package information ( SoftwareID - Version )
 			packageInformation, err := redfish.GetSoftwareInventory(service.GetClient(), packageLocation)
 			if err != nil {
-				return diag.Errorf("there was an issue when retrieving uploaded package information - %s", err)
+				return redfishDiagnostics("there was an issue when retrieving uploaded package information", err, nil)
 			}
 
 			// Set payload for POST call that'll trigger the update job scheduling
@@ -276,19 +286,19 @@ func updateRedfishSimpleUpdate(ctx context.Context, service *gofish.Service, d *
 			response, err = service.GetClient().Post(updateService.UpdateServiceTarget, triggerUpdatePayload)
 			if err != nil {
 				// Delete uploaded package - TBD
-				return diag.Errorf("there was an issue when scheduling the update job - %s", err)
+				return redfishDiagnostics("there was an issue when scheduling the update job", err, simpleUpdateAttributePaths)
 			}
 			response.Body.Close()
 
-			err = updateJobStatus(service, d, response, resetType)
+			err = updateJobStatus(ctx, service, d, m.(*providerMeta).waits, response, resetType)
 			if err != nil {
-				diag.Errorf("Error running job %v", err)
+				return redfishDiagnostics("Error running job", err, nil)
 			}
 			// Get updated FW inventory
 			fwInventory, err := updateService.FirmwareInventories()
 			if err != nil {
 				// TBD - HOW TO HANDLE WHEN FAILS BUT FIRMWARE WAS INSTALLED?
-				return diag.Errorf("error when getting firmware inventory - %s", err)
+				return redfishDiagnostics("error when getting firmware inventory", err, nil)
 			}
 
 			// Get fw ID
@@ -358,12 +368,12 @@ func getFWfromInventory(softwareInventories []*redfish.SoftwareInventory, softwa
 	}
 	return nil, fmt.Errorf("couldn't find FW on Firmware inventory")
 }
-func pullUpdate(service *gofish.Service, d *schema.ResourceData, resetType string) error {
+func pullUpdate(ctx context.Context, service *gofish.Service, d *schema.ResourceData, waits waitTimes, resetType string) error {
 
 	// Get update service from root
 	updateService, err := service.UpdateService()
 	if err != nil {
-		return fmt.Errorf("error while retrieving UpdateService - %s", err)
+		return fmt.Errorf("error while retrieving UpdateService - %w", err)
 	}
 
 	protocol := d.Get("transfer_protocol")
@@ -377,15 +387,15 @@ func pullUpdate(service *gofish.Service, d *schema.ResourceData, resetType strin
 	response, err := service.GetClient().Post(httpURI, payload)
 	if err != nil {
 		// Delete uploaded package - TBD
-		return fmt.Errorf("there was an issue when scheduling the update job - %s", err)
+		return fmt.Errorf("there was an issue when scheduling the update job - %w", err)
 	}
 
 	// Get jobid
 	jobID := response.Header.Get("Location")
-	err = updateJobStatus(service, d, response, resetType)
+	err = updateJobStatus(ctx, service, d, waits, response, resetType)
 	if err != nil {
 		// Delete uploaded package - TBD
-		return fmt.Errorf("there was an issue when waiting for the job to complete - %s", err)
+		return fmt.Errorf("there was an issue when waiting for the job to complete - %w", err)
 	}
 
 	job, err := redfish.GetTask(service.GetClient(), jobID)
@@ -398,38 +408,32 @@ func pullUpdate(service *gofish.Service, d *schema.ResourceData, resetType strin
 
 	swInventory, err := redfish.GetSoftwareInventory(service.GetClient(), d.Id())
 	if err != nil {
-		return fmt.Errorf("unable to fetch data %v", err)
+		return fmt.Errorf("unable to fetch data %w", err)
 	}
 	d.SetId(swInventory.ODataID)
 	return nil
 }
 
-func updateJobStatus(service *gofish.Service, d *schema.ResourceData, response *http.Response, resetType string) error {
+func updateJobStatus(ctx context.Context, service *gofish.Service, d *schema.ResourceData, waits waitTimes, response *http.Response, resetType string) error {
 	// Get jobid
 	jobID := response.Header.Get("Location")
 
-	resetTimeout, ok := d.GetOk("reset_timeout")
-	if !ok {
-		resetTimeout = defaultSimpleUpdateResetTimeout
-	}
-	simpleUpdateJobTimeout, ok := d.GetOk("simple_update_job_timeout")
-	if !ok {
-		simpleUpdateJobTimeout = defaultSimpleUpdateJobTimeout
-	}
-	log.Printf("[DEBUG] resetTimeout is set to %d and simpleUpdateJobTimeout to %d", resetTimeout.(int), simpleUpdateJobTimeout.(int))
+	resetTimeout := deprecatedTimeout(d, "reset_timeout", 0)
+	simpleUpdateJobTimeout := deprecatedTimeout(d, "simple_update_job_timeout", 0)
+	log.Printf("[DEBUG] resetTimeout is set to %d and simpleUpdateJobTimeout to %d", resetTimeout, simpleUpdateJobTimeout)
 
 	// Reboot the server
-	_, diags := PowerOperation(resetType, resetTimeout.(int), intervalSimpleUpdateJobCheckTime, service)
+	_, diags := PowerOperation(ctx, resetType, resetTimeout, waits.simpleUpdateJobCheck, service, d.Get("system_id").(string))
 	if diags.HasError() {
 		// Delete uploaded package - TBD
-		return fmt.Errorf("there was an issue when restarting the server")
+		return fmt.Errorf("there was an issue when restarting the server - %s: %s", diags[0].Summary, diags[0].Detail)
 	}
 
 	// Check JID
-	err := common.WaitForJobToFinish(service, jobID, intervalSimpleUpdateJobCheckTime, simpleUpdateJobTimeout.(int))
+	err := common.WaitForJobToFinish(ctx, service, jobID, waits.simpleUpdateJobCheck, simpleUpdateJobTimeout)
 	if err != nil {
 		// Delete uploaded package - TBD
-		return fmt.Errorf("there was an issue when waiting for the job to complete - %s", err)
+		return fmt.Errorf("there was an issue when waiting for the job to complete - %w", err)
 	}
 
 	return nil
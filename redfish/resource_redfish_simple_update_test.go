@@ -2,8 +2,11 @@ package redfish
 
 import (
 	"fmt"
+	"github.com/dell/terraform-provider-redfish/internal/emulator"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"net/http"
 	"os"
+	"path/filepath"
 	"regexp"
 	"testing"
 )
@@ -73,6 +76,76 @@ func TestAccRedfishSimpleUpdate_InvalidProto(t *testing.T) {
 		},
 	})
 }
+
+// Test to upload an update package to an emulated iDRAC and install it
+func TestRedfishSimpleUpdate_emulated(t *testing.T) {
+	_, creds := newEmulatedServer(t)
+	image := filepath.Join(t.TempDir(), "BIOS_FXC54_WN64_1.15.0.EXE")
+	if err := os.WriteFile(image, []byte("update package"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceUpdateConfig(creds, "HTTP", image),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_simple_update.update", "software_id", "159"),
+					resource.TestCheckResourceAttr("redfish_simple_update.update", "version", "1.15.0"),
+				),
+			},
+		},
+	})
+}
+
+// Test that an update job ending in an exception is reported
+func TestRedfishSimpleUpdate_emulatedJobException(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	server.Inject(emulator.Fault{
+		Method:   http.MethodPost,
+		URI:      "/redfish/v1/UpdateService/Actions/UpdateService.SimpleUpdate",
+		JobState: emulator.JobException,
+	})
+	image := filepath.Join(t.TempDir(), "BIOS_FXC54_WN64_1.15.0.EXE")
+	if err := os.WriteFile(image, []byte("update package"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccRedfishResourceUpdateConfig(creds, "HTTP", image),
+				ExpectError: regexp.MustCompile("Unable to complete the job"),
+			},
+		},
+	})
+}
+
+// Test that a rejected upload of the update package is reported
+func TestRedfishSimpleUpdate_emulatedUploadError(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	server.Inject(emulator.Fault{
+		Method:     http.MethodPost,
+		URI:        "/redfish/v1/UpdateService/FirmwareInventory",
+		StatusCode: http.StatusPreconditionFailed,
+		MessageID:  "Base.1.12.PreconditionFailed",
+		Message:    "The ETag supplied did not match the ETag required to change this resource.",
+	})
+	image := filepath.Join(t.TempDir(), "BIOS_FXC54_WN64_1.15.0.EXE")
+	if err := os.WriteFile(image, []byte("update package"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccRedfishResourceUpdateConfig(creds, "HTTP", image),
+				ExpectError: regexp.MustCompile("there was an issue when uploading FW package to redfish"),
+			},
+		},
+	})
+}
+
 func testAccRedfishResourceUpdateConfig(testingInfo TestingServerCredentials,
 	transferProtocol string,
 	imagePath string) string {
@@ -0,0 +1,372 @@
+package redfish
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/stmcginnis/gofish"
+	redfishcommon "github.com/stmcginnis/gofish/common"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// defaultStorageControllerTimeout bounds the change of the controller settings and actions, resets of the server and
+// RAID jobs included
+const defaultStorageControllerTimeout = 30 * time.Minute
+
+// storageControllerAttributePaths locates the attributes setting the storage controller
+var storageControllerAttributePaths = attributePaths{
+	"Oem/Dell/DellStorageController/ControllerMode":                      "controller_mode",
+	"Oem/Dell/DellStorageController/PatrolReadMode":                      "patrol_read_mode",
+	"Oem/Dell/DellStorageController/RebuildRatePercent":                  "rebuild_rate_percent",
+	"Oem/Dell/DellStorageController/CheckConsistencyRatePercent":         "check_consistency_rate_percent",
+	"Oem/Dell/DellStorageController/BackgroundInitializationRatePercent": "background_initialization_rate_percent",
+	"@Redfish.SettingsApplyTime/ApplyTime":                               "settings_apply_time",
+	"@Redfish.OperationApplyTime":                                        "settings_apply_time",
+	"TargetFQDD":                                                         "storage_controller_id",
+}
+
+func resourceRedfishStorageController() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceRedfishStorageControllerUpdate,
+		ReadContext:   resourceRedfishStorageControllerRead,
+		UpdateContext: resourceRedfishStorageControllerUpdate,
+		DeleteContext: resourceRedfishStorageControllerDelete,
+		Schema:        getResourceRedfishStorageControllerSchema(),
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultStorageControllerTimeout),
+			Update: schema.DefaultTimeout(defaultStorageControllerTimeout),
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceRedfishStorageControllerImport,
+		},
+	}
+}
+
+func getResourceRedfishStorageControllerSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"redfish_alias":  redfishAliasSchema(),
+		"redfish_server": redfishServerSchema(),
+		"system_id":      systemIDSchema(true),
+		"storage_controller_id": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "ID of the storage controller. I.e: RAID.Integrated.1-1",
+		},
+		"controller_mode": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+			Description: "Mode of the controller, such as \"RAID\" or \"HBA\", among the ones the controller allows. " +
+				"Controllers only change mode on reset and once they have no volumes.",
+			ValidateFunc: validation.StringIsNotEmpty,
+		},
+		"patrol_read_mode": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+			Description: "Mode of the patrol read checking the drives for errors, such as \"Automatic\", \"Manual\" or " +
+				"\"Disabled\", among the ones the controller allows.",
+			ValidateFunc: validation.StringIsNotEmpty,
+		},
+		"rebuild_rate_percent": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Computed:     true,
+			Description:  "Percentage of the controller resources given to rebuilding the failed drives of volumes",
+			ValidateFunc: validation.IntBetween(0, 100),
+		},
+		"check_consistency_rate_percent": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Computed:     true,
+			Description:  "Percentage of the controller resources given to checking the consistency of redundant volumes",
+			ValidateFunc: validation.IntBetween(0, 100),
+		},
+		"background_initialization_rate_percent": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Computed:     true,
+			Description:  "Percentage of the controller resources given to the background initialization (BGI) of new volumes",
+			ValidateFunc: validation.IntBetween(0, 100),
+		},
+		"controller_action": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Description: "Action run on the controller when the resource is created with it and every time it changes to " +
+				"another value. Applicable values are 'ResetConfig', which deletes every volume of the controller and " +
+				"unassigns its hot spares, and 'ClearForeignConfig', which clears the configuration the drives bring " +
+				"from another controller. The action runs before the settings are changed.",
+			ValidateFunc: validation.StringInSlice([]string{"ResetConfig", "ClearForeignConfig"}, false),
+		},
+		"settings_apply_time": {
+			Type:        schema.TypeString,
+			Description: "Flag to make the operation either \"Immediate\" or \"OnReset\". By default value is \"Immediate\"",
+			Optional:    true,
+			ValidateFunc: validation.StringInSlice([]string{
+				string(redfishcommon.ImmediateApplyTime),
+				string(redfishcommon.OnResetApplyTime)}, false),
+			Default: string(redfishcommon.ImmediateApplyTime),
+		},
+		"reset_type": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Description: "Reset type allows to choose the type of restart to apply when settings_apply_time is set to \"OnReset\". " +
+				"Possible values are: \"ForceRestart\", \"GracefulRestart\" or \"PowerCycle\". If not set, \"ForceRestart\" is the default.",
+			ValidateFunc: validation.StringInSlice([]string{
+				string(redfish.ForceRestartResetType),
+				string(redfish.GracefulRestartResetType),
+				string(redfish.PowerCycleResetType),
+			}, false),
+			Default: string(redfish.ForceRestartResetType),
+		},
+	}
+}
+
+func resourceRedfishStorageControllerRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	service, err := NewConfig(m.(*providerMeta).config, d)
+	if err != nil {
+		return diag.Errorf(err.Error())
+	}
+	return readRedfishStorageController(service, d)
+}
+
+func resourceRedfishStorageControllerUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	service, err := NewConfig(m.(*providerMeta).config, d)
+	if err != nil {
+		return diag.Errorf(err.Error())
+	}
+	return updateRedfishStorageController(ctx, service, d, m)
+}
+
+// resourceRedfishStorageControllerDelete removes the resource from the state, leaving the controller settings as they are
+func resourceRedfishStorageControllerDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	d.SetId("")
+	return nil
+}
+
+// resourceRedfishStorageControllerImport imports the settings of a storage controller from an ID such as
+// https://my-server-1.myawesomecompany.org|/redfish/v1/Systems/System.Embedded.1/Storage/RAID.Integrated.1-1
+func resourceRedfishStorageControllerImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	odataID, err := parseImportID(d, m)
+	if err != nil {
+		return nil, err
+	}
+	d.SetId(odataID)
+	if err := d.Set("system_id", odataIDMember(odataID, "Systems")); err != nil {
+		return nil, err
+	}
+	if err := d.Set("storage_controller_id", odataIDMember(odataID, "Storage")); err != nil {
+		return nil, err
+	}
+	if err := setImportDefaults(d, getResourceRedfishStorageControllerSchema()); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func updateRedfishStorageController(ctx context.Context, service *gofish.Service, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Lock the mutex to avoid race conditions with other resources
+	redfishMutexKV.Lock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
+	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
+
+	storageID := d.Get("storage_controller_id").(string)
+	system, err := getSystemResource(service, d.Get("system_id").(string))
+	if err != nil {
+		return redfishDiagnostics("Error when retreiving the Systems from the Redfish API", err, nil)
+	}
+	storageControllers, err := system.Storage()
+	if err != nil {
+		return redfishDiagnostics(fmt.Sprintf("Error when retreiving the Storage from %v from the Redfish API", system.Name), err, nil)
+	}
+	storage, err := getStorageController(storageControllers, storageID)
+	if err != nil {
+		return diag.Errorf("Error when getting the storage struct: %s", err)
+	}
+
+	// The settings and their apply time are checked before the controller action runs, for invalid ones not to fail
+	// the apply once an action such as ResetConfig has deleted the volumes
+	applyTime := d.Get("settings_apply_time").(string)
+	settings, err := getStorageControllerSettings(service.GetClient(), storage.ODataID)
+	if err != nil {
+		return redfishDiagnostics(fmt.Sprintf("Error when reading storage controller %s", storageID), err, nil)
+	}
+	payload, diags := storageControllerPayload(d, storageID, settings)
+	if diags.HasError() {
+		return diags
+	}
+	if supported := settings.Settings.SupportedApplyTimes; len(payload) != 0 && len(supported) != 0 && !contains(supported, applyTime) {
+		return diag.Errorf("Storage controller %s does not support settings_apply_time %s: the supported apply times are %s",
+			storageID, applyTime, strings.Join(supported, ", "))
+	}
+
+	// Changes applied on reset are all staged before the system is reset once, for their jobs to run together. A
+	// controller only runs one RAID job at a time, so an action applied immediately completes before the settings
+	// are changed.
+	onReset := applyTime == string(redfishcommon.OnResetApplyTime)
+	var stagedJobs []string
+	if action, ok := d.GetOk("controller_action"); ok && (d.IsNewResource() || d.HasChange("controller_action")) {
+		raidServiceURI, err := getDellRaidService(system)
+		if err != nil {
+			return redfishDiagnostics("Error when retrieving the RAID service of the system", err, nil)
+		}
+		if raidServiceURI == "" {
+			return diag.Errorf("the system has no Dell RAID service to run %s on storage controller %s with", action, storageID)
+		}
+		log.Printf("[DEBUG] Running %s on the storage controller %s", action, storage.ODataID)
+		jobURI, err := runDellRaidServiceAction(service.GetClient(), raidServiceURI, action.(string), map[string]interface{}{
+			"TargetFQDD":                  storage.ID,
+			"@Redfish.OperationApplyTime": applyTime,
+		})
+		if err != nil {
+			return redfishDiagnostics(fmt.Sprintf("Error when running %s on storage controller %s", action, storageID), err, storageControllerAttributePaths)
+		}
+		if onReset {
+			stagedJobs = append(stagedJobs, jobURI)
+		} else if diags := waitForStorageJobs(ctx, service, d, m.(*providerMeta).waits, jobURI); diags.HasError() {
+			return diags
+		}
+	}
+
+	if len(payload) != 0 {
+		jobURI, err := patchSettingsObject(service.GetClient(), storage.ODataID, map[string]interface{}{
+			"Oem": map[string]interface{}{
+				"Dell": map[string]interface{}{"DellStorageController": payload},
+			},
+			"@Redfish.SettingsApplyTime": map[string]interface{}{"ApplyTime": applyTime},
+		})
+		if err != nil {
+			return redfishDiagnostics(fmt.Sprintf("Error when changing the settings of storage controller %s", storageID), err, storageControllerAttributePaths)
+		}
+		if onReset {
+			stagedJobs = append(stagedJobs, jobURI)
+		} else if diags := waitForStorageJobs(ctx, service, d, m.(*providerMeta).waits, jobURI); diags.HasError() {
+			return diags
+		}
+	}
+
+	if len(stagedJobs) != 0 {
+		if diags := waitForStorageJobs(ctx, service, d, m.(*providerMeta).waits, stagedJobs...); diags.HasError() {
+			return diags
+		}
+	}
+
+	d.SetId(storage.ODataID)
+	return readRedfishStorageController(service, d)
+}
+
+// storageControllerPayload returns the configured settings which differ from the current ones of the controller, once
+// checked against the values it allows
+func storageControllerPayload(d *schema.ResourceData, storageID string, settings storageControllerSettings) (map[string]interface{}, diag.Diagnostics) {
+	controller := settings.Oem.Dell.DellStorageController
+	if controller == nil {
+		controller = &dellStorageController{}
+	}
+	config := d.GetRawConfig()
+	payload := make(map[string]interface{})
+	for _, setting := range []struct {
+		attribute string
+		property  string
+		current   interface{}
+		allowable []string
+	}{
+		{"controller_mode", "ControllerMode", controller.ControllerMode, controller.ControllerModeAllowableValues},
+		{"patrol_read_mode", "PatrolReadMode", controller.PatrolReadMode, controller.PatrolReadModeAllowableValues},
+		{"rebuild_rate_percent", "RebuildRatePercent", controller.RebuildRatePercent, nil},
+		{"check_consistency_rate_percent", "CheckConsistencyRatePercent", controller.CheckConsistencyRatePercent, nil},
+		{"background_initialization_rate_percent", "BackgroundInitializationRatePercent", controller.BackgroundInitializationRatePercent, nil},
+	} {
+		if config.GetAttr(setting.attribute).IsNull() {
+			continue
+		}
+		value := d.Get(setting.attribute)
+		if len(setting.allowable) != 0 && !contains(setting.allowable, value.(string)) {
+			return nil, diag.Errorf("Storage controller %s does not support %s %s: the allowable values are %s",
+				storageID, setting.attribute, value, strings.Join(setting.allowable, ", "))
+		}
+		if value != setting.current {
+			payload[setting.property] = value
+		}
+	}
+	if len(payload) != 0 && settings.Oem.Dell.DellStorageController == nil {
+		return nil, diag.Errorf("storage controller %s does not show its settings", storageID)
+	}
+	return payload, nil
+}
+
+func readRedfishStorageController(service *gofish.Service, d *schema.ResourceData) diag.Diagnostics {
+	system, err := getSystemResource(service, d.Get("system_id").(string))
+	if err != nil {
+		return redfishDiagnostics("Error when retreiving the Systems from the Redfish API", err, nil)
+	}
+	storageControllers, err := system.Storage()
+	if err != nil {
+		return redfishDiagnostics(fmt.Sprintf("Error when retreiving the Storage from %v from the Redfish API", system.Name), err, nil)
+	}
+	storage, err := getStorageController(storageControllers, d.Get("storage_controller_id").(string))
+	if err != nil {
+		return diag.Errorf("Error when getting the storage struct: %s", err)
+	}
+	settings, err := getStorageControllerSettings(service.GetClient(), storage.ODataID)
+	if err != nil {
+		return redfishDiagnostics(fmt.Sprintf("Error when reading storage controller %s", storage.ID), err, nil)
+	}
+
+	controller := settings.Oem.Dell.DellStorageController
+	if controller == nil {
+		controller = &dellStorageController{}
+	}
+	values := map[string]interface{}{
+		"system_id":                              odataIDMember(storage.ODataID, "Systems"),
+		"storage_controller_id":                  storage.ID,
+		"controller_mode":                        controller.ControllerMode,
+		"patrol_read_mode":                       controller.PatrolReadMode,
+		"rebuild_rate_percent":                   controller.RebuildRatePercent,
+		"check_consistency_rate_percent":         controller.CheckConsistencyRatePercent,
+		"background_initialization_rate_percent": controller.BackgroundInitializationRatePercent,
+	}
+	for k, v := range values {
+		if err := d.Set(k, v); err != nil {
+			return diag.Errorf("Error when setting %s: %s", k, err)
+		}
+	}
+	return nil
+}
+
+// storageControllerSettings holds the settings of a storage controller and the way they are changed, which gofish
+// does not support
+type storageControllerSettings struct {
+	Settings struct {
+		SupportedApplyTimes []string
+	} `json:"@Redfish.Settings"`
+	Oem struct {
+		Dell struct {
+			// DellStorageController is nil for controllers which do not show their settings
+			DellStorageController *dellStorageController
+		}
+	}
+}
+
+// dellStorageController is the settings of a storage controller of an iDRAC
+type dellStorageController struct {
+	ControllerMode                      string
+	ControllerModeAllowableValues       []string `json:"ControllerMode@Redfish.AllowableValues"`
+	PatrolReadMode                      string
+	PatrolReadModeAllowableValues       []string `json:"PatrolReadMode@Redfish.AllowableValues"`
+	RebuildRatePercent                  int
+	CheckConsistencyRatePercent         int
+	BackgroundInitializationRatePercent int
+}
+
+// getStorageControllerSettings returns the settings of the storage controller at storageURI
+func getStorageControllerSettings(client redfishcommon.Client, storageURI string) (storageControllerSettings, error) {
+	var settings storageControllerSettings
+	err := getJSON(client, storageURI, &settings)
+	return settings, err
+}
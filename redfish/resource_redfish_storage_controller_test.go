@@ -0,0 +1,195 @@
+package redfish
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/dell/terraform-provider-redfish/internal/emulator"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const storageControllerURI = "/redfish/v1/Systems/System.Embedded.1/Storage/RAID.Integrated.1-1"
+
+// Test to change the patrol read mode and rebuild rate of a storage controller
+func TestAccRedfishStorageController_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceStorageControllerConfig(creds, `
+					patrol_read_mode     = "Manual"
+					rebuild_rate_percent = 60`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_storage_controller.controller", "patrol_read_mode", "Manual"),
+					resource.TestCheckResourceAttr("redfish_storage_controller.controller", "rebuild_rate_percent", "60"),
+				),
+			},
+		},
+	})
+}
+
+// Test to change the settings of a storage controller on an emulated iDRAC, change them again once changed outside of
+// Terraform, and then reset its configuration along with other settings
+func TestRedfishStorageController_emulated(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	settingsConfig := testAccRedfishResourceStorageControllerConfig(creds, `
+		patrol_read_mode     = "Manual"
+		rebuild_rate_percent = 60
+		settings_apply_time  = "OnReset"`)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: settingsConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_storage_controller.controller", "id", storageControllerURI),
+					resource.TestCheckResourceAttr("redfish_storage_controller.controller", "controller_mode", "RAID"),
+					resource.TestCheckResourceAttr("redfish_storage_controller.controller", "check_consistency_rate_percent", "30"),
+					checkEmulatedResource(server, storageControllerURI, "Oem/Dell/DellStorageController/PatrolReadMode", "Manual"),
+					checkEmulatedResource(server, storageControllerURI, "Oem/Dell/DellStorageController/RebuildRatePercent", float64(60)),
+				),
+			},
+			{
+				PreConfig: func() {
+					server.Modify(storageControllerURI, map[string]interface{}{
+						"Oem": map[string]interface{}{"Dell": map[string]interface{}{"DellStorageController": map[string]interface{}{"PatrolReadMode": "Disabled"}}},
+					})
+				},
+				Config:             settingsConfig,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: settingsConfig,
+				Check: resource.ComposeTestCheckFunc(
+					checkEmulatedResource(server, storageControllerURI, "Oem/Dell/DellStorageController/PatrolReadMode", "Manual"),
+				),
+			},
+			{
+				Config: testAccRedfishResourceStorageControllerConfig(creds, `
+					controller_action                      = "ResetConfig"
+					patrol_read_mode                       = "Automatic"
+					background_initialization_rate_percent = 50`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_storage_controller.controller", "controller_action", "ResetConfig"),
+					checkEmulatedResource(server, storageControllerURI, "Oem/Dell/DellStorageController/PatrolReadMode", "Automatic"),
+					checkEmulatedResource(server, storageControllerURI, "Oem/Dell/DellStorageController/BackgroundInitializationRatePercent", float64(50)),
+					checkEmulatedResource(server, storageControllerURI+"/Volumes", "Members@odata.count", float64(0)),
+				),
+			},
+		},
+	})
+}
+
+// Test that an action and settings applied on reset are applied by a single reset
+func TestRedfishStorageController_emulatedSingleReset(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	resetURI := "/redfish/v1/Systems/System.Embedded.1/Actions/ComputerSystem.Reset"
+	// The first reset is served as usual, the next ones fail
+	server.Inject(emulator.Fault{Method: http.MethodPost, URI: resetURI, Count: 1})
+	server.Inject(emulator.Fault{Method: http.MethodPost, URI: resetURI, StatusCode: http.StatusConflict, Message: "second reset"})
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceStorageControllerConfig(creds, `
+					controller_action   = "ResetConfig"
+					patrol_read_mode    = "Manual"
+					settings_apply_time = "OnReset"`),
+				Check: resource.ComposeTestCheckFunc(
+					checkEmulatedResource(server, storageControllerURI, "Oem/Dell/DellStorageController/PatrolReadMode", "Manual"),
+					checkEmulatedResource(server, storageControllerURI+"/Volumes", "Members@odata.count", float64(0)),
+				),
+			},
+		},
+	})
+}
+
+// Test that the settings are checked against the values the controller allows
+func TestRedfishStorageController_emulatedInvalid(t *testing.T) {
+	_, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceStorageControllerConfig(creds, `
+					controller_mode = "JBOD"`),
+				ExpectError: regexp.MustCompile("Storage controller RAID.Integrated.1-1 does not support controller_mode JBOD: the\\s+allowable values are RAID, HBA"),
+			},
+			{
+				Config: testAccRedfishResourceStorageControllerConfig(creds, `
+					rebuild_rate_percent = 101`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("expected rebuild_rate_percent to be in the range \\(0 - 100\\)"),
+			},
+		},
+	})
+}
+
+// Test that invalid settings fail the apply before the controller action deletes the volumes
+func TestRedfishStorageController_emulatedInvalidWithAction(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	volumeConfig := testAccRedfishResourceStorageVolumeMinConfig(
+		creds,
+		"RAID.Integrated.1-1",
+		"TerraformVol1",
+		"NonRedundant",
+		"Solid State Disk 0:0:0")
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: volumeConfig,
+			},
+			{
+				Config: volumeConfig + testAccRedfishResourceStorageControllerConfig(creds, `
+					controller_action = "ResetConfig"
+					controller_mode   = "JBOD"`),
+				ExpectError: regexp.MustCompile("does not support controller_mode JBOD"),
+			},
+			{
+				// A deleted volume would be planned again
+				Config:   volumeConfig,
+				PlanOnly: true,
+			},
+			{
+				PreConfig: func() {
+					server.Modify(storageControllerURI, map[string]interface{}{
+						"@Redfish.Settings": map[string]interface{}{"SupportedApplyTimes": []interface{}{"OnReset"}},
+					})
+				},
+				Config: volumeConfig + testAccRedfishResourceStorageControllerConfig(creds, `
+					controller_action = "ResetConfig"
+					patrol_read_mode  = "Manual"`),
+				ExpectError: regexp.MustCompile("does not support settings_apply_time Immediate"),
+			},
+			{
+				Config:   volumeConfig,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func testAccRedfishResourceStorageControllerConfig(testingInfo TestingServerCredentials, settings string) string {
+	return fmt.Sprintf(`
+		resource "redfish_storage_controller" "controller" {
+		  redfish_server {
+			user         = "%s"
+			password     = "%s"
+			endpoint     = "https://%s"
+			ssl_insecure = true
+		  }
+
+		  storage_controller_id = "RAID.Integrated.1-1"
+		  %s
+		}
+		`,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+		settings,
+	)
+}
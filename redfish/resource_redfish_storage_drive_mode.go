@@ -0,0 +1,295 @@
+package redfish
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/stmcginnis/gofish"
+	redfishcommon "github.com/stmcginnis/gofish/common"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// defaultStorageDriveModeTimeout bounds the conversion of the drives, reset of the server and RAID job included
+const defaultStorageDriveModeTimeout = 30 * time.Minute
+
+const (
+	// raidDriveMode is the mode of the drives a storage controller can make volumes and hot spares of
+	raidDriveMode = "RAID"
+	// nonRAIDDriveMode is the mode of the drives a storage controller passes through to the operating system
+	nonRAIDDriveMode = "NonRAID"
+	// mixedDriveMode is read when some of the drives are in either mode
+	mixedDriveMode = "Mixed"
+	// nonRAIDStatus is the Dell RAID status of the drives in non-RAID mode
+	nonRAIDStatus = "Non-RAID"
+)
+
+// driveModeAttributePaths locates the attributes setting the conversion of the drives
+var driveModeAttributePaths = attributePaths{
+	"PDArray":                     "drives",
+	"@Redfish.OperationApplyTime": "settings_apply_time",
+}
+
+func resourceRedfishStorageDriveMode() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceRedfishStorageDriveModeUpdate,
+		ReadContext:   resourceRedfishStorageDriveModeRead,
+		UpdateContext: resourceRedfishStorageDriveModeUpdate,
+		DeleteContext: resourceRedfishStorageDriveModeDelete,
+		Schema:        getResourceRedfishStorageDriveModeSchema(),
+		CustomizeDiff: resourceRedfishStorageDriveModeCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceRedfishStorageDriveModeImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultStorageDriveModeTimeout),
+			Update: schema.DefaultTimeout(defaultStorageDriveModeTimeout),
+		},
+	}
+}
+
+func getResourceRedfishStorageDriveModeSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"redfish_alias":  redfishAliasSchema(),
+		"redfish_server": redfishServerSchema(),
+		"system_id":      systemIDSchema(true),
+		"storage_controller_id": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "ID of the storage controller of the drives. I.e: RAID.Integrated.1-1",
+		},
+		"drives": {
+			Type:     schema.TypeSet,
+			Required: true,
+			MinItems: 1,
+			Description: "Names of the drives to convert. I.e: [\"Solid State Disk 0:0:2\", \"Solid State Disk 0:0:3\"]. " +
+				"Drives taken out of the list are left in the mode they are in. It is empty after an import, the next " +
+				"apply sets it from the configuration.",
+			Elem: &schema.Schema{
+				Type:         schema.TypeString,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+		},
+		"mode": {
+			Type:     schema.TypeString,
+			Required: true,
+			Description: "Mode of the drives. Possible values are: \"RAID\", the drives volumes and hot spares are made of, or " +
+				"\"NonRAID\", the drives passed through to the operating system as they are, like for Ceph or vSAN. " +
+				"Drives of a volume or hot spares cannot be converted to non-RAID. The mode is read as \"Mixed\" when " +
+				"the drives are not all in the same one.",
+			ValidateFunc: validation.StringInSlice([]string{raidDriveMode, nonRAIDDriveMode}, false),
+		},
+		"drive_states": {
+			Type:        schema.TypeMap,
+			Computed:    true,
+			Description: "The RAID status of each drive by name, such as \"Ready\", \"Online\" or \"Non-RAID\"",
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"settings_apply_time": {
+			Type:        schema.TypeString,
+			Description: "Flag to make the operation either \"Immediate\" or \"OnReset\". By default value is \"Immediate\"",
+			Optional:    true,
+			ValidateFunc: validation.StringInSlice([]string{
+				string(redfishcommon.ImmediateApplyTime),
+				string(redfishcommon.OnResetApplyTime)}, false),
+			Default: string(redfishcommon.ImmediateApplyTime),
+		},
+		"reset_type": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Description: "Reset type allows to choose the type of restart to apply when settings_apply_time is set to \"OnReset\". " +
+				"Possible values are: \"ForceRestart\", \"GracefulRestart\" or \"PowerCycle\". If not set, \"ForceRestart\" is the default.",
+			ValidateFunc: validation.StringInSlice([]string{
+				string(redfish.ForceRestartResetType),
+				string(redfish.GracefulRestartResetType),
+				string(redfish.PowerCycleResetType),
+			}, false),
+			Default: string(redfish.ForceRestartResetType),
+		},
+	}
+}
+
+// resourceRedfishStorageDriveModeCustomizeDiff plans the states of the drives as unknown when they are converted
+func resourceRedfishStorageDriveModeCustomizeDiff(_ context.Context, diff *schema.ResourceDiff, m interface{}) error {
+	if diff.Id() != "" && diff.HasChanges("drives", "mode") {
+		return diff.SetNewComputed("drive_states")
+	}
+	return nil
+}
+
+func resourceRedfishStorageDriveModeRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	service, err := NewConfig(m.(*providerMeta).config, d)
+	if err != nil {
+		return diag.Errorf(err.Error())
+	}
+	return readRedfishStorageDriveMode(service, d)
+}
+
+func resourceRedfishStorageDriveModeUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	service, err := NewConfig(m.(*providerMeta).config, d)
+	if err != nil {
+		return diag.Errorf(err.Error())
+	}
+	return updateRedfishStorageDriveMode(ctx, service, d, m)
+}
+
+// resourceRedfishStorageDriveModeDelete removes the resource from the state, leaving the drives in the mode they are in
+func resourceRedfishStorageDriveModeDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	d.SetId("")
+	return nil
+}
+
+// resourceRedfishStorageDriveModeImport imports the drive mode of a storage controller from an ID such as
+// https://my-server-1.myawesomecompany.org|/redfish/v1/Systems/System.Embedded.1/Storage/RAID.Integrated.1-1. The
+// drives are not part of the ID, they are left empty for the next apply to set them, which converts the ones of
+// the configuration in the other mode only.
+func resourceRedfishStorageDriveModeImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	odataID, err := parseImportID(d, m)
+	if err != nil {
+		return nil, err
+	}
+	d.SetId(odataID)
+	if err := d.Set("system_id", odataIDMember(odataID, "Systems")); err != nil {
+		return nil, err
+	}
+	if err := d.Set("storage_controller_id", odataIDMember(odataID, "Storage")); err != nil {
+		return nil, err
+	}
+	if err := setImportDefaults(d, getResourceRedfishStorageDriveModeSchema()); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func updateRedfishStorageDriveMode(ctx context.Context, service *gofish.Service, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Lock the mutex to avoid race conditions with other resources
+	redfishMutexKV.Lock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
+	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
+
+	storageID := d.Get("storage_controller_id").(string)
+	system, storage, drives, diags := getStorageDrives(service, d.Get("system_id").(string), storageID, driveModeDriveNames(d))
+	if diags.HasError() {
+		return diags
+	}
+	raidServiceURI, err := getDellRaidService(system)
+	if err != nil {
+		return redfishDiagnostics("Error when retrieving the RAID service of the system", err, nil)
+	}
+	if raidServiceURI == "" {
+		return diag.Errorf("the system has no Dell RAID service to convert the drives of storage controller %s with", storageID)
+	}
+
+	// Only the drives in the other mode are converted
+	mode := d.Get("mode").(string)
+	var fqdds []string
+	for _, drive := range drives {
+		status, err := getDriveRAIDStatus(service.GetClient(), drive.ODataID)
+		if err != nil {
+			return redfishDiagnostics(fmt.Sprintf("Error when reading drive %s", drive.Name), err, nil)
+		}
+		if driveMode(status) != mode {
+			fqdds = append(fqdds, drive.ID)
+		}
+	}
+
+	if len(fqdds) != 0 {
+		action := "ConvertToRAID"
+		if mode == nonRAIDDriveMode {
+			action = "ConvertToNonRAID"
+		}
+		log.Printf("[DEBUG] Converting the drives %v with %s", fqdds, action)
+		jobURI, err := runDellRaidServiceAction(service.GetClient(), raidServiceURI, action, map[string]interface{}{
+			"PDArray":                     fqdds,
+			"@Redfish.OperationApplyTime": d.Get("settings_apply_time"),
+		})
+		if err != nil {
+			return redfishDiagnostics(fmt.Sprintf("Error when converting the drives to %s", mode), err, driveModeAttributePaths)
+		}
+		if diags := waitForStorageJobs(ctx, service, d, m.(*providerMeta).waits, jobURI); diags.HasError() {
+			return diags
+		}
+	}
+
+	d.SetId(storage.ODataID)
+	return readRedfishStorageDriveMode(service, d)
+}
+
+func readRedfishStorageDriveMode(service *gofish.Service, d *schema.ResourceData) diag.Diagnostics {
+	_, _, drives, diags := getStorageDrives(service, d.Get("system_id").(string), d.Get("storage_controller_id").(string), driveModeDriveNames(d))
+	if diags.HasError() {
+		return diags
+	}
+
+	// Drives converted outside of Terraform make the mode Mixed or the other one, planning their conversion again
+	states := make(map[string]interface{}, len(drives))
+	modes := make([]string, 0, len(drives))
+	for _, drive := range drives {
+		status, err := getDriveRAIDStatus(service.GetClient(), drive.ODataID)
+		if err != nil {
+			return redfishDiagnostics(fmt.Sprintf("Error when reading drive %s", drive.Name), err, nil)
+		}
+		states[drive.Name] = status
+		modes = append(modes, driveMode(status))
+	}
+	values := map[string]interface{}{
+		"system_id":    odataIDMember(d.Id(), "Systems"),
+		"drive_states": states,
+	}
+	// There are no drives to read the mode of after an import
+	if len(modes) != 0 {
+		sort.Strings(modes)
+		values["mode"] = mixedDriveMode
+		if modes[0] == modes[len(modes)-1] {
+			values["mode"] = modes[0]
+		}
+	}
+	for k, v := range values {
+		if err := d.Set(k, v); err != nil {
+			return diag.Errorf("Error when setting %s: %s", k, err)
+		}
+	}
+	return nil
+}
+
+// driveModeDriveNames returns the names of the drives of the resource, in order
+func driveModeDriveNames(d *schema.ResourceData) []string {
+	names := []string{}
+	for _, name := range d.Get("drives").(*schema.Set).List() {
+		names = append(names, name.(string))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// getDriveRAIDStatus returns the Dell RAID status of a drive, such as Ready, Online or Non-RAID
+func getDriveRAIDStatus(client redfishcommon.Client, driveURI string) (string, error) {
+	var drive struct {
+		Oem struct {
+			Dell struct {
+				DellPhysicalDisk struct {
+					RaidStatus string
+				}
+			}
+		}
+	}
+	if err := getJSON(client, driveURI, &drive); err != nil {
+		return "", err
+	}
+	return drive.Oem.Dell.DellPhysicalDisk.RaidStatus, nil
+}
+
+// driveMode returns the mode of a drive with the given Dell RAID status
+func driveMode(raidStatus string) string {
+	if raidStatus == nonRAIDStatus {
+		return nonRAIDDriveMode
+	}
+	return raidDriveMode
+}
@@ -0,0 +1,211 @@
+package redfish
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+const (
+	driveModeDrive2URI = "/redfish/v1/Systems/System.Embedded.1/Storage/RAID.Integrated.1-1/Drives/Disk.Bay.2:Enclosure.Internal.0-1:RAID.Integrated.1-1"
+	driveModeDrive3URI = "/redfish/v1/Systems/System.Embedded.1/Storage/RAID.Integrated.1-1/Drives/Disk.Bay.3:Enclosure.Internal.0-1:RAID.Integrated.1-1"
+)
+
+// Test to convert drives to non-RAID mode
+func TestAccRedfishStorageDriveMode_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceStorageDriveModeConfig(creds, `
+					drives = ["Solid State Disk 0:0:2", "Solid State Disk 0:0:3"]
+					mode   = "NonRAID"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_storage_drive_mode.mode", "mode", "NonRAID"),
+				),
+			},
+		},
+	})
+}
+
+// Test to convert drives to non-RAID mode on an emulated iDRAC, convert one again once changed outside of Terraform,
+// and then convert them back to RAID mode
+func TestRedfishStorageDriveMode_emulated(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	nonRAIDConfig := testAccRedfishResourceStorageDriveModeConfig(creds, `
+		drives              = ["Solid State Disk 0:0:2", "Solid State Disk 0:0:3"]
+		mode                = "NonRAID"
+		settings_apply_time = "OnReset"`)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: nonRAIDConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_storage_drive_mode.mode", "id", "/redfish/v1/Systems/System.Embedded.1/Storage/RAID.Integrated.1-1"),
+					resource.TestCheckResourceAttr("redfish_storage_drive_mode.mode", "drive_states.Solid State Disk 0:0:2", "Non-RAID"),
+					checkEmulatedResource(server, driveModeDrive2URI, "Oem/Dell/DellPhysicalDisk/RaidStatus", "Non-RAID"),
+					checkEmulatedResource(server, driveModeDrive3URI, "Oem/Dell/DellPhysicalDisk/RaidStatus", "Non-RAID"),
+				),
+			},
+			{
+				PreConfig: func() {
+					server.Modify(driveModeDrive3URI, map[string]interface{}{
+						"Oem": map[string]interface{}{"Dell": map[string]interface{}{"DellPhysicalDisk": map[string]interface{}{"RaidStatus": "Ready"}}},
+					})
+				},
+				Config:             nonRAIDConfig,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: nonRAIDConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_storage_drive_mode.mode", "mode", "NonRAID"),
+					checkEmulatedResource(server, driveModeDrive3URI, "Oem/Dell/DellPhysicalDisk/RaidStatus", "Non-RAID"),
+				),
+			},
+			{
+				Config: testAccRedfishResourceStorageDriveModeConfig(creds, `
+					drives = ["Solid State Disk 0:0:2", "Solid State Disk 0:0:3"]
+					mode   = "RAID"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_storage_drive_mode.mode", "drive_states.Solid State Disk 0:0:3", "Ready"),
+					checkEmulatedResource(server, driveModeDrive2URI, "Oem/Dell/DellPhysicalDisk/RaidStatus", "Ready"),
+					checkEmulatedResource(server, driveModeDrive3URI, "Oem/Dell/DellPhysicalDisk/RaidStatus", "Ready"),
+				),
+			},
+		},
+	})
+}
+
+// Test that drives converted outside of Terraform to the other mode make the mode Mixed, which converts them back
+func TestRedfishStorageDriveMode_emulatedMixed(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	nonRAIDConfig := testAccRedfishResourceStorageDriveModeConfig(creds, `
+		drives = ["Solid State Disk 0:0:2", "Solid State Disk 0:0:3"]
+		mode   = "NonRAID"`)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: nonRAIDConfig,
+			},
+			{
+				PreConfig: func() {
+					server.Modify(driveModeDrive2URI, map[string]interface{}{
+						"Oem": map[string]interface{}{"Dell": map[string]interface{}{"DellPhysicalDisk": map[string]interface{}{"RaidStatus": "Ready"}}},
+					})
+				},
+				RefreshState:       true,
+				ExpectNonEmptyPlan: true,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_storage_drive_mode.mode", "mode", "Mixed"),
+					resource.TestCheckResourceAttr("redfish_storage_drive_mode.mode", "drive_states.Solid State Disk 0:0:2", "Ready"),
+				),
+			},
+			{
+				Config: nonRAIDConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_storage_drive_mode.mode", "mode", "NonRAID"),
+					checkEmulatedResource(server, driveModeDrive2URI, "Oem/Dell/DellPhysicalDisk/RaidStatus", "Non-RAID"),
+					checkEmulatedResource(server, driveModeDrive3URI, "Oem/Dell/DellPhysicalDisk/RaidStatus", "Non-RAID"),
+				),
+			},
+		},
+	})
+}
+
+// Test to import the drive mode of a storage controller, whose drives the next apply sets from the configuration
+func TestRedfishStorageDriveMode_emulatedImport(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	config := fmt.Sprintf(`
+		provider "redfish" {
+		  user     = "%s"
+		  password = "%s"
+		}
+		`, creds.Username, creds.Password) +
+		testAccRedfishResourceStorageDriveModeConfig(creds, `
+		drives = ["Solid State Disk 0:0:2"]
+		mode   = "NonRAID"`)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:             config,
+				ResourceName:       "redfish_storage_drive_mode.mode",
+				ImportState:        true,
+				ImportStateId:      "https://" + creds.Endpoint + "|/redfish/v1/Systems/System.Embedded.1/Storage/RAID.Integrated.1-1|ssl_insecure=true",
+				ImportStatePersist: true,
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if len(states) != 1 {
+						return fmt.Errorf("got %d imported resources, want 1", len(states))
+					}
+					attributes := states[0].Attributes
+					if attributes["storage_controller_id"] != "RAID.Integrated.1-1" || (attributes["drives.#"] != "" && attributes["drives.#"] != "0") {
+						return fmt.Errorf("got storage_controller_id %s and %s drives, want RAID.Integrated.1-1 and none",
+							attributes["storage_controller_id"], attributes["drives.#"])
+					}
+					return nil
+				},
+			},
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_storage_drive_mode.mode", "mode", "NonRAID"),
+					checkEmulatedResource(server, driveModeDrive2URI, "Oem/Dell/DellPhysicalDisk/RaidStatus", "Non-RAID"),
+				),
+			},
+		},
+	})
+}
+
+// Test that drives which are missing or in use are not converted
+func TestRedfishStorageDriveMode_emulatedInvalid(t *testing.T) {
+	_, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceStorageDriveModeConfig(creds, `
+					drives = ["Solid State Disk 0:0:9"]
+					mode   = "NonRAID"`),
+				ExpectError: regexp.MustCompile("the storage controller has no drive named Solid State Disk 0:0:9"),
+			},
+			{
+				Config: testAccRedfishResourceStorageHotSpareConfig(creds, `
+					drive         = "Solid State Disk 0:0:3"
+					hotspare_type = "Global"`) +
+					testAccRedfishResourceStorageDriveModeConfig(creds, `
+					drives     = ["Solid State Disk 0:0:3"]
+					mode       = "NonRAID"
+					depends_on = [redfish_storage_hot_spare.spare]`),
+				ExpectError: regexp.MustCompile("Error when converting the drives to NonRAID"),
+			},
+		},
+	})
+}
+
+func testAccRedfishResourceStorageDriveModeConfig(testingInfo TestingServerCredentials, settings string) string {
+	return fmt.Sprintf(`
+		resource "redfish_storage_drive_mode" "mode" {
+		  redfish_server {
+			user         = "%s"
+			password     = "%s"
+			endpoint     = "https://%s"
+			ssl_insecure = true
+		  }
+
+		  storage_controller_id = "RAID.Integrated.1-1"
+		  %s
+		}
+		`,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+		settings,
+	)
+}
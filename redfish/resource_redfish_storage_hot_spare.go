@@ -0,0 +1,411 @@
+package redfish
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/stmcginnis/gofish"
+	redfishcommon "github.com/stmcginnis/gofish/common"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// defaultStorageHotSpareTimeout bounds the assignment of a hot spare, reset of the server and RAID job included
+const defaultStorageHotSpareTimeout = 30 * time.Minute
+
+// hotSpareAttributePaths locates the attributes setting the hot spares
+var hotSpareAttributePaths = attributePaths{
+	"TargetFQDD":                  "drive",
+	"VirtualDiskArray":            "volume_odata_ids",
+	"HotspareType":                "hotspare_type",
+	"Links/DedicatedSpareDrives":  "volume_odata_ids",
+	"@Redfish.OperationApplyTime": "settings_apply_time",
+}
+
+func resourceRedfishStorageHotSpare() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceRedfishStorageHotSpareCreate,
+		ReadContext:   resourceRedfishStorageHotSpareRead,
+		UpdateContext: resourceRedfishStorageHotSpareUpdate,
+		DeleteContext: resourceRedfishStorageHotSpareDelete,
+		Schema:        getResourceRedfishStorageHotSpareSchema(),
+		CustomizeDiff: resourceRedfishStorageHotSpareCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceRedfishStorageHotSpareImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultStorageHotSpareTimeout),
+			Delete: schema.DefaultTimeout(defaultStorageHotSpareTimeout),
+		},
+	}
+}
+
+func getResourceRedfishStorageHotSpareSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"redfish_alias":  redfishAliasSchema(),
+		"redfish_server": redfishServerSchema(),
+		"system_id":      systemIDSchema(true),
+		"storage_controller_id": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "ID of the storage controller of the drive. I.e: RAID.Integrated.1-1",
+		},
+		"drive": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ForceNew:     true,
+			Description:  "Name of the drive to make a hot spare. I.e: Solid State Disk 0:0:3",
+			ValidateFunc: validation.StringIsNotEmpty,
+		},
+		"hotspare_type": {
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+			Description: "Type of hot spare. Possible values are: \"Global\", a spare of every volume of the controller, " +
+				"or \"Dedicated\", a spare of the volumes in volume_odata_ids only.",
+			ValidateFunc: validation.StringInSlice([]string{
+				string(redfish.GlobalHotspareType),
+				string(redfish.DedicatedHotspareType),
+			}, false),
+		},
+		"volume_odata_ids": {
+			Type:     schema.TypeSet,
+			Optional: true,
+			ForceNew: true,
+			Description: "OData IDs of the volumes a dedicated hot spare replaces the failed drives of, such as the id of a " +
+				"redfish_storage_volume. It must be set when hotspare_type is \"Dedicated\" only.",
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"settings_apply_time": {
+			Type:        schema.TypeString,
+			Description: "Flag to make the operation either \"Immediate\" or \"OnReset\". By default value is \"Immediate\"",
+			Optional:    true,
+			ValidateFunc: validation.StringInSlice([]string{
+				string(redfishcommon.ImmediateApplyTime),
+				string(redfishcommon.OnResetApplyTime)}, false),
+			Default: string(redfishcommon.ImmediateApplyTime),
+		},
+		"reset_type": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Description: "Reset type allows to choose the type of restart to apply when settings_apply_time is set to \"OnReset\". " +
+				"Possible values are: \"ForceRestart\", \"GracefulRestart\" or \"PowerCycle\". If not set, \"ForceRestart\" is the default.",
+			ValidateFunc: validation.StringInSlice([]string{
+				string(redfish.ForceRestartResetType),
+				string(redfish.GracefulRestartResetType),
+				string(redfish.PowerCycleResetType),
+			}, false),
+			Default: string(redfish.ForceRestartResetType),
+		},
+	}
+}
+
+// resourceRedfishStorageHotSpareCustomizeDiff checks that volumes are given to dedicated hot spares only
+func resourceRedfishStorageHotSpareCustomizeDiff(_ context.Context, diff *schema.ResourceDiff, m interface{}) error {
+	// Volumes not known yet, like the ones of volumes to create, are checked once they are
+	config := diff.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() || !config.GetAttr("volume_odata_ids").IsWhollyKnown() {
+		return nil
+	}
+	volumes := config.GetAttr("volume_odata_ids")
+	count := 0
+	if !volumes.IsNull() {
+		count = volumes.LengthInt()
+	}
+	hotspareType := diff.Get("hotspare_type").(string)
+	if hotspareType == string(redfish.DedicatedHotspareType) && count == 0 {
+		return fmt.Errorf("volume_odata_ids must be set for a Dedicated hot spare")
+	}
+	if hotspareType == string(redfish.GlobalHotspareType) && count != 0 {
+		return fmt.Errorf("volume_odata_ids cannot be set for a Global hot spare")
+	}
+	return nil
+}
+
+func resourceRedfishStorageHotSpareCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	service, err := NewConfig(m.(*providerMeta).config, d)
+	if err != nil {
+		return diag.Errorf(err.Error())
+	}
+	return createRedfishStorageHotSpare(ctx, service, d, m)
+}
+
+func resourceRedfishStorageHotSpareRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	service, err := NewConfig(m.(*providerMeta).config, d)
+	if err != nil {
+		return diag.Errorf(err.Error())
+	}
+	return readRedfishStorageHotSpare(service, d)
+}
+
+// resourceRedfishStorageHotSpareUpdate only takes the changes of the server settings and of the way changes are
+// applied, the hot spare being assigned again when it changes
+func resourceRedfishStorageHotSpareUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return resourceRedfishStorageHotSpareRead(ctx, d, m)
+}
+
+func resourceRedfishStorageHotSpareDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	service, err := NewConfig(m.(*providerMeta).config, d)
+	if err != nil {
+		return diag.Errorf(err.Error())
+	}
+	return deleteRedfishStorageHotSpare(ctx, service, d, m)
+}
+
+// resourceRedfishStorageHotSpareImport imports a hot spare from the ID of its drive such as
+// https://my-server-1.myawesomecompany.org|/redfish/v1/Systems/System.Embedded.1/Storage/RAID.Integrated.1-1/Drives/Disk.Bay.3:Enclosure.Internal.0-1:RAID.Integrated.1-1
+func resourceRedfishStorageHotSpareImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	odataID, err := parseImportID(d, m)
+	if err != nil {
+		return nil, err
+	}
+	d.SetId(odataID)
+	if err := d.Set("system_id", odataIDMember(odataID, "Systems")); err != nil {
+		return nil, err
+	}
+	if err := d.Set("storage_controller_id", odataIDMember(odataID, "Storage")); err != nil {
+		return nil, err
+	}
+	if err := setImportDefaults(d, getResourceRedfishStorageHotSpareSchema()); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func createRedfishStorageHotSpare(ctx context.Context, service *gofish.Service, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Lock the mutex to avoid race conditions with other resources
+	redfishMutexKV.Lock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
+	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
+
+	storageID := d.Get("storage_controller_id").(string)
+	hotspareType := d.Get("hotspare_type").(string)
+	volumeURIs := d.Get("volume_odata_ids").(*schema.Set).List()
+	if hotspareType == string(redfish.DedicatedHotspareType) && len(volumeURIs) == 0 {
+		return diag.Errorf("volume_odata_ids must be set for a Dedicated hot spare")
+	}
+	if hotspareType == string(redfish.GlobalHotspareType) && len(volumeURIs) != 0 {
+		return diag.Errorf("volume_odata_ids cannot be set for a Global hot spare")
+	}
+
+	system, storage, drives, diags := getStorageDrives(service, d.Get("system_id").(string), storageID, []string{d.Get("drive").(string)})
+	if diags.HasError() {
+		return diags
+	}
+	drive := drives[0]
+	volumes := make([]*redfish.Volume, 0, len(volumeURIs))
+	for _, uri := range volumeURIs {
+		volume, err := redfish.GetVolume(service.GetClient(), uri.(string))
+		if err != nil {
+			return redfishDiagnostics(fmt.Sprintf("Error when retrieving volume %s", uri), err, nil)
+		}
+		if path.Dir(path.Dir(volume.ODataID)) != storage.ODataID {
+			return diag.Errorf("volume %s does not belong to storage controller %s", volume.ODataID, storageID)
+		}
+		volumes = append(volumes, volume)
+	}
+
+	raidServiceURI, err := getDellRaidService(system)
+	if err != nil {
+		return redfishDiagnostics("Error when retrieving the RAID service of the system", err, nil)
+	}
+	var jobURI string
+	if raidServiceURI != "" {
+		payload := map[string]interface{}{
+			"TargetFQDD":                  drive.ID,
+			"@Redfish.OperationApplyTime": d.Get("settings_apply_time"),
+		}
+		if len(volumes) != 0 {
+			volumeIDs := make([]string, 0, len(volumes))
+			for _, volume := range volumes {
+				volumeIDs = append(volumeIDs, volume.ID)
+			}
+			payload["VirtualDiskArray"] = volumeIDs
+		}
+		log.Printf("[DEBUG] Assigning %s as a %s hot spare", drive.ODataID, hotspareType)
+		jobURI, err = runDellRaidServiceAction(service.GetClient(), raidServiceURI, "AssignSpare", payload)
+	} else {
+		volumeURIs := make([]string, 0, len(volumes))
+		for _, volume := range volumes {
+			volumeURIs = append(volumeURIs, volume.ODataID)
+		}
+		jobURI, err = setHotspareType(service.GetClient(), drive, hotspareType, volumeURIs)
+	}
+	if err != nil {
+		return redfishDiagnostics(fmt.Sprintf("Error when assigning drive %s as a hot spare", drive.Name), err, hotSpareAttributePaths)
+	}
+	if diags := waitForStorageJobs(ctx, service, d, m.(*providerMeta).waits, jobURI); diags.HasError() {
+		return diags
+	}
+
+	d.SetId(drive.ODataID)
+	return readRedfishStorageHotSpare(service, d)
+}
+
+func readRedfishStorageHotSpare(service *gofish.Service, d *schema.ResourceData) diag.Diagnostics {
+	drive, err := redfish.GetDrive(service.GetClient(), d.Id())
+	if err != nil {
+		if e, ok := err.(*redfishcommon.Error); ok && e.HTTPReturnedStatusCode == http.StatusNotFound {
+			log.Printf("[DEBUG] The drive %s no longer exists", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return redfishDiagnostics(fmt.Sprintf("Error when reading drive %s", d.Id()), err, nil)
+	}
+
+	volumeURIs := []string{}
+	if drive.HotspareType == redfish.DedicatedHotspareType {
+		if volumeURIs, err = getDedicatedVolumes(service.GetClient(), drive); err != nil {
+			return redfishDiagnostics("Error when retrieving the volumes of the dedicated hot spare", err, nil)
+		}
+	}
+
+	// A drive which is no longer a hot spare shows as None, planning its assignment again
+	values := map[string]interface{}{
+		"system_id":             odataIDMember(drive.ODataID, "Systems"),
+		"storage_controller_id": odataIDMember(drive.ODataID, "Storage"),
+		"drive":                 drive.Name,
+		"hotspare_type":         string(drive.HotspareType),
+		"volume_odata_ids":      volumeURIs,
+	}
+	for k, v := range values {
+		if err := d.Set(k, v); err != nil {
+			return diag.Errorf("Error when setting %s: %s", k, err)
+		}
+	}
+	return nil
+}
+
+func deleteRedfishStorageHotSpare(ctx context.Context, service *gofish.Service, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	redfishMutexKV.Lock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
+	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
+
+	drive, err := redfish.GetDrive(service.GetClient(), d.Id())
+	if err != nil {
+		if e, ok := err.(*redfishcommon.Error); ok && e.HTTPReturnedStatusCode == http.StatusNotFound {
+			d.SetId("")
+			return nil
+		}
+		return redfishDiagnostics(fmt.Sprintf("Error when reading drive %s", d.Id()), err, nil)
+	}
+	// The drive may already have been unassigned, like when the volumes it was dedicated to were deleted
+	if drive.HotspareType == "" || drive.HotspareType == redfish.NoneHotspareType {
+		d.SetId("")
+		return nil
+	}
+
+	system, err := getSystemResource(service, d.Get("system_id").(string))
+	if err != nil {
+		return redfishDiagnostics("Error when retreiving the Systems from the Redfish API", err, nil)
+	}
+	raidServiceURI, err := getDellRaidService(system)
+	if err != nil {
+		return redfishDiagnostics("Error when retrieving the RAID service of the system", err, nil)
+	}
+	var jobURI string
+	if raidServiceURI != "" {
+		log.Printf("[DEBUG] Unassigning the hot spare %s", drive.ODataID)
+		jobURI, err = runDellRaidServiceAction(service.GetClient(), raidServiceURI, "UnassignSpare", map[string]interface{}{
+			"TargetFQDD":                  drive.ID,
+			"@Redfish.OperationApplyTime": d.Get("settings_apply_time"),
+		})
+	} else {
+		// The drive is taken out of the spares of its volumes, which the hot spare type does not do on its own
+		var volumeURIs []string
+		if drive.HotspareType == redfish.DedicatedHotspareType {
+			if volumeURIs, err = getDedicatedVolumes(service.GetClient(), drive); err != nil {
+				return redfishDiagnostics("Error when retrieving the volumes of the dedicated hot spare", err, nil)
+			}
+		}
+		jobURI, err = setHotspareType(service.GetClient(), drive, string(redfish.NoneHotspareType), volumeURIs)
+	}
+	if err != nil {
+		return redfishDiagnostics(fmt.Sprintf("Error when unassigning the hot spare %s", drive.Name), err, nil)
+	}
+	if diags := waitForStorageJobs(ctx, service, d, m.(*providerMeta).waits, jobURI); diags.HasError() {
+		return diags
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// getDedicatedVolumes returns the URIs of the volumes a dedicated hot spare replaces the failed drives of, which
+// link to it
+func getDedicatedVolumes(client redfishcommon.Client, drive *redfish.Drive) ([]string, error) {
+	storage, err := redfish.GetStorage(client, path.Dir(path.Dir(drive.ODataID)))
+	if err != nil {
+		return nil, err
+	}
+	volumes, err := storage.Volumes()
+	if err != nil {
+		return nil, err
+	}
+	volumeURIs := []string{}
+	for _, volume := range volumes {
+		spares, err := getDedicatedSpareDrives(client, volume.ODataID)
+		if err != nil {
+			return nil, err
+		}
+		if contains(spares, drive.ODataID) {
+			volumeURIs = append(volumeURIs, volume.ODataID)
+		}
+	}
+	return volumeURIs, nil
+}
+
+// getDedicatedSpareDrives returns the URIs of the dedicated hot spares of a volume
+func getDedicatedSpareDrives(client redfishcommon.Client, volumeURI string) ([]string, error) {
+	var links struct {
+		Links struct {
+			DedicatedSpareDrives redfishcommon.Links
+		}
+	}
+	if err := getJSON(client, volumeURI, &links); err != nil {
+		return nil, err
+	}
+	return links.Links.DedicatedSpareDrives.ToStrings(), nil
+}
+
+// setHotspareType makes a drive a hot spare the standard way, on systems without the Dell RAID service. A dedicated
+// hot spare is added to the spares of its volumes, and a drive made None is removed from the spares of the given
+// volumes. It returns the URI of the job applying the change, if any.
+func setHotspareType(client redfishcommon.Client, drive *redfish.Drive, hotspareType string, volumeURIs []string) (string, error) {
+	for _, volumeURI := range volumeURIs {
+		current, err := getDedicatedSpareDrives(client, volumeURI)
+		if err != nil {
+			return "", err
+		}
+		spares := []map[string]string{}
+		for _, uri := range current {
+			if uri != drive.ODataID {
+				spares = append(spares, map[string]string{"@odata.id": uri})
+			}
+		}
+		if hotspareType != string(redfish.NoneHotspareType) {
+			spares = append(spares, map[string]string{"@odata.id": drive.ODataID})
+		}
+		res, err := client.Patch(volumeURI, map[string]interface{}{
+			"Links": map[string]interface{}{"DedicatedSpareDrives": spares},
+		})
+		if err != nil {
+			return "", err
+		}
+		res.Body.Close()
+	}
+
+	res, err := client.Patch(drive.ODataID, map[string]interface{}{"HotspareType": hotspareType})
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	return res.Header.Get("Location"), nil
+}
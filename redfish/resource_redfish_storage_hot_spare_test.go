@@ -0,0 +1,169 @@
+package redfish
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const hotSpareDriveURI = "/redfish/v1/Systems/System.Embedded.1/Storage/RAID.Integrated.1-1/Drives/Disk.Bay.3:Enclosure.Internal.0-1:RAID.Integrated.1-1"
+
+// Test to assign a global hot spare
+func TestAccRedfishStorageHotSpare_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceStorageHotSpareConfig(creds, `
+					drive         = "Solid State Disk 0:0:3"
+					hotspare_type = "Global"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_storage_hot_spare.spare", "hotspare_type", "Global"),
+				),
+			},
+		},
+	})
+}
+
+// Test to assign a global hot spare on an emulated iDRAC, assign it again once unassigned outside of Terraform, and
+// then make it a dedicated hot spare of a volume
+func TestRedfishStorageHotSpare_emulated(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		CheckDestroy:      checkEmulatedResource(server, hotSpareDriveURI, "HotspareType", "None"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceStorageHotSpareConfig(creds, `
+					drive               = "Solid State Disk 0:0:3"
+					hotspare_type       = "Global"
+					settings_apply_time = "OnReset"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_storage_hot_spare.spare", "id", hotSpareDriveURI),
+					checkEmulatedResource(server, hotSpareDriveURI, "HotspareType", "Global"),
+				),
+			},
+			{
+				PreConfig: func() {
+					server.Modify(hotSpareDriveURI, map[string]interface{}{"HotspareType": "None"})
+				},
+				Config: testAccRedfishResourceStorageHotSpareConfig(creds, `
+					drive               = "Solid State Disk 0:0:3"
+					hotspare_type       = "Global"
+					settings_apply_time = "OnReset"`),
+				Check: resource.ComposeTestCheckFunc(
+					checkEmulatedResource(server, hotSpareDriveURI, "HotspareType", "Global"),
+				),
+			},
+			{
+				Config: testAccRedfishResourceStorageVolumeMinConfig(
+					creds,
+					"RAID.Integrated.1-1",
+					"TerraformVol1",
+					"NonRedundant",
+					"Solid State Disk 0:0:0") +
+					testAccRedfishResourceStorageHotSpareConfig(creds, `
+					drive            = "Solid State Disk 0:0:3"
+					hotspare_type    = "Dedicated"
+					volume_odata_ids = [redfish_storage_volume.volume.id]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_storage_hot_spare.spare", "volume_odata_ids.#", "1"),
+					resource.TestCheckResourceAttrPair("redfish_storage_hot_spare.spare", "volume_odata_ids.0", "redfish_storage_volume.volume", "id"),
+					checkEmulatedResource(server, hotSpareDriveURI, "HotspareType", "Dedicated"),
+				),
+			},
+		},
+	})
+}
+
+// Test that a dedicated hot spare assigned the standard way, without the Dell RAID service, is taken out of the spares
+// of its volume when destroyed
+func TestRedfishStorageHotSpare_emulatedStandard(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	server.Modify("/redfish/v1/Systems/System.Embedded.1", map[string]interface{}{
+		"Links": map[string]interface{}{"Oem": map[string]interface{}{"Dell": map[string]interface{}{"DellRaidService": nil}}},
+	})
+	volumeConfig := testAccRedfishResourceStorageVolumeMinConfig(
+		creds,
+		"RAID.Integrated.1-1",
+		"TerraformVol1",
+		"NonRedundant",
+		"Solid State Disk 0:0:0")
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: volumeConfig + testAccRedfishResourceStorageHotSpareConfig(creds, `
+					drive            = "Solid State Disk 0:0:3"
+					hotspare_type    = "Dedicated"
+					volume_odata_ids = [redfish_storage_volume.volume.id]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("redfish_storage_hot_spare.spare", "volume_odata_ids.0", "redfish_storage_volume.volume", "id"),
+					checkEmulatedResource(server, hotSpareDriveURI, "HotspareType", "Dedicated"),
+					checkEmulatedResourceOf(server, "redfish_storage_volume.volume", "Links/DedicatedSpareDrives@odata.count", float64(1)),
+				),
+			},
+			{
+				Config: volumeConfig,
+				Check: resource.ComposeTestCheckFunc(
+					checkEmulatedResource(server, hotSpareDriveURI, "HotspareType", "None"),
+					checkEmulatedResourceOf(server, "redfish_storage_volume.volume", "Links/DedicatedSpareDrives@odata.count", float64(0)),
+				),
+			},
+		},
+	})
+}
+
+// Test that volumes are given to dedicated hot spares only
+func TestRedfishStorageHotSpare_emulatedInvalid(t *testing.T) {
+	_, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceStorageHotSpareConfig(creds, `
+					drive         = "Solid State Disk 0:0:3"
+					hotspare_type = "Dedicated"`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("volume_odata_ids must be set for a Dedicated hot spare"),
+			},
+			{
+				Config: testAccRedfishResourceStorageHotSpareConfig(creds, `
+					drive            = "Solid State Disk 0:0:3"
+					hotspare_type    = "Global"
+					volume_odata_ids = ["/redfish/v1/Systems/System.Embedded.1/Storage/RAID.Integrated.1-1/Volumes/Disk.Virtual.0:RAID.Integrated.1-1"]`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("volume_odata_ids cannot be set for a Global hot spare"),
+			},
+			{
+				Config: testAccRedfishResourceStorageHotSpareConfig(creds, `
+					drive         = "Solid State Disk 0:0:9"
+					hotspare_type = "Global"`),
+				ExpectError: regexp.MustCompile("the storage controller has no drive named Solid State Disk 0:0:9"),
+			},
+		},
+	})
+}
+
+func testAccRedfishResourceStorageHotSpareConfig(testingInfo TestingServerCredentials, settings string) string {
+	return fmt.Sprintf(`
+		resource "redfish_storage_hot_spare" "spare" {
+		  redfish_server {
+			user         = "%s"
+			password     = "%s"
+			endpoint     = "https://%s"
+			ssl_insecure = true
+		  }
+
+		  storage_controller_id = "RAID.Integrated.1-1"
+		  %s
+		}
+		`,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+		settings,
+	)
+}
@@ -5,8 +5,12 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/dell/terraform-provider-redfish/common"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -16,11 +20,34 @@ import (
 )
 
 const (
-	defaultStorageVolumeResetTimeout  int = 120
-	defaultStorageVolumeJobTimeout    int = 1200
-	intervalStorageVolumeJobCheckTime int = 10
+	defaultStorageVolumeResetTimeout int = 120
+	defaultStorageVolumeJobTimeout   int = 1200
+	// defaultStorageVolumeTimeout bounds a whole operation on a volume, reset of the server and volume job included
+	defaultStorageVolumeTimeout = 30 * time.Minute
 )
 
+// intervalStorageVolumeJobCheckTime is the time in seconds between checks of volume jobs
+const intervalStorageVolumeJobCheckTime = 10
+
+// volumeAttributePaths locates the attributes setting the properties of volumes
+var volumeAttributePaths = attributePaths{
+	"VolumeType":                           "volume_type",
+	"RAIDType":                             "raid_type",
+	"StripSizeBytes":                       "stripe_size_bytes",
+	"Oem/Dell/DellVolume/SpanDepth":        "span_depth",
+	"Oem/Dell/DellVolume/SpanLength":       "span_length",
+	"Name":                                 "volume_name",
+	"DisplayName":                          "volume_name",
+	"Drives":                               "drives",
+	"CapacityBytes":                        "capacity_bytes",
+	"OptimumIOSizeBytes":                   "optimum_io_size_bytes",
+	"ReadCachePolicy":                      "read_cache_policy",
+	"WriteCachePolicy":                     "write_cache_policy",
+	"Oem/Dell/DellVolume/DiskCachePolicy":  "disk_cache_policy",
+	"@Redfish.OperationApplyTime":          "settings_apply_time",
+	"@Redfish.SettingsApplyTime/ApplyTime": "settings_apply_time",
+}
+
 func resourceRedfishStorageVolume() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceRedfishStorageVolumeCreate,
@@ -28,43 +55,26 @@ func resourceRedfishStorageVolume() *schema.Resource {
 		UpdateContext: resourceRedfishStorageVolumeUpdate,
 		DeleteContext: resourceRedfishStorageVolumeDelete,
 		Schema:        getResourceRedfishStorageVolumeSchema(),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceRedfishStorageVolumeImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultStorageVolumeTimeout),
+			Update: schema.DefaultTimeout(defaultStorageVolumeTimeout),
+			Delete: schema.DefaultTimeout(defaultStorageVolumeTimeout),
+		},
 	}
 }
 
 func getResourceRedfishStorageVolumeSchema() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
-		"redfish_server": {
-			Type:        schema.TypeList,
-			Required:    true,
-			Description: "This list contains the different redfish endpoints to manage (different servers)",
-			Elem: &schema.Resource{
-				Schema: map[string]*schema.Schema{
-					"user": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Description: "This field is the user to login against the redfish API",
-					},
-					"password": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Description: "This field is the password related to the user given",
-					},
-					"endpoint": {
-						Type:        schema.TypeString,
-						Required:    true,
-						Description: "This field is the endpoint where the redfish API is placed",
-					},
-					"ssl_insecure": {
-						Type:        schema.TypeBool,
-						Optional:    true,
-						Description: "This field indicates if the SSL/TLS certificate must be verified",
-					},
-				},
-			},
-		},
+		"redfish_alias":  redfishAliasSchema(),
+		"redfish_server": redfishServerSchema(),
+		"system_id":      systemIDSchema(true),
 		"storage_controller_id": {
 			Type:        schema.TypeString,
 			Required:    true,
+			ForceNew:    true,
 			Description: "This value must be the storage controller ID the user want to manage. I.e: RAID.Integrated.1-1",
 		},
 		"volume_name": {
@@ -74,9 +84,13 @@ func getResourceRedfishStorageVolumeSchema() map[string]*schema.Schema {
 			ValidateFunc: validation.StringLenBetween(1, 15),
 		},
 		"volume_type": {
-			Type:        schema.TypeString,
-			Required:    true,
-			Description: "This value specifies the raid level the virtual disk is going to have. Possible values are: NonRedundant (RAID-0), Mirrored (RAID-1), StripedWithParity (RAID-5), SpannedMirrors (RAID-10) or SpannedStripesWithParity (RAID-50)",
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+			ForceNew: true,
+			Description: "This value specifies the raid level the virtual disk is going to have. Possible values are: NonRedundant (RAID-0), Mirrored (RAID-1), StripedWithParity (RAID-5), SpannedMirrors (RAID-10) or SpannedStripesWithParity (RAID-50). " +
+				"Either volume_type or raid_type must be set.",
+			ExactlyOneOf: []string{"volume_type", "raid_type"},
 			ValidateFunc: validation.StringInSlice([]string{
 				string(redfish.NonRedundantVolumeType),
 				string(redfish.MirroredVolumeType),
@@ -85,10 +99,130 @@ func getResourceRedfishStorageVolumeSchema() map[string]*schema.Schema {
 				string(redfish.SpannedStripesWithParityVolumeType),
 			}, false),
 		},
+		"raid_type": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+			ForceNew: true,
+			Description: "This value specifies the RAID type of the volume, such as RAID6 or RAID60 which volume_type cannot set. " +
+				"It must be one of the RAID types the storage controller supports.",
+			ValidateFunc: validation.StringInSlice([]string{
+				string(redfish.RAID0RAIDType),
+				string(redfish.RAID1RAIDType),
+				string(redfish.RAID3RAIDType),
+				string(redfish.RAID4RAIDType),
+				string(redfish.RAID5RAIDType),
+				string(redfish.RAID6RAIDType),
+				string(redfish.RAID10RAIDType),
+				string(redfish.RAID01RAIDType),
+				string(redfish.RAID6TPRAIDType),
+				string(redfish.RAID1ERAIDType),
+				string(redfish.RAID50RAIDType),
+				string(redfish.RAID60RAIDType),
+				string(redfish.RAID00RAIDType),
+				string(redfish.RAID10ERAIDType),
+				string(redfish.RAID1TripleRAIDType),
+				string(redfish.RAID10TripleRAIDType),
+			}, false),
+		},
+		"span_depth": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Computed:     true,
+			ForceNew:     true,
+			Description:  "Number of spans of the volume, such as the RAID 1 or RAID 5 sets a RAID 10 or RAID 50 volume stripes over.",
+			ValidateFunc: validation.IntAtLeast(1),
+		},
+		"span_length": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Computed:     true,
+			ForceNew:     true,
+			Description:  "Number of drives in each span of the volume. The span depth multiplied by the span length must be the number of drives.",
+			ValidateFunc: validation.IntAtLeast(1),
+		},
+		"stripe_size_bytes": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Computed:     true,
+			ForceNew:     true,
+			Description:  "Number of bytes of each strip of the volume on a drive, such as 65536",
+			ValidateFunc: validation.IntAtLeast(1),
+		},
 		"drives": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Computed: true,
+			ForceNew: true,
+			Description: "This list contains the physical disks names to create the volume within a disk controller. Changing the drives replaces the volume. " +
+				"Either drives or drive_selector must be set.",
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+			ExactlyOneOf: []string{"drives", "drive_selector"},
+		},
+		"drive_selector": {
+			Type:     schema.TypeList,
+			Optional: true,
+			ForceNew: true,
+			MaxItems: 1,
+			Description: "Criteria to choose the drives of the volume among the ones of the storage controller, instead of naming them in drives. " +
+				"The drives are chosen in the order of their slots when the volume is created, changing the criteria replaces the volume.",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"media_type": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						Description:  "Media type of the drives. Possible values are: \"HDD\" or \"SSD\"",
+						ValidateFunc: validation.StringInSlice([]string{string(redfish.HDDMediaType), string(redfish.SSDMediaType)}, false),
+					},
+					"protocol": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: "Protocol of the drives. Possible values are: \"SAS\", \"SATA\" or \"NVMe\"",
+						ValidateFunc: validation.StringInSlice([]string{
+							string(redfishcommon.SASProtocol),
+							string(redfishcommon.SATAProtocol),
+							string(redfishcommon.NVMeProtocol),
+						}, false),
+					},
+					"min_capacity_bytes": {
+						Type:         schema.TypeInt,
+						Optional:     true,
+						Description:  "Minimum capacity in bytes of the drives",
+						ValidateFunc: validation.IntAtLeast(0),
+					},
+					"min_slot": {
+						Type:         schema.TypeInt,
+						Optional:     true,
+						Description:  "Lowest slot of the drives",
+						ValidateFunc: validation.IntAtLeast(0),
+					},
+					"max_slot": {
+						Type:         schema.TypeInt,
+						Optional:     true,
+						Description:  "Highest slot of the drives",
+						ValidateFunc: validation.IntAtLeast(0),
+					},
+					"unassigned_only": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Description: "Whether only the drives which are neither part of a volume nor hot spares are chosen. Default is true.",
+						Default:     true,
+					},
+					"count": {
+						Type:         schema.TypeInt,
+						Required:     true,
+						Description:  "Number of drives to choose",
+						ValidateFunc: validation.IntAtLeast(1),
+					},
+				},
+			},
+		},
+		"drive_odata_ids": {
 			Type:        schema.TypeList,
-			Required:    true,
-			Description: "This list contains the physical disks names to create the volume within a disk controller",
+			Computed:    true,
+			Description: "OData IDs of the drives of the volume, such as the ones drive_selector chose",
 			Elem: &schema.Schema{
 				Type: schema.TypeString,
 			},
@@ -118,26 +252,34 @@ func getResourceRedfishStorageVolumeSchema() map[string]*schema.Schema {
 			Type:     schema.TypeInt,
 			Optional: true,
 			Description: "reset_timeout is the time in seconds that the provider waits for the server to be reset" +
-				"(if settings_apply_time is set to \"OnReset\") before timing out. Default is 120s.",
-			Default: defaultStorageVolumeResetTimeout,
+				"(if settings_apply_time is set to \"OnReset\") before timing out. Default is 120s. " +
+				"Deprecated, use the timeouts block instead.",
+			Default:    defaultStorageVolumeResetTimeout,
+			Deprecated: "Use the timeouts block instead",
 		},
 		"volume_job_timeout": {
 			Type:     schema.TypeInt,
 			Optional: true,
 			Description: "volume_job_timeout is the time in seconds that the provider waits for the volume job to be completed before timing out." +
-				"Default is 1200s",
-			Default: defaultStorageVolumeJobTimeout,
+				"Default is 1200s. Deprecated, use the timeouts block instead.",
+			Default:    defaultStorageVolumeJobTimeout,
+			Deprecated: "Use the timeouts block instead",
 		},
 		"capacity_bytes": {
-			Type:         schema.TypeInt,
-			Optional:     true,
-			Description:  "capacity_bytes shall contain the size in bytes of the associated volume.",
+			Type:     schema.TypeInt,
+			Optional: true,
+			Computed: true,
+			ForceNew: true,
+			Description: "capacity_bytes shall contain the size in bytes of the associated volume. The system sizes the volume when it is not set. " +
+				"The system rounds the size to whole stripes across the drives, the configured size is kept as long as the volume has its rounded size.",
 			ValidateFunc: validation.IntAtLeast(1000000000),
 		},
 		"optimum_io_size_bytes": {
-			Type:        schema.TypeInt,
-			Optional:    true,
-			Description: "optimum_io_size_bytes shall contain the optimum IO size to use when performing IO on this volume.",
+			Type:     schema.TypeInt,
+			Optional: true,
+			Computed: true,
+			Description: "optimum_io_size_bytes shall contain the optimum IO size to use when performing IO on this volume. " +
+				"The system may use another size, such as the stripe size, so the configured one is kept and changing it does not replace the volume.",
 		},
 		"read_cache_policy": {
 			Type:        schema.TypeString,
@@ -175,15 +317,15 @@ func getResourceRedfishStorageVolumeSchema() map[string]*schema.Schema {
 }
 
 func resourceRedfishStorageVolumeCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	service, err := NewConfig(m.(*schema.ResourceData), d)
+	service, err := NewConfig(m.(*providerMeta).config, d)
 	if err != nil {
 		return diag.Errorf(err.Error())
 	}
-	return createRedfishStorageVolume(service, d)
+	return createRedfishStorageVolume(ctx, service, d, m)
 }
 
 func resourceRedfishStorageVolumeRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	service, err := NewConfig(m.(*schema.ResourceData), d)
+	service, err := NewConfig(m.(*providerMeta).config, d)
 	if err != nil {
 		return diag.Errorf(err.Error())
 	}
@@ -191,7 +333,7 @@ func resourceRedfishStorageVolumeRead(ctx context.Context, d *schema.ResourceDat
 }
 
 func resourceRedfishStorageVolumeUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	service, err := NewConfig(m.(*schema.ResourceData), d)
+	service, err := NewConfig(m.(*providerMeta).config, d)
 	if err != nil {
 		return diag.Errorf(err.Error())
 	}
@@ -202,26 +344,58 @@ func resourceRedfishStorageVolumeUpdate(ctx context.Context, d *schema.ResourceD
 }
 
 func resourceRedfishStorageVolumeDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	service, err := NewConfig(m.(*schema.ResourceData), d)
+	service, err := NewConfig(m.(*providerMeta).config, d)
 	if err != nil {
 		return diag.Errorf(err.Error())
 	}
-	return deleteRedfishStorageVolume(service, d)
+	return deleteRedfishStorageVolume(ctx, service, d, m)
 }
 
-func createRedfishStorageVolume(service *gofish.Service, d *schema.ResourceData) diag.Diagnostics {
+// resourceRedfishStorageVolumeImport imports a volume from an ID such as
+// https://my-server-1.myawesomecompany.org|/redfish/v1/Systems/System.Embedded.1/Storage/RAID.Integrated.1-1/Volumes/Disk.Virtual.0:RAID.Integrated.1-1
+func resourceRedfishStorageVolumeImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	odataID, err := parseImportID(d, m)
+	if err != nil {
+		return nil, err
+	}
+
+	// The properties of the volume are read once imported
+	d.SetId(odataID)
+	if err := d.Set("system_id", odataIDMember(odataID, "Systems")); err != nil {
+		return nil, err
+	}
+	if err := d.Set("storage_controller_id", odataIDMember(odataID, "Storage")); err != nil {
+		return nil, err
+	}
+	if err := setImportDefaults(d, getResourceRedfishStorageVolumeSchema()); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func createRedfishStorageVolume(ctx context.Context, service *gofish.Service, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
 	// Lock the mutex to avoid race conditions with other resources
-	redfishMutexKV.Lock(getRedfishServerEndpoint(d))
-	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(d))
+	redfishMutexKV.Lock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
+	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
 
 	// Get user config
 	storageID := d.Get("storage_controller_id").(string)
 	volumeType := d.Get("volume_type").(string)
+	raidType := d.Get("raid_type").(string)
+	spanDepth := d.Get("span_depth").(int)
+	spanLength := d.Get("span_length").(int)
+	stripSizeBytes := d.Get("stripe_size_bytes").(int)
 	volumeName := d.Get("volume_name").(string)
-	optimumIOSizeBytes := d.Get("optimum_io_size_bytes").(int)
-	capacityBytes := d.Get("capacity_bytes").(int)
+	// The system sizes the volume when the sizes are not set, they are only sent when they are
+	var optimumIOSizeBytes, capacityBytes int
+	if v, ok := d.GetOk("optimum_io_size_bytes"); ok {
+		optimumIOSizeBytes = v.(int)
+	}
+	if v, ok := d.GetOk("capacity_bytes"); ok {
+		capacityBytes = v.(int)
+	}
 	driveNamesRaw := d.Get("drives").([]interface{})
 	readCachePolicy := d.Get("read_cache_policy")
 	writeCachePolicy := d.Get("write_cache_policy")
@@ -230,7 +404,8 @@ func createRedfishStorageVolume(service *gofish.Service, d *schema.ResourceData)
 
 	// Convert from []interface{} to []string for using
 	driveNames := make([]string, len(driveNamesRaw))
-	if len(driveNamesRaw) == 0 {
+	selector, selected := d.GetOk("drive_selector")
+	if len(driveNamesRaw) == 0 && !selected {
 		return diag.Errorf("Error when getting the drives: drives cannot be empty")
 	}
 	for i, raw := range driveNamesRaw {
@@ -240,17 +415,17 @@ func createRedfishStorageVolume(service *gofish.Service, d *schema.ResourceData)
 		driveNames[i] = raw.(string)
 	}
 
-	volumeJobTimeout := d.Get("volume_job_timeout")
+	volumeJobTimeout := deprecatedTimeout(d, "volume_job_timeout", defaultStorageVolumeJobTimeout)
 
 	// Get storage
-	systems, err := service.Systems()
+	system, err := getSystemResource(service, d.Get("system_id").(string))
 	if err != nil {
-		return diag.Errorf("Error when retreiving the Systems from the Redfish API")
+		return redfishDiagnostics("Error when retreiving the Systems from the Redfish API", err, nil)
 	}
 
-	storageControllers, err := systems[0].Storage()
+	storageControllers, err := system.Storage()
 	if err != nil {
-		return diag.Errorf("Error when retreiving the Storage from %v from the Redfish API", systems[0].Name)
+		return redfishDiagnostics(fmt.Sprintf("Error when retreiving the Storage from %v from the Redfish API", system.Name), err, nil)
 	}
 
 	storage, err := getStorageController(storageControllers, storageID)
@@ -261,26 +436,48 @@ func createRedfishStorageVolume(service *gofish.Service, d *schema.ResourceData)
 	// Check if settings_apply_time is doable on this controller
 	operationApplyTimes, err := storage.GetOperationApplyTimeValues()
 	if err != nil {
-		return diag.Errorf("couldn't retrieve operationApplyTimes from %s controller", storage.Name)
+		return redfishDiagnostics(fmt.Sprintf("couldn't retrieve operationApplyTimes from %s controller", storage.Name), err, nil)
 	}
 	if !checkOperationApplyTimes(applyTime.(string), operationApplyTimes) {
 		return diag.Errorf("Storage controller %s does not support settings_apply_time: %s", storageID, applyTime)
 	}
 
+	// Check if the RAID type is supported by the controller before submitting the job
+	if raidType != "" {
+		if err := checkRAIDType(storage, raidType); err != nil {
+			return diag.Errorf("Storage controller %s does not support raid_type %s: %s", storageID, raidType, err)
+		}
+	}
+
 	//Get drives
 	allStorageDrives, err := storage.Drives()
 	if err != nil {
-		return diag.Errorf("Error when getting the drives attached to controller - %s", err)
+		return redfishDiagnostics("Error when getting the drives attached to controller", err, nil)
+	}
+	var drives []*redfish.Drive
+	if selected {
+		// The slot limits are only known to be set from the configuration, 0 being a slot
+		maxSlot := -1
+		if !d.GetRawConfig().GetAttr("drive_selector").Index(cty.NumberIntVal(0)).GetAttr("max_slot").IsNull() {
+			maxSlot = d.Get("drive_selector.0.max_slot").(int)
+		}
+		drives, err = selectDrives(allStorageDrives, selector.([]interface{})[0].(map[string]interface{}), maxSlot)
+	} else {
+		drives, err = getDrives(allStorageDrives, driveNames)
 	}
-	drives, err := getDrives(allStorageDrives, driveNames)
 	if err != nil {
 		return diag.Errorf("Error when getting the drives: %s", err)
 	}
+	if spanDepth != 0 && spanLength != 0 && spanDepth*spanLength != len(drives) {
+		return diag.Errorf("span_depth %d and span_length %d make spans of %d drives while the volume has %d drives",
+			spanDepth, spanLength, spanDepth*spanLength, len(drives))
+	}
 
 	// Create volume job
-	jobID, err := createVolume(service, storage.ODataID, volumeType, volumeName, optimumIOSizeBytes, capacityBytes, readCachePolicy.(string), writeCachePolicy.(string), diskCachePolicy.(string), drives, applyTime.(string))
+	jobID, err := createVolume(service, storage.ODataID, volumeType, raidType, volumeName, optimumIOSizeBytes, capacityBytes, stripSizeBytes, spanDepth, spanLength,
+		readCachePolicy.(string), writeCachePolicy.(string), diskCachePolicy.(string), drives, applyTime.(string))
 	if err != nil {
-		return diag.Errorf("Error when creating the virtual disk on disk controller %s - %s", storageID, err)
+		return redfishDiagnostics(fmt.Sprintf("Error when creating the virtual disk on disk controller %s", storageID), err, volumeAttributePaths)
 	}
 
 	// Immediate or OnReset scenarios
@@ -288,27 +485,27 @@ func createRedfishStorageVolume(service *gofish.Service, d *schema.ResourceData)
 	case string(redfishcommon.OnResetApplyTime): // OnReset case
 		// Get reset_timeout and reset_type from schema
 		resetType := d.Get("reset_type")
-		resetTimeout := d.Get("reset_timeout")
+		resetTimeout := deprecatedTimeout(d, "reset_timeout", defaultStorageVolumeResetTimeout)
 
 		// Reboot the server
-		_, diags := PowerOperation(resetType.(string), resetTimeout.(int), intervalSimpleUpdateJobCheckTime, service)
+		_, diags := PowerOperation(ctx, resetType.(string), resetTimeout, m.(*providerMeta).waits.simpleUpdateJobCheck, service, d.Get("system_id").(string))
 		if diags.HasError() {
 			// Handle this scenario - TBD
-			return diag.Errorf("there was an issue when restarting the server")
+			return diags
 		}
 
 	}
 
 	// Wait for the job to finish
-	err = common.WaitForJobToFinish(service, jobID, intervalStorageVolumeJobCheckTime, volumeJobTimeout.(int))
+	err = common.WaitForJobToFinish(ctx, service, jobID, m.(*providerMeta).waits.storageVolumeJobCheck, volumeJobTimeout)
 	if err != nil {
-		return diag.Errorf("Error, job %s wasn't able to complete: %s", jobID, err)
+		return redfishDiagnostics(fmt.Sprintf("Error, job %s wasn't able to complete", jobID), err, volumeAttributePaths)
 	}
 
 	//Get storage volumes
 	volumes, err := storage.Volumes()
 	if err != nil {
-		return diag.Errorf("there was an issue when retrieving volumes - %s", err)
+		return redfishDiagnostics("there was an issue when retrieving volumes", err, nil)
 	}
 	volumeID, err := getVolumeID(volumes, volumeName)
 	if err != nil {
@@ -326,34 +523,128 @@ func readRedfishStorageVolume(service *gofish.Service, d *schema.ResourceData) d
 	var diags diag.Diagnostics
 
 	//Check if the volume exists
-	_, err := redfish.GetVolume(service.GetClient(), d.Id())
+	volume, err := redfish.GetVolume(service.GetClient(), d.Id())
 	if err != nil {
 		e, ok := err.(*redfishcommon.Error)
 		if !ok {
-			return diag.Errorf("There was an error with the API: %s", err)
+			return redfishDiagnostics("There was an error with the API", err, nil)
 		}
 		if e.HTTPReturnedStatusCode == http.StatusNotFound {
 			log.Printf("Volume %s doesn't exist", d.Id())
 			d.SetId("")
 			return diags
 		}
-		return diag.Errorf("Status code %d - %s", e.HTTPReturnedStatusCode, e.Error())
+		return redfishDiagnostics(fmt.Sprintf("Error when reading volume %s", d.Id()), err, nil)
+	}
+
+	drives, err := volume.Drives()
+	if err != nil {
+		return redfishDiagnostics(fmt.Sprintf("Error when retrieving the drives of volume %s", d.Id()), err, nil)
+	}
+	// gofish does not decode the RAID type, strip size and OEM properties of volumes, nor tells the properties the BMC
+	// does not report from the ones it reports empty
+	var raw struct {
+		Name               *string
+		VolumeType         *string
+		RAIDType           *string
+		CapacityBytes      *int
+		OptimumIOSizeBytes *int
+		ReadCachePolicy    *string
+		WriteCachePolicy   *string
+		StripSizeBytes     *int
+		Oem                struct {
+			Dell struct {
+				DellVolume struct {
+					DiskCachePolicy *string
+					SpanDepth       *int
+					SpanLength      *int
+				}
+			}
+		}
+	}
+	if err := getJSON(service.GetClient(), volume.ODataID, &raw); err != nil {
+		return redfishDiagnostics(fmt.Sprintf("Error when reading volume %s", d.Id()), err, nil)
 	}
 
-	/*
-		- If it has jobID, if finished, get the volumeID
-		Also never EVER trigger an update regarding disk properties for safety reasons
-	*/
+	driveODataIDs := make([]string, 0, len(drives))
+	for _, drive := range drives {
+		driveODataIDs = append(driveODataIDs, drive.ODataID)
+	}
+
+	values := map[string]interface{}{
+		"drive_odata_ids": driveODataIDs,
+		"drives":          volumeDriveNames(d.Get("drives").([]interface{}), drives),
+	}
+	// Every property the BMC reports is refreshed so that changes made outside of Terraform show up in plans, the
+	// ones it does not report are left as they are
+	reported := map[string]interface{}{
+		"volume_name":           raw.Name,
+		"volume_type":           raw.VolumeType,
+		"raid_type":             raw.RAIDType,
+		"span_depth":            raw.Oem.Dell.DellVolume.SpanDepth,
+		"span_length":           raw.Oem.Dell.DellVolume.SpanLength,
+		"stripe_size_bytes":     raw.StripSizeBytes,
+		"capacity_bytes":        raw.CapacityBytes,
+		"optimum_io_size_bytes": raw.OptimumIOSizeBytes,
+		"read_cache_policy":     raw.ReadCachePolicy,
+		"write_cache_policy":    raw.WriteCachePolicy,
+		"disk_cache_policy":     raw.Oem.Dell.DellVolume.DiskCachePolicy,
+	}
+	for k, v := range reported {
+		switch v := v.(type) {
+		case *string:
+			if v != nil {
+				values[k] = *v
+			}
+		case *int:
+			if v != nil {
+				values[k] = *v
+			}
+		}
+	}
+	// The system aligns the configured sizes, which would replace the volume on every apply if they were refreshed.
+	// The configured capacity is kept while the volume has its rounded size, and the configured optimum IO size is
+	// always kept.
+	if capacity, ok := values["capacity_bytes"].(int); ok {
+		stripeSize, ok := values["stripe_size_bytes"].(int)
+		if !ok {
+			stripeSize = d.Get("stripe_size_bytes").(int)
+		}
+		if alignedCapacity(d.Get("capacity_bytes").(int), capacity, stripeSize, len(drives)) {
+			delete(values, "capacity_bytes")
+		}
+	}
+	if _, ok := d.GetOk("optimum_io_size_bytes"); ok {
+		delete(values, "optimum_io_size_bytes")
+	}
+	for k, v := range values {
+		if err := d.Set(k, v); err != nil {
+			return diag.Errorf("Error when setting %s: %s", k, err)
+		}
+	}
 
 	return diags
 }
 
+// alignedCapacity tells whether the capacity reported for a volume is the one configured, once rounded by the system
+// to whole stripes across the drives
+func alignedCapacity(configured, reported, stripeSize, drives int) bool {
+	if configured == 0 {
+		return false
+	}
+	diff := configured - reported
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff < stripeSize*drives || diff == 0
+}
+
 func updateRedfishStorageVolume(ctx context.Context, service *gofish.Service, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
 	// Lock the mutex to avoid race conditions with other resources
-	redfishMutexKV.Lock(getRedfishServerEndpoint(d))
-	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(d))
+	redfishMutexKV.Lock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
+	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
 
 	// Get user config
 	storageID := d.Get("storage_controller_id").(string)
@@ -376,17 +667,17 @@ func updateRedfishStorageVolume(ctx context.Context, service *gofish.Service, d
 		driveNames[i] = raw.(string)
 	}
 
-	volumeJobTimeout := d.Get("volume_job_timeout")
+	volumeJobTimeout := deprecatedTimeout(d, "volume_job_timeout", defaultStorageVolumeJobTimeout)
 
 	// Get storage
-	systems, err := service.Systems()
+	system, err := getSystemResource(service, d.Get("system_id").(string))
 	if err != nil {
-		return diag.Errorf("Error when retreiving the Systems from the Redfish API")
+		return redfishDiagnostics("Error when retreiving the Systems from the Redfish API", err, nil)
 	}
 
-	storageControllers, err := systems[0].Storage()
+	storageControllers, err := system.Storage()
 	if err != nil {
-		return diag.Errorf("Error when retreiving the Storage from %v from the Redfish API", systems[0].Name)
+		return redfishDiagnostics(fmt.Sprintf("Error when retreiving the Storage from %v from the Redfish API", system.Name), err, nil)
 	}
 
 	storage, err := getStorageController(storageControllers, storageID)
@@ -397,7 +688,7 @@ func updateRedfishStorageVolume(ctx context.Context, service *gofish.Service, d
 	// Check if settings_apply_time is doable on this controller
 	operationApplyTimes, err := storage.GetOperationApplyTimeValues()
 	if err != nil {
-		return diag.Errorf("couldn't retrieve operationApplyTimes from %s controller", storage.Name)
+		return redfishDiagnostics(fmt.Sprintf("couldn't retrieve operationApplyTimes from %s controller", storage.Name), err, nil)
 	}
 	if !checkOperationApplyTimes(applyTime.(string), operationApplyTimes) {
 		return diag.Errorf("Storage controller %s does not support settings_apply_time: %s", storageID, applyTime)
@@ -406,7 +697,7 @@ func updateRedfishStorageVolume(ctx context.Context, service *gofish.Service, d
 	// Update volume job
 	jobID, err := updateVolume(service, d.Id(), readCachePolicy.(string), writeCachePolicy.(string), volumeName, diskCachePolicy.(string), applyTime.(string))
 	if err != nil {
-		return diag.Errorf("Error when updating the virtual disk on disk controller %s - %s", storageID, err)
+		return redfishDiagnostics(fmt.Sprintf("Error when updating the virtual disk on disk controller %s", storageID), err, volumeAttributePaths)
 	}
 
 	// Immediate or OnReset scenarios
@@ -414,79 +705,96 @@ func updateRedfishStorageVolume(ctx context.Context, service *gofish.Service, d
 	case string(redfishcommon.OnResetApplyTime): // OnReset case
 		// Get reset_timeout and reset_type from schema
 		resetType := d.Get("reset_type")
-		resetTimeout := d.Get("reset_timeout")
+		resetTimeout := deprecatedTimeout(d, "reset_timeout", defaultStorageVolumeResetTimeout)
 
 		// Reboot the server
-		_, diags := PowerOperation(resetType.(string), resetTimeout.(int), intervalSimpleUpdateJobCheckTime, service)
+		_, diags := PowerOperation(ctx, resetType.(string), resetTimeout, m.(*providerMeta).waits.simpleUpdateJobCheck, service, d.Get("system_id").(string))
 		if diags.HasError() {
 			// Handle this scenario - TBD
-			return diag.Errorf("there was an issue when restarting the server")
+			return diags
 		}
 
 	}
 
 	// Wait for the job to finish
-	err = common.WaitForJobToFinish(service, jobID, intervalStorageVolumeJobCheckTime, volumeJobTimeout.(int))
+	err = common.WaitForJobToFinish(ctx, service, jobID, m.(*providerMeta).waits.storageVolumeJobCheck, volumeJobTimeout)
 	if err != nil {
-		return diag.Errorf("Error, job %s wasn't able to complete: %s", jobID, err)
+		return redfishDiagnostics(fmt.Sprintf("Error, job %s wasn't able to complete", jobID), err, volumeAttributePaths)
 	}
 
 	return diags
 }
 
-func deleteRedfishStorageVolume(service *gofish.Service, d *schema.ResourceData) diag.Diagnostics {
+func deleteRedfishStorageVolume(ctx context.Context, service *gofish.Service, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
 	// Lock the mutex to avoid race conditions with other resources
-	redfishMutexKV.Lock(getRedfishServerEndpoint(d))
-	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(d))
+	redfishMutexKV.Lock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
+	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
 
 	// Get vars from schema
 	applyTime := d.Get("settings_apply_time")
-	volumeJobTimeout := d.Get("volume_job_timeout")
+	volumeJobTimeout := deprecatedTimeout(d, "volume_job_timeout", defaultStorageVolumeJobTimeout)
 
 	jobID, err := deleteVolume(service, d.Id())
 	if err != nil {
-		return diag.Errorf("Error. There was an error when deleting volume %s - %s", d.Id(), err)
+		return redfishDiagnostics(fmt.Sprintf("Error. There was an error when deleting volume %s", d.Id()), err, nil)
 	}
 
 	switch applyTime.(string) {
 	case string(redfishcommon.OnResetApplyTime): // OnReset case
 		// Get reset_timeout and reset_type from schema
 		resetType := d.Get("reset_type")
-		resetTimeout := d.Get("reset_timeout")
+		resetTimeout := deprecatedTimeout(d, "reset_timeout", defaultStorageVolumeResetTimeout)
 
 		// Reboot the server
-		_, diags := PowerOperation(resetType.(string), resetTimeout.(int), intervalSimpleUpdateJobCheckTime, service)
+		_, diags := PowerOperation(ctx, resetType.(string), resetTimeout, m.(*providerMeta).waits.simpleUpdateJobCheck, service, d.Get("system_id").(string))
 		if diags.HasError() {
 			// Handle this scenario - TBD
-			return diag.Errorf("there was an issue when restarting the server")
+			return diags
 		}
 	}
 
 	//WAIT FOR VOLUME TO DELETE
-	err = common.WaitForJobToFinish(service, jobID, intervalStorageVolumeJobCheckTime, volumeJobTimeout.(int))
+	err = common.WaitForJobToFinish(ctx, service, jobID, m.(*providerMeta).waits.storageVolumeJobCheck, volumeJobTimeout)
 	if err != nil {
-		return diag.Errorf("Error, timeout reached when waiting for job %s to finish. %s", jobID, err)
+		return redfishDiagnostics(fmt.Sprintf("Error, timeout reached when waiting for job %s to finish", jobID), err, nil)
 	}
 
 	return diags
 }
 
-func getStorageController(storageControllers []*redfish.Storage, diskControllerID string) (*redfish.Storage, error) {
-	for _, storage := range storageControllers {
-		if storage.Entity.ID == diskControllerID {
-			return storage, nil
+// volumeDriveNames returns the names of the drives of a volume. The configured order is kept when the drives are the
+// same, since the BMC may list them in another one.
+func volumeDriveNames(configured []interface{}, drives []*redfish.Drive) []string {
+	names := make([]string, 0, len(drives))
+	count := make(map[string]int, len(drives))
+	for _, drive := range drives {
+		names = append(names, drive.Name)
+		count[drive.Name]++
+	}
+	if len(configured) != len(names) {
+		return names
+	}
+	for _, v := range configured {
+		name, _ := v.(string)
+		if count[name] == 0 {
+			return names
 		}
+		count[name]--
+	}
+	ordered := make([]string, 0, len(configured))
+	for _, v := range configured {
+		ordered = append(ordered, v.(string))
 	}
-	return nil, fmt.Errorf("error. Didn't find the storage controller %v", diskControllerID)
+	return ordered
 }
 
 func deleteVolume(service *gofish.Service, volumeURI string) (jobID string, err error) {
 	//TODO - Check if we can delete immediately or if we need to schedule a job
 	res, err := service.GetClient().Delete(volumeURI)
 	if err != nil {
-		return "", fmt.Errorf("error while deleting the volume %s", volumeURI)
+		return "", fmt.Errorf("error while deleting the volume %s: %w", volumeURI, err)
 	}
 	defer res.Body.Close()
 	if res.StatusCode != http.StatusAccepted {
@@ -499,19 +807,39 @@ func deleteVolume(service *gofish.Service, volumeURI string) (jobID string, err
 	return jobID, nil
 }
 
-func getDrives(drives []*redfish.Drive, driveNames []string) ([]*redfish.Drive, error) {
-	drivesToReturn := []*redfish.Drive{}
-	for _, v := range drives {
-		for _, w := range driveNames {
-			if v.Name == w {
-				drivesToReturn = append(drivesToReturn, v)
-			}
+// selectDrives chooses the drives matching the criteria of a drive_selector block, in the order of their slots.
+// maxSlot is -1 when the slots have no upper limit.
+func selectDrives(drives []*redfish.Drive, selector map[string]interface{}, maxSlot int) ([]*redfish.Drive, error) {
+	mediaType := selector["media_type"].(string)
+	protocol := selector["protocol"].(string)
+	minCapacityBytes := selector["min_capacity_bytes"].(int)
+	minSlot := selector["min_slot"].(int)
+	unassignedOnly := selector["unassigned_only"].(bool)
+	count := selector["count"].(int)
+
+	slotted := minSlot > 0 || maxSlot >= 0
+	matching := []*redfish.Drive{}
+	for _, drive := range drives {
+		slot := drive.PhysicalLocation.PartLocation.LocationOrdinalValue
+		switch {
+		case mediaType != "" && string(drive.MediaType) != mediaType,
+			protocol != "" && string(drive.Protocol) != protocol,
+			int64(drive.CapacityBytes) < int64(minCapacityBytes),
+			slotted && drive.PhysicalLocation.PartLocation.LocationType == "",
+			slot < minSlot,
+			maxSlot >= 0 && slot > maxSlot,
+			unassignedOnly && (drive.VolumesCount > 0 || (drive.HotspareType != "" && drive.HotspareType != redfish.NoneHotspareType)):
+			continue
 		}
+		matching = append(matching, drive)
 	}
-	if len(driveNames) != len(drivesToReturn) {
-		return nil, fmt.Errorf("any of the drives you inserted doesn't exist")
+	if len(matching) < count {
+		return nil, fmt.Errorf("%d drives of the storage controller match drive_selector, %d are needed", len(matching), count)
 	}
-	return drivesToReturn, nil
+	sort.SliceStable(matching, func(i, j int) bool {
+		return matching[i].PhysicalLocation.PartLocation.LocationOrdinalValue < matching[j].PhysicalLocation.PartLocation.LocationOrdinalValue
+	})
+	return matching[:count], nil
 }
 
 /*
@@ -520,9 +848,13 @@ createVolume creates a virtualdisk on a disk controller by using the redfish API
 func createVolume(service *gofish.Service,
 	storageLink string,
 	volumeType string,
+	raidType string,
 	volumeName string,
 	optimumIOSizeBytes int,
 	capacityBytes int,
+	stripSizeBytes int,
+	spanDepth int,
+	spanLength int,
 	readCachePolicy string,
 	writeCachePolicy string,
 	diskCachePolicy string,
@@ -530,18 +862,38 @@ func createVolume(service *gofish.Service,
 	applyTime string) (jobID string, err error) {
 
 	newVolume := make(map[string]interface{})
-	newVolume["VolumeType"] = volumeType
+	// The RAID type replaces the volume type in newer versions of the Redfish schema, only one of them is set
+	if volumeType != "" {
+		newVolume["VolumeType"] = volumeType
+	}
+	if raidType != "" {
+		newVolume["RAIDType"] = raidType
+	}
 	newVolume["DisplayName"] = volumeName
 	newVolume["Name"] = volumeName
 	newVolume["ReadCachePolicy"] = readCachePolicy
 	newVolume["WriteCachePolicy"] = writeCachePolicy
-	newVolume["CapacityBytes"] = capacityBytes
-	newVolume["OptimumIOSizeBytes"] = optimumIOSizeBytes
+	if capacityBytes != 0 {
+		newVolume["CapacityBytes"] = capacityBytes
+	}
+	if optimumIOSizeBytes != 0 {
+		newVolume["OptimumIOSizeBytes"] = optimumIOSizeBytes
+	}
+	if stripSizeBytes != 0 {
+		newVolume["StripSizeBytes"] = stripSizeBytes
+	}
+	dellVolume := map[string]interface{}{
+		"DiskCachePolicy": diskCachePolicy,
+	}
+	if spanDepth != 0 {
+		dellVolume["SpanDepth"] = spanDepth
+	}
+	if spanLength != 0 {
+		dellVolume["SpanLength"] = spanLength
+	}
 	newVolume["Oem"] = map[string]map[string]map[string]interface{}{
 		"Dell": {
-			"DellVolume": {
-				"DiskCachePolicy": diskCachePolicy,
-			},
+			"DellVolume": dellVolume,
 		},
 	}
 	newVolume["@Redfish.OperationApplyTime"] = applyTime
@@ -560,7 +912,7 @@ func createVolume(service *gofish.Service,
 	}
 	defer res.Body.Close()
 	if res.StatusCode != http.StatusAccepted {
-		return "", fmt.Errorf("the query was unsucessfull")
+		return "", fmt.Errorf("the query was unsucessfull, the status code was %d instead of %d", res.StatusCode, http.StatusAccepted)
 	}
 	jobID = res.Header.Get("Location")
 	if len(jobID) == 0 {
@@ -600,7 +952,7 @@ func updateVolume(service *gofish.Service,
 	}
 	defer res.Body.Close()
 	if res.StatusCode != http.StatusAccepted {
-		return "", fmt.Errorf("the query was unsucessfull")
+		return "", fmt.Errorf("the query was unsucessfull, the status code was %d instead of %d", res.StatusCode, http.StatusAccepted)
 	}
 	jobID = res.Header.Get("Location")
 	if len(jobID) == 0 {
@@ -619,6 +971,21 @@ func getVolumeID(volumes []*redfish.Volume, volumeName string) (volumeLink strin
 	return "", fmt.Errorf("couldn't find a volume with the provided name")
 }
 
+// checkRAIDType checks that a controller of the storage supports a RAID type. Controllers which do not tell the RAID
+// types they support are trusted with any.
+func checkRAIDType(storage *redfish.Storage, raidType string) error {
+	var supported []string
+	for _, controller := range storage.StorageControllers {
+		for _, v := range controller.SupportedRAIDTypes {
+			supported = append(supported, string(v))
+		}
+	}
+	if len(supported) == 0 || contains(supported, raidType) {
+		return nil
+	}
+	return fmt.Errorf("the supported RAID types are %s", strings.Join(supported, ", "))
+}
+
 func checkOperationApplyTimes(optionToCheck string, storageOperationApplyTimes []redfishcommon.OperationApplyTime) (result bool) {
 	for _, v := range storageOperationApplyTimes {
 		if optionToCheck == string(v) {
@@ -2,10 +2,13 @@ package redfish
 
 import (
 	"fmt"
+	"net/http"
 	"regexp"
 	"testing"
 
+	"github.com/dell/terraform-provider-redfish/internal/emulator"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func TestAccRedfishStorageVolumeCreate_basic(t *testing.T) {
@@ -189,6 +192,324 @@ func TestAccRedfishStorageVolumeUpdate_basic(t *testing.T) {
 	})
 }
 
+// Test to create, update and delete a volume on an emulated iDRAC
+func TestRedfishStorageVolume_emulated(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	drive := "/redfish/v1/Systems/System.Embedded.1/Storage/RAID.Integrated.1-1/Drives/Disk.Bay.1:Enclosure.Internal.0-1:RAID.Integrated.1-1"
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		CheckDestroy:      checkEmulatedResource(server, drive, "Links/Volumes@odata.count", float64(0)),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceStorageVolumeConfig(
+					creds,
+					"RAID.Integrated.1-1",
+					"TerraformVol1",
+					"NonRedundant",
+					"Solid State Disk 0:0:1",
+					"Immediate",
+					"Off",
+					"UnprotectedWriteBack",
+					"ForceRestart",
+					100,
+					1200,
+					100000000000,
+					131072),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_storage_volume.volume", "volume_name", "TerraformVol1"),
+					checkEmulatedResource(server, drive, "Links/Volumes@odata.count", float64(1)),
+				),
+			},
+			{
+				Config: testAccRedfishResourceStorageVolumeConfig(
+					creds,
+					"RAID.Integrated.1-1",
+					"TerraformVol1",
+					"NonRedundant",
+					"Solid State Disk 0:0:1",
+					"OnReset",
+					"AdaptiveReadAhead",
+					"WriteThrough",
+					"ForceRestart",
+					100,
+					1200,
+					100000000000,
+					131072),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_storage_volume.volume", "read_cache_policy", "AdaptiveReadAhead"),
+					checkEmulatedResourceOf(server, "redfish_storage_volume.volume", "ReadCachePolicy", "AdaptiveReadAhead"),
+				),
+			},
+		},
+	})
+}
+
+// Test that the properties of a volume changed outside of Terraform are read and changed back
+func TestRedfishStorageVolume_emulatedDrift(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	config := testAccRedfishResourceStorageVolumeMinConfig(
+		creds,
+		"RAID.Integrated.1-1",
+		"TerraformVol1",
+		"NonRedundant",
+		"Solid State Disk 0:0:1")
+	var volumeURI string
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_storage_volume.volume", "capacity_bytes", "479559942144"),
+					resource.TestCheckResourceAttr("redfish_storage_volume.volume", "disk_cache_policy", "Enabled"),
+					resource.TestCheckResourceAttr("redfish_storage_volume.volume", "drives.0", "Solid State Disk 0:0:1"),
+					func(s *terraform.State) error {
+						volumeURI = s.RootModule().Resources["redfish_storage_volume.volume"].Primary.ID
+						return nil
+					},
+				),
+			},
+			{
+				PreConfig: func() {
+					server.Modify(volumeURI, map[string]interface{}{
+						"Name":            "Renamed",
+						"ReadCachePolicy": "ReadAhead",
+						"Oem":             map[string]interface{}{"Dell": map[string]interface{}{"DellVolume": map[string]interface{}{"DiskCachePolicy": "Disabled"}}},
+					})
+				},
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					checkEmulatedResourceOf(server, "redfish_storage_volume.volume", "Name", "TerraformVol1"),
+					checkEmulatedResourceOf(server, "redfish_storage_volume.volume", "ReadCachePolicy", "Off"),
+					checkEmulatedResourceOf(server, "redfish_storage_volume.volume", "Oem/Dell/DellVolume/DiskCachePolicy", "Enabled"),
+					resource.TestCheckResourceAttrPtr("redfish_storage_volume.volume", "id", &volumeURI),
+				),
+			},
+			{
+				// Properties changed to empty values are read as well
+				PreConfig: func() {
+					server.Modify(volumeURI, map[string]interface{}{"ReadCachePolicy": ""})
+				},
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+// Test that the sizes left out of the config are left out of the volume created, for the system to choose them
+func TestRedfishStorageVolume_emulatedSystemSized(t *testing.T) {
+	_, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// The emulated iDRAC refuses volumes of 0 bytes
+				Config: testAccRedfishResourceStorageVolumeMinConfig(
+					creds,
+					"RAID.Integrated.1-1",
+					"TerraformVol1",
+					"NonRedundant",
+					"Solid State Disk 0:0:1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_storage_volume.volume", "capacity_bytes", "479559942144"),
+					resource.TestCheckResourceAttr("redfish_storage_volume.volume", "optimum_io_size_bytes", "65536"),
+				),
+			},
+		},
+	})
+}
+
+// Test that a capacity the system rounds to whole stripes does not replace the volume on the next apply
+func TestRedfishStorageVolume_emulatedAlignedCapacity(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	config := testAccRedfishResourceStorageVolumeConfig(
+		creds,
+		"RAID.Integrated.1-1",
+		"TerraformVol1",
+		"NonRedundant",
+		"Solid State Disk 0:0:1",
+		"Immediate",
+		"Off",
+		"UnprotectedWriteBack",
+		"ForceRestart",
+		100,
+		1200,
+		100000000001,
+		131072)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_storage_volume.volume", "capacity_bytes", "100000000001"),
+					resource.TestCheckResourceAttr("redfish_storage_volume.volume", "optimum_io_size_bytes", "131072"),
+					checkEmulatedResourceOf(server, "redfish_storage_volume.volume", "CapacityBytes", float64(99999940608)),
+				),
+			},
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+// Test to create a volume on drives chosen by criteria on an emulated iDRAC
+func TestRedfishStorageVolume_emulatedDriveSelector(t *testing.T) {
+	_, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceStorageVolumeSelectorConfig(creds, "Mirrored", `
+				  media_type = "SSD"
+				  protocol   = "SATA"
+				  min_slot   = 2
+				  count      = 2`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_storage_volume.volume", "drives.#", "2"),
+					resource.TestCheckResourceAttr("redfish_storage_volume.volume", "drives.0", "Solid State Disk 0:0:2"),
+					resource.TestCheckResourceAttr("redfish_storage_volume.volume", "drives.1", "Solid State Disk 0:0:3"),
+					resource.TestCheckResourceAttr("redfish_storage_volume.volume", "drive_odata_ids.0",
+						"/redfish/v1/Systems/System.Embedded.1/Storage/RAID.Integrated.1-1/Drives/Disk.Bay.2:Enclosure.Internal.0-1:RAID.Integrated.1-1"),
+				),
+			},
+		},
+	})
+}
+
+// Test to create a RAID 6 volume and a RAID 10 volume with explicit spans on an emulated iDRAC
+func TestRedfishStorageVolume_emulatedRAIDType(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceStorageVolumeRAIDConfig(creds, `
+				  raid_type         = "RAID6"
+				  stripe_size_bytes = 131072
+				  drive_selector {
+				    count = 4
+				  }`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_storage_volume.volume", "volume_type", "StripedWithParity"),
+					resource.TestCheckResourceAttr("redfish_storage_volume.volume", "span_depth", "1"),
+					resource.TestCheckResourceAttr("redfish_storage_volume.volume", "span_length", "4"),
+					resource.TestCheckResourceAttr("redfish_storage_volume.volume", "capacity_bytes", "959119884288"),
+					checkEmulatedResourceOf(server, "redfish_storage_volume.volume", "RAIDType", "RAID6"),
+					checkEmulatedResourceOf(server, "redfish_storage_volume.volume", "StripSizeBytes", float64(131072)),
+				),
+			},
+			{
+				Config: testAccRedfishResourceStorageVolumeRAIDConfig(creds, `
+				  raid_type   = "RAID10"
+				  span_depth  = 2
+				  span_length = 2
+				  drive_selector {
+				    count = 4
+				  }`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_storage_volume.volume", "volume_type", "SpannedMirrors"),
+					resource.TestCheckResourceAttr("redfish_storage_volume.volume", "stripe_size_bytes", "65536"),
+					checkEmulatedResourceOf(server, "redfish_storage_volume.volume", "Oem/Dell/DellVolume/SpanDepth", float64(2)),
+				),
+			},
+		},
+	})
+}
+
+// Test that RAID types the controller does not support and spans not matching the drives are refused
+func TestRedfishStorageVolume_emulatedInvalidRAIDType(t *testing.T) {
+	_, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceStorageVolumeRAIDConfig(creds, `
+				  raid_type = "RAID1E"
+				  drives    = ["Solid State Disk 0:0:0", "Solid State Disk 0:0:1", "Solid State Disk 0:0:2"]`),
+				ExpectError: regexp.MustCompile("does not support raid_type RAID1E: the supported RAID types are RAID0, RAID1"),
+			},
+			{
+				Config: testAccRedfishResourceStorageVolumeRAIDConfig(creds, `
+				  raid_type   = "RAID10"
+				  span_depth  = 3
+				  span_length = 2
+				  drive_selector {
+				    count = 4
+				  }`),
+				ExpectError: regexp.MustCompile("span_depth 3 and span_length 2 make spans of 6 drives while the volume has 4 drives"),
+			},
+			{
+				Config: testAccRedfishResourceStorageVolumeRAIDConfig(creds, `
+				  raid_type   = "RAID10"
+				  volume_type = "SpannedMirrors"
+				  drive_selector {
+				    count = 4
+				  }`),
+				ExpectError: regexp.MustCompile("only one of `raid_type,volume_type` can be specified"),
+			},
+		},
+	})
+}
+
+// Test that drives which cannot be found are reported
+func TestRedfishStorageVolume_emulatedMissingDrives(t *testing.T) {
+	_, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceStorageVolumeMinConfig(
+					creds,
+					"RAID.Integrated.1-1",
+					"TerraformVol1",
+					"NonRedundant",
+					"Solid State Disk 0:0:9"),
+				ExpectError: regexp.MustCompile("the storage controller has no drive named Solid State Disk 0:0:9"),
+			},
+			{
+				Config: testAccRedfishResourceStorageVolumeSelectorConfig(creds, "NonRedundant", `
+				  media_type = "HDD"
+				  count      = 1`),
+				ExpectError: regexp.MustCompile("0 drives of the storage controller match drive_selector, 1 are needed"),
+			},
+		},
+	})
+}
+
+// Test that a volume creation accepted without the location of its job is reported
+func TestRedfishStorageVolume_emulatedNoLocation(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	server.Inject(emulator.Fault{
+		Method:     http.MethodPost,
+		URI:        "/redfish/v1/Systems/System.Embedded.1/Storage/RAID.Integrated.1-1/Volumes",
+		NoLocation: true,
+	})
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceStorageVolumeMinConfig(
+					creds,
+					"RAID.Integrated.1-1",
+					"TerraformVol1",
+					"NonRedundant",
+					"Solid State Disk 0:0:1"),
+				ExpectError: regexp.MustCompile("there was some error when retreiving the jobID"),
+			},
+		},
+	})
+}
+
 func testAccRedfishResourceStorageVolumeConfig(testingInfo TestingServerCredentials,
 	storage_controller_id string,
 	volume_name string,
@@ -275,3 +596,54 @@ func testAccRedfishResourceStorageVolumeMinConfig(testingInfo TestingServerCrede
 		drives,
 	)
 }
+
+func testAccRedfishResourceStorageVolumeSelectorConfig(testingInfo TestingServerCredentials,
+	volume_type string,
+	drive_selector string,
+) string {
+	return fmt.Sprintf(`
+	resource "redfish_storage_volume" "volume" {
+		redfish_server {
+		  user         = "%s"
+		  password     = "%s"
+		  endpoint     = "https://%s"
+		  ssl_insecure = true
+		}
+	  
+		storage_controller_id = "RAID.Integrated.1-1"
+		volume_name           = "TerraformVol1"
+		volume_type           = "%s"
+		drive_selector {
+		  %s
+		}
+	  }
+	  `,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+		volume_type,
+		drive_selector,
+	)
+}
+
+func testAccRedfishResourceStorageVolumeRAIDConfig(testingInfo TestingServerCredentials, settings string) string {
+	return fmt.Sprintf(`
+	resource "redfish_storage_volume" "volume" {
+		redfish_server {
+		  user         = "%s"
+		  password     = "%s"
+		  endpoint     = "https://%s"
+		  ssl_insecure = true
+		}
+	  
+		storage_controller_id = "RAID.Integrated.1-1"
+		volume_name           = "TerraformVol1"
+		%s
+	  }
+	  `,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+		settings,
+	)
+}
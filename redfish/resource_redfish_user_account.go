@@ -3,6 +3,7 @@ package redfish
 import (
 	"context"
 	"fmt"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strconv"
@@ -15,6 +16,14 @@ import (
 	"github.com/stmcginnis/gofish/redfish"
 )
 
+// userAttributePaths locates the attributes setting the properties of accounts
+var userAttributePaths = attributePaths{
+	"UserName": "username",
+	"Password": "password",
+	"Enabled":  "enabled",
+	"RoleId":   "role_id",
+}
+
 func resourceRedfishUserAccount() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceRedfishUserAccountCreate,
@@ -22,40 +31,16 @@ func resourceRedfishUserAccount() *schema.Resource {
 		UpdateContext: resourceRedfishUserAccountUpdate,
 		DeleteContext: resourceRedfishUserAccountDelete,
 		Schema:        getResourceRedfishUserAccountSchema(),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceRedfishUserAccountImport,
+		},
 	}
 }
 
 func getResourceRedfishUserAccountSchema() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
-		"redfish_server": {
-			Type:        schema.TypeList,
-			Required:    true,
-			Description: "This list contains the different redfish endpoints to manage (different servers)",
-			Elem: &schema.Resource{
-				Schema: map[string]*schema.Schema{
-					"user": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Description: "This field is the user to login against the redfish API",
-					},
-					"password": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Description: "This field is the password related to the user given",
-					},
-					"endpoint": {
-						Type:        schema.TypeString,
-						Required:    true,
-						Description: "This field is the endpoint where the redfish API is placed",
-					},
-					"ssl_insecure": {
-						Type:        schema.TypeBool,
-						Optional:    true,
-						Description: "This field indicates if the SSL/TLS certificate must be verified",
-					},
-				},
-			},
-		},
+		"redfish_alias":  redfishAliasSchema(),
+		"redfish_server": redfishServerSchema(),
 		"user_id": {
 			Type:        schema.TypeString,
 			Optional:    true,
@@ -98,15 +83,15 @@ func getResourceRedfishUserAccountSchema() map[string]*schema.Schema {
 }
 
 func resourceRedfishUserAccountCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	service, err := NewConfig(m.(*schema.ResourceData), d)
+	service, err := NewConfig(m.(*providerMeta).config, d)
 	if err != nil {
 		return diag.Errorf(err.Error())
 	}
-	return createRedfishUserAccount(service, d)
+	return createRedfishUserAccount(service, d, m)
 }
 
 func resourceRedfishUserAccountRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	service, err := NewConfig(m.(*schema.ResourceData), d)
+	service, err := NewConfig(m.(*providerMeta).config, d)
 	if err != nil {
 		return diag.Errorf(err.Error())
 	}
@@ -114,7 +99,7 @@ func resourceRedfishUserAccountRead(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceRedfishUserAccountUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	service, err := NewConfig(m.(*schema.ResourceData), d)
+	service, err := NewConfig(m.(*providerMeta).config, d)
 	if err != nil {
 		return diag.Errorf(err.Error())
 	}
@@ -125,19 +110,34 @@ func resourceRedfishUserAccountUpdate(ctx context.Context, d *schema.ResourceDat
 }
 
 func resourceRedfishUserAccountDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	service, err := NewConfig(m.(*schema.ResourceData), d)
+	service, err := NewConfig(m.(*providerMeta).config, d)
 	if err != nil {
 		return diag.Errorf(err.Error())
 	}
-	return deleteRedfishUserAccount(service, d)
+	return deleteRedfishUserAccount(service, d, m)
+}
+
+// resourceRedfishUserAccountImport imports an account from an ID such as
+// https://my-server-1.myawesomecompany.org|/redfish/v1/AccountService/Accounts/3. Passwords cannot be read back, so
+// the one in the configuration is applied on the next apply.
+func resourceRedfishUserAccountImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	odataID, err := parseImportID(d, m)
+	if err != nil {
+		return nil, err
+	}
+	d.SetId(path.Base(odataID))
+	if err := setImportDefaults(d, getResourceRedfishUserAccountSchema()); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
 }
 
-func createRedfishUserAccount(service *gofish.Service, d *schema.ResourceData) diag.Diagnostics {
+func createRedfishUserAccount(service *gofish.Service, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
 	// Lock the mutex to avoid race conditions with other resources
-	redfishMutexKV.Lock(getRedfishServerEndpoint(d))
-	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(d))
+	redfishMutexKV.Lock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
+	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
 
 	// validate Password
 	err := validatePassword(d.Get("password").(string))
@@ -147,7 +147,7 @@ func createRedfishUserAccount(service *gofish.Service, d *schema.ResourceData) d
 
 	accountList, err := getAccountList(service)
 	if err != nil {
-		return diag.Errorf("Error when retrieving account list %v", err)
+		return redfishDiagnostics("Error when retrieving account list", err, nil)
 	}
 
 	// check if username already exists
@@ -184,7 +184,7 @@ func createRedfishUserAccount(service *gofish.Service, d *schema.ResourceData) d
 			//Ideally a go routine for each server should be done
 			res, err := service.GetClient().Patch(account.ODataID, payload)
 			if err != nil {
-				return diag.Errorf("Error when contacting the redfish API %v", err) //This error might happen when a user was created outside terraform
+				return redfishDiagnostics("Error when contacting the redfish API", err, userAttributePaths) //This error might happen when a user was created outside terraform
 			}
 			if res.StatusCode != 200 {
 				return diag.Errorf("There was an issue with the APIClient. HTTP error code %d", res.StatusCode)
@@ -204,7 +204,7 @@ func readRedfishUserAccount(service *gofish.Service, d *schema.ResourceData) dia
 
 	accountList, err := getAccountList(service)
 	if err != nil {
-		return diag.Errorf("Error when retrieving account list %v", err)
+		return redfishDiagnostics("Error when retrieving account list", err, nil)
 	}
 
 	account, err := getAccount(accountList, d.Id())
@@ -229,8 +229,8 @@ func updateRedfishUserAccount(ctx context.Context, service *gofish.Service, d *s
 	var userUpdated bool
 
 	// Lock the mutex to avoid race conditions with other resources
-	redfishMutexKV.Lock(getRedfishServerEndpoint(d))
-	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(d))
+	redfishMutexKV.Lock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
+	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
 
 	// validate Password
 	err := validatePassword(d.Get("password").(string))
@@ -240,7 +240,7 @@ func updateRedfishUserAccount(ctx context.Context, service *gofish.Service, d *s
 
 	accountList, err := getAccountList(service)
 	if err != nil {
-		return diag.Errorf("Error when retrieving account list %v", err)
+		return redfishDiagnostics("Error when retrieving account list", err, nil)
 	}
 
 	account, err := getAccount(accountList, d.Id())
@@ -268,7 +268,7 @@ func updateRedfishUserAccount(ctx context.Context, service *gofish.Service, d *s
 		payload["RoleId"] = d.Get("role_id")
 		res, err := service.GetClient().Patch(account.ODataID, payload)
 		if err != nil {
-			return diag.Errorf("Error when contacting the redfish API %v", err)
+			return redfishDiagnostics("Error when contacting the redfish API", err, userAttributePaths)
 		}
 		if res.StatusCode != 200 {
 			return diag.Errorf("There was an issue with the server. HTTP error code %d", res.StatusCode)
@@ -277,16 +277,16 @@ func updateRedfishUserAccount(ctx context.Context, service *gofish.Service, d *s
 	return diags
 }
 
-func deleteRedfishUserAccount(service *gofish.Service, d *schema.ResourceData) diag.Diagnostics {
+func deleteRedfishUserAccount(service *gofish.Service, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
 	// Lock the mutex to avoid race conditions with other resources
-	redfishMutexKV.Lock(getRedfishServerEndpoint(d))
-	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(d))
+	redfishMutexKV.Lock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
+	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
 
 	accountList, err := getAccountList(service)
 	if err != nil {
-		return diag.Errorf("Error when retrieving account list %v", err)
+		return redfishDiagnostics("Error when retrieving account list", err, nil)
 	}
 
 	account, err := getAccount(accountList, d.Id())
@@ -299,7 +299,7 @@ func deleteRedfishUserAccount(service *gofish.Service, d *schema.ResourceData) d
 	payload["RoleId"] = "None"
 	res, err := service.GetClient().Patch(account.ODataID, payload)
 	if err != nil {
-		return diag.Errorf("Error when contacting the redfish API %v", err)
+		return redfishDiagnostics("Error when contacting the redfish API", err, userAttributePaths)
 	}
 	if res.StatusCode != 200 {
 		return diag.Errorf("There was an issue with the server. HTTP error code %d", res.StatusCode)
@@ -310,7 +310,7 @@ func deleteRedfishUserAccount(service *gofish.Service, d *schema.ResourceData) d
 	payload["UserName"] = ""
 	res, err = service.GetClient().Patch(account.ODataID, payload)
 	if err != nil {
-		return diag.Errorf("Error when contacting the redfish API %v", err)
+		return redfishDiagnostics("Error when contacting the redfish API", err, userAttributePaths)
 	}
 	if res.StatusCode != 200 {
 		return diag.Errorf("There was an issue with the server. HTTP error code %d", res.StatusCode)
@@ -2,7 +2,9 @@ package redfish
 
 import (
 	"fmt"
+	"github.com/dell/terraform-provider-redfish/internal/emulator"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"net/http"
 	"regexp"
 	"testing"
 )
@@ -199,6 +201,146 @@ func TestAccRedfishUserUpdateUser_basic(t *testing.T) {
 	})
 }
 
+// Test to import an existing user - positive
+func TestAccRedfishUser_import(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceUserAliasConfig(creds, "test1", "test1234", "15"),
+			},
+			{
+				ResourceName:            "redfish_user_account.user_config",
+				ImportState:             true,
+				ImportStateId:           "server1|/redfish/v1/AccountService/Accounts/15",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"password"},
+			},
+		},
+	})
+}
+
+// Test to create, update and delete a user on an emulated iDRAC
+func TestRedfishUser_emulated(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		CheckDestroy:      checkEmulatedResource(server, "/redfish/v1/AccountService/Accounts/3", "UserName", ""),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceUserConfig(creds, "test1", "T0pSecret!", "Operator", true, "3"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_user_account.user_config", "username", "test1"),
+					checkEmulatedResource(server, "/redfish/v1/AccountService/Accounts/3", "RoleId", "Operator"),
+				),
+			},
+			{
+				Config: testAccRedfishResourceUserConfig(creds, "test1", "T0pSecret!", "ReadOnly", false, "3"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_user_account.user_config", "role_id", "ReadOnly"),
+					checkEmulatedResource(server, "/redfish/v1/AccountService/Accounts/3", "Enabled", false),
+				),
+			},
+		},
+	})
+}
+
+// Test to import a user on an emulated iDRAC through its endpoint, whose self-signed certificate is only trusted with
+// the TLS settings of the import ID
+func TestRedfishUser_emulatedImportEndpoint(t *testing.T) {
+	_, creds := newEmulatedServer(t)
+	config := fmt.Sprintf(`
+		provider "redfish" {
+		  user     = "%s"
+		  password = "%s"
+		}
+		`, creds.Username, creds.Password) +
+		testAccRedfishResourceUserConfig(creds, "test1", "T0pSecret!", "Operator", true, "3")
+	endpointID := "https://" + creds.Endpoint + "|/redfish/v1/AccountService/Accounts/3"
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				Config:        config,
+				ResourceName:  "redfish_user_account.user_config",
+				ImportState:   true,
+				ImportStateId: endpointID,
+				ExpectError:   regexp.MustCompile("certificate"),
+			},
+			{
+				Config:                  config,
+				ResourceName:            "redfish_user_account.user_config",
+				ImportState:             true,
+				ImportStateId:           endpointID + "|ssl_insecure=true",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"password", "redfish_server"},
+			},
+		},
+	})
+}
+
+// Test that a malformed account collection is reported
+func TestRedfishUser_emulatedMalformedJSON(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	server.Inject(emulator.Fault{
+		Method:        http.MethodGet,
+		URI:           "/redfish/v1/AccountService/Accounts",
+		MalformedJSON: true,
+	})
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccRedfishResourceUserConfig(creds, "test1", "T0pSecret!", "Operator", true, "3"),
+				ExpectError: regexp.MustCompile("Error when retrieving account list"),
+			},
+		},
+	})
+}
+
+// Test that changes of an account busy iDRACs answer with "iDRAC is not ready" are retried
+func TestRedfishUser_emulatedNotReady(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	server.Inject(emulator.Fault{
+		Method:     http.MethodPatch,
+		URI:        "/redfish/v1/AccountService/Accounts/*",
+		Count:      2,
+		StatusCode: http.StatusServiceUnavailable,
+		MessageID:  emulator.NotReadyMessageID,
+		Message:    emulator.NotReadyMessage,
+	})
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishProviderRetryConfig(2) +
+					testAccRedfishResourceUserConfig(creds, "test1", "T0pSecret!", "Operator", true, "3"),
+				Check: checkEmulatedResource(server, "/redfish/v1/AccountService/Accounts/3", "UserName", "test1"),
+			},
+		},
+	})
+}
+
+// Test that the error is reported once the retries are exhausted
+func TestRedfishUser_emulatedRetriesExhausted(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	server.Inject(emulator.NotReady("/redfish/v1/AccountService/Accounts/3", 0))
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishProviderRetryConfig(1) +
+					testAccRedfishResourceUserConfig(creds, "test1", "T0pSecret!", "Operator", true, "3"),
+				ExpectError: regexp.MustCompile("iDRAC is not ready"),
+			},
+		},
+	})
+}
+
 func testAccRedfishResourceUserConfig(testingInfo TestingServerCredentials,
 	username string,
 	password string,
@@ -233,3 +375,37 @@ func testAccRedfishResourceUserConfig(testingInfo TestingServerCredentials,
 		userId,
 	)
 }
+
+func testAccRedfishResourceUserAliasConfig(testingInfo TestingServerCredentials,
+	username string,
+	password string,
+	userId string) string {
+	return fmt.Sprintf(`
+		provider "redfish" {
+		  servers {
+			alias = "server1"
+			user = "%s"
+			password = "%s"
+			endpoint = "https://%s"
+			ssl_insecure = true
+		  }
+		}
+
+		resource "redfish_user_account" "user_config" {
+		  redfish_alias = "server1"
+
+		  username = "%s"
+		  password = "%s"
+		  role_id = "Operator"
+		  enabled = true
+		  user_id = %s
+		}
+		`,
+		testingInfo.Username,
+		testingInfo.Password,
+		testingInfo.Endpoint,
+		username,
+		password,
+		userId,
+	)
+}
@@ -12,6 +12,15 @@ import (
 	"github.com/stmcginnis/gofish/redfish"
 )
 
+// virtualMediaAttributePaths locates the attributes setting the parameters of the InsertMedia action
+var virtualMediaAttributePaths = attributePaths{
+	"Image":                "image",
+	"Inserted":             "inserted",
+	"TransferMethod":       "transfer_method",
+	"TransferProtocolType": "transfer_protocol_type",
+	"WriteProtected":       "write_protected",
+}
+
 func resourceRedfishVirtualMedia() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceRedfishVirtualMediaCreate,
@@ -19,41 +28,18 @@ func resourceRedfishVirtualMedia() *schema.Resource {
 		UpdateContext: resourceRedfishVirtualMediaUpdate,
 		DeleteContext: resourceRedfishVirtualMediaDelete,
 		Schema:        getResourceRedfishVirtualMediaSchema(),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceRedfishVirtualMediaImport,
+		},
 	}
 }
 
 func getResourceRedfishVirtualMediaSchema() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
-		"redfish_server": {
-			Type:        schema.TypeList,
-			Required:    true,
-			Description: "List of server BMCs and their respective user credentials",
-			Elem: &schema.Resource{
-				Schema: map[string]*schema.Schema{
-					"user": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Description: "User name for login",
-					},
-					"password": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						Description: "User password for login",
-						Sensitive:   true,
-					},
-					"endpoint": {
-						Type:        schema.TypeString,
-						Required:    true,
-						Description: "Server BMC IP address or hostname",
-					},
-					"ssl_insecure": {
-						Type:        schema.TypeBool,
-						Optional:    true,
-						Description: "This field indicates whether the SSL/TLS certificate must be verified or not",
-					},
-				},
-			},
-		},
+		"redfish_alias":  redfishAliasSchema(),
+		"redfish_server": redfishServerSchema(),
+		"manager_id":     managerIDSchema(true),
+		"system_id":      systemIDSchema(true),
 		"image": {
 			Type:        schema.TypeString,
 			Description: "The URI of the remote media to attach to the virtual media",
@@ -101,15 +87,15 @@ func getResourceRedfishVirtualMediaSchema() map[string]*schema.Schema {
 }
 
 func resourceRedfishVirtualMediaCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	service, err := NewConfig(m.(*schema.ResourceData), d)
+	service, err := NewConfig(m.(*providerMeta).config, d)
 	if err != nil {
 		return diag.Errorf(err.Error())
 	}
-	return createRedfishVirtualMedia(service, d)
+	return createRedfishVirtualMedia(service, d, m)
 }
 
 func resourceRedfishVirtualMediaRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	service, err := NewConfig(m.(*schema.ResourceData), d)
+	service, err := NewConfig(m.(*providerMeta).config, d)
 	if err != nil {
 		return diag.Errorf(err.Error())
 	}
@@ -117,7 +103,7 @@ func resourceRedfishVirtualMediaRead(ctx context.Context, d *schema.ResourceData
 }
 
 func resourceRedfishVirtualMediaUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	service, err := NewConfig(m.(*schema.ResourceData), d)
+	service, err := NewConfig(m.(*providerMeta).config, d)
 	if err != nil {
 		return diag.Errorf(err.Error())
 	}
@@ -128,19 +114,37 @@ func resourceRedfishVirtualMediaUpdate(ctx context.Context, d *schema.ResourceDa
 }
 
 func resourceRedfishVirtualMediaDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	service, err := NewConfig(m.(*schema.ResourceData), d)
+	service, err := NewConfig(m.(*providerMeta).config, d)
 	if err != nil {
 		return diag.Errorf(err.Error())
 	}
-	return deleteRedfishVirtualMedia(service, d)
+	return deleteRedfishVirtualMedia(service, d, m)
+}
+
+// resourceRedfishVirtualMediaImport imports a mounted virtual media from an ID such as
+// https://my-server-1.myawesomecompany.org|/redfish/v1/Systems/System.Embedded.1/VirtualMedia/1, or
+// https://my-server-1.myawesomecompany.org|/redfish/v1/Managers/iDRAC.Embedded.1/VirtualMedia/CD on iDRAC 5.x
+func resourceRedfishVirtualMediaImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	odataID, err := parseImportID(d, m)
+	if err != nil {
+		return nil, err
+	}
+	d.SetId(odataID)
+	if err := d.Set("system_id", odataIDMember(odataID, "Systems")); err != nil {
+		return nil, err
+	}
+	if err := d.Set("manager_id", odataIDMember(odataID, "Managers")); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
 }
 
-func createRedfishVirtualMedia(service *gofish.Service, d *schema.ResourceData) diag.Diagnostics {
+func createRedfishVirtualMedia(service *gofish.Service, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
 	// Lock the mutex to avoid race conditions with other resources
-	redfishMutexKV.Lock(getRedfishServerEndpoint(d))
-	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(d))
+	redfishMutexKV.Lock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
+	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
 
 	//Get terraform schema data
 	image := d.Get("image").(string)
@@ -181,17 +185,14 @@ func createRedfishVirtualMedia(service *gofish.Service, d *schema.ResourceData)
 	}
 
 	//Get Systems details
-	systems, err := service.Systems()
+	system, err := getSystemResource(service, d.Get("system_id").(string))
 	if err != nil {
-		return diag.Errorf("Error when retrieving systems: %s", err)
-	}
-	if len(systems) == 0 {
-		return diag.Errorf("There is no system available")
+		return redfishDiagnostics("Error when retrieving systems", err, nil)
 	}
 
-	virtualMediaCollection, err := systems[0].VirtualMedia()
+	virtualMediaCollection, err := system.VirtualMedia()
 	if err != nil {
-		return diag.Errorf("Couldn't retrieve virtual media collection from redfish API: %s", err)
+		return redfishDiagnostics("Couldn't retrieve virtual media collection from redfish API", err, nil)
 	}
 
 	if len(virtualMediaCollection) != 0 {
@@ -199,12 +200,12 @@ func createRedfishVirtualMedia(service *gofish.Service, d *schema.ResourceData)
 			//Get specific virtual media
 			virtualMedia, err := getVirtualMedia(virtualMediaCollection[index].ID, virtualMediaCollection)
 			if err != nil {
-				return diag.Errorf("Virtual Media selected doesn't exist: %s", err)
+				return redfishDiagnostics("Virtual Media selected doesn't exist", err, nil)
 			}
 			if !virtualMedia.Inserted {
 				err = virtualMedia.InsertMediaConfig(virtualMediaConfig)
 				if err != nil {
-					return diag.Errorf("Couldn't mount Virtual Media: %s", err)
+					return redfishDiagnostics("Couldn't mount Virtual Media", err, virtualMediaAttributePaths)
 				}
 
 				d.SetId(virtualMedia.ODataID)
@@ -214,15 +215,15 @@ func createRedfishVirtualMedia(service *gofish.Service, d *schema.ResourceData)
 		}
 	} else {
 		// This implementation is added to support iDRAC firmware version 5.x. As virtual media can only be accessed through Managers card on 5.x.
-		//Get OOB Manager card - managers[0] will be our oob card
-		managers, err := service.Managers()
+		//Get OOB Manager card
+		manager, err := getManagerResource(service, d.Get("manager_id").(string))
 		if err != nil {
-			return diag.Errorf("Couldn't retrieve managers from redfish API: %s", err)
+			return redfishDiagnostics("Couldn't retrieve managers from redfish API", err, nil)
 		}
 
-		virtualMediaCollection, err := managers[0].VirtualMedia()
+		virtualMediaCollection, err := manager.VirtualMedia()
 		if err != nil {
-			return diag.Errorf("Couldn't retrieve virtual media collection from redfish API: %s", err)
+			return redfishDiagnostics("Couldn't retrieve virtual media collection from redfish API", err, nil)
 		}
 
 		var virtualMediaID string
@@ -234,12 +235,12 @@ func createRedfishVirtualMedia(service *gofish.Service, d *schema.ResourceData)
 
 		virtualMedia, err := getVirtualMedia(virtualMediaID, virtualMediaCollection)
 		if err != nil {
-			return diag.Errorf("Virtual Media selected doesn't exist: %s", err)
+			return redfishDiagnostics("Virtual Media selected doesn't exist", err, nil)
 		}
 		if !virtualMedia.Inserted {
 			err = virtualMedia.InsertMediaConfig(virtualMediaConfig)
 			if err != nil {
-				return diag.Errorf("Couldn't mount Virtual Media: %s", err)
+				return redfishDiagnostics("Couldn't mount Virtual Media", err, virtualMediaAttributePaths)
 			}
 
 			d.SetId(virtualMedia.ODataID)
@@ -256,7 +257,7 @@ func readRedfishVirtualMedia(service *gofish.Service, d *schema.ResourceData) di
 
 	virtualMedia, err := redfish.GetVirtualMedia(service.GetClient(), d.Id())
 	if err != nil {
-		return diag.Errorf("Virtual Media doesn't exist: %s", err) //This error won't be triggered ever
+		return redfishDiagnostics("Virtual Media doesn't exist", err, nil) //This error won't be triggered ever
 	}
 
 	if len(virtualMedia.Image) == 0 { //Nothing is mounted here
@@ -307,18 +308,18 @@ func updateRedfishVirtualMedia(ctx context.Context, service *gofish.Service, d *
 	var diags diag.Diagnostics
 
 	// Lock the mutex to avoid race conditions with other resources
-	redfishMutexKV.Lock(getRedfishServerEndpoint(d))
-	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(d))
+	redfishMutexKV.Lock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
+	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
 
 	//Hot update os not possible. Unmount and mount needs to be done to update
 	virtualMedia, err := redfish.GetVirtualMedia(service.GetClient(), d.Id())
 	if err != nil {
-		return diag.Errorf("Virtual Media doesn't exist: %s", err) //This error won't be triggered ever
+		return redfishDiagnostics("Virtual Media doesn't exist", err, nil) //This error won't be triggered ever
 	}
 
 	err = virtualMedia.EjectMedia()
 	if err != nil {
-		return diag.Errorf("There was an error when ejecting media: %s", err)
+		return redfishDiagnostics("There was an error when ejecting media", err, nil)
 	}
 
 	//Get terraform schema data
@@ -361,27 +362,27 @@ func updateRedfishVirtualMedia(ctx context.Context, service *gofish.Service, d *
 
 	err = virtualMedia.InsertMediaConfig(virtualMediaConfig)
 	if err != nil {
-		return diag.Errorf("Couldn't mount Virtual Media: %s", err)
+		return redfishDiagnostics("Couldn't mount Virtual Media", err, virtualMediaAttributePaths)
 	}
 
 	return diags
 }
 
-func deleteRedfishVirtualMedia(service *gofish.Service, d *schema.ResourceData) diag.Diagnostics {
+func deleteRedfishVirtualMedia(service *gofish.Service, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
 	// Lock the mutex to avoid race conditions with other resources
-	redfishMutexKV.Lock(getRedfishServerEndpoint(d))
-	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(d))
+	redfishMutexKV.Lock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
+	defer redfishMutexKV.Unlock(getRedfishServerEndpoint(m.(*providerMeta).config, d))
 
 	virtualMedia, err := redfish.GetVirtualMedia(service.GetClient(), d.Id())
 	if err != nil {
-		return diag.Errorf("Virtual Media doesn't exist: %s", err) //This error won't be triggered ever
+		return redfishDiagnostics("Virtual Media doesn't exist", err, nil) //This error won't be triggered ever
 	}
 
 	err = virtualMedia.EjectMedia()
 	if err != nil {
-		return diag.Errorf("There was an error when ejecting media: %s", err)
+		return redfishDiagnostics("There was an error when ejecting media", err, nil)
 	}
 
 	return diags
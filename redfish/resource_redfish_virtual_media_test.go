@@ -2,9 +2,11 @@ package redfish
 
 import (
 	"fmt"
+	"net/http"
 	"regexp"
 	"testing"
 
+	"github.com/dell/terraform-provider-redfish/internal/emulator"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 )
 
@@ -140,6 +142,53 @@ func TestAccRedfishVirtualMediaUpdate_basic(t *testing.T) {
 	})
 }
 
+// Test to insert, change and eject virtual media on an emulated iDRAC
+func TestRedfishVirtualMedia_emulated(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			checkEmulatedResource(server, "/redfish/v1/Systems/System.Embedded.1/VirtualMedia/1", "Inserted", false),
+			checkEmulatedResource(server, "/redfish/v1/Systems/System.Embedded.1/VirtualMedia/2", "Inserted", false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRedfishResourceVirtualMediaConfig(creds, "cd", "http://images.example.com/ubuntu.iso", true, "HTTP", "Stream"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_virtual_media.cd", "inserted", "true"),
+					checkEmulatedResourceOf(server, "redfish_virtual_media.cd", "Image", "http://images.example.com/ubuntu.iso"),
+				),
+			},
+			{
+				Config: testAccRedfishResourceVirtualMediaConfig(creds, "cd", "http://images.example.com/fedora.iso", true, "HTTP", "Stream"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("redfish_virtual_media.cd", "image", "http://images.example.com/fedora.iso"),
+					checkEmulatedResourceOf(server, "redfish_virtual_media.cd", "Image", "http://images.example.com/fedora.iso"),
+				),
+			},
+		},
+	})
+}
+
+// Test that a connection dropped while inserting virtual media is reported
+func TestRedfishVirtualMedia_emulatedDroppedConnection(t *testing.T) {
+	server, creds := newEmulatedServer(t)
+	server.Inject(emulator.Fault{
+		Method:         http.MethodPost,
+		URI:            "/redfish/v1/Systems/System.Embedded.1/VirtualMedia/*",
+		DropConnection: true,
+	})
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testAccEmulatedProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccRedfishResourceVirtualMediaConfig(creds, "cd", "http://images.example.com/ubuntu.iso", true, "HTTP", "Stream"),
+				ExpectError: regexp.MustCompile("Couldn't mount Virtual Media"),
+			},
+		},
+	})
+}
+
 func testAccRedfishResourceVirtualMediaConfig(testingInfo TestingServerCredentials,
 	resource_name string,
 	image string,
@@ -0,0 +1,123 @@
+package redfish
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dell/terraform-provider-redfish/common"
+	"github.com/stmcginnis/gofish"
+)
+
+const (
+	// tlsHandshakeTimeout is the time allowed for the TLS handshake against a BMC
+	tlsHandshakeTimeout = 10 * time.Second
+	// logoutTimeout bounds the time spent deleting sessions when the provider shuts down
+	logoutTimeout = 2 * time.Second
+)
+
+// clientConfig holds everything that identifies a connection to a redfish service.
+// A cached client is only reused while its configuration stays the same.
+type clientConfig struct {
+	endpoint string
+	user     string
+	password string
+	tls      common.TLSOptions
+	retry    common.RetryPolicy
+}
+
+// cachedClient is a cache slot for one endpoint and user. Its own lock serializes connection setup for that
+// slot without blocking connections to other servers.
+type cachedClient struct {
+	sync.Mutex
+	config    clientConfig
+	api       *gofish.APIClient
+	transport *common.SessionTransport
+}
+
+// sessionCache keeps one session based client per endpoint and user for the lifetime of the provider process,
+// so the BMC is not asked to authenticate on every CRUD operation
+var sessionCache = struct {
+	sync.Mutex
+	clients map[string]*cachedClient
+}{clients: map[string]*cachedClient{}}
+
+// getClient returns a cached client for the given configuration, creating it if needed
+func getClient(config clientConfig) (*gofish.APIClient, error) {
+	key := config.endpoint + "|" + config.user
+
+	sessionCache.Lock()
+	cached, ok := sessionCache.clients[key]
+	if !ok {
+		cached = &cachedClient{}
+		sessionCache.clients[key] = cached
+	}
+	sessionCache.Unlock()
+
+	cached.Lock()
+	defer cached.Unlock()
+
+	if cached.api != nil {
+		if cached.config == config {
+			return cached.api, nil
+		}
+		// Credentials, TLS settings or the retry policy changed, the old session is of no use anymore
+		logout(cached.transport)
+		cached.api, cached.transport = nil, nil
+	}
+
+	tlsConfig, err := config.tls.Config()
+	if err != nil {
+		return nil, err
+	}
+	// Retries happen below the session, so that the requests creating sessions are retried too
+	transport := common.NewSessionTransport(config.endpoint, config.user, config.password, common.NewRetryTransport(config.retry, &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		TLSHandshakeTimeout: tlsHandshakeTimeout,
+		TLSClientConfig:     tlsConfig,
+	}))
+	api, err := gofish.Connect(gofish.ClientConfig{
+		Endpoint:   config.endpoint,
+		HTTPClient: &http.Client{Transport: transport},
+	})
+	if err != nil {
+		logout(transport)
+		return nil, err
+	}
+
+	cached.config, cached.api, cached.transport = config, api, transport
+	return api, nil
+}
+
+// CloseSessions deletes every redfish session opened by the provider. It is meant to be called when the
+// provider process is shutting down.
+func CloseSessions() {
+	sessionCache.Lock()
+	defer sessionCache.Unlock()
+
+	var wg sync.WaitGroup
+	for key, cached := range sessionCache.clients {
+		wg.Add(1)
+		go func(c *cachedClient) {
+			defer wg.Done()
+			c.Lock()
+			defer c.Unlock()
+			if c.transport != nil {
+				logout(c.transport)
+			}
+			c.api, c.transport = nil, nil
+		}(cached)
+		delete(sessionCache.clients, key)
+	}
+	wg.Wait()
+}
+
+func logout(transport *common.SessionTransport) {
+	ctx, cancel := context.WithTimeout(context.Background(), logoutTimeout)
+	defer cancel()
+	if err := transport.Logout(ctx); err != nil {
+		log.Printf("[WARN] %v\n", err)
+	}
+}
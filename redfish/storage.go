@@ -0,0 +1,125 @@
+package redfish
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dell/terraform-provider-redfish/common"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stmcginnis/gofish"
+	redfishcommon "github.com/stmcginnis/gofish/common"
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+// getStorageController returns the storage controller with the given ID
+func getStorageController(storageControllers []*redfish.Storage, diskControllerID string) (*redfish.Storage, error) {
+	for _, storage := range storageControllers {
+		if storage.Entity.ID == diskControllerID {
+			return storage, nil
+		}
+	}
+	return nil, fmt.Errorf("error. Didn't find the storage controller %v", diskControllerID)
+}
+
+// getDrives returns the drives with the given names, in the order of the names
+func getDrives(drives []*redfish.Drive, driveNames []string) ([]*redfish.Drive, error) {
+	drivesToReturn := []*redfish.Drive{}
+	var missing []string
+	for _, w := range driveNames {
+		found := false
+		for _, v := range drives {
+			if v.Name == w {
+				drivesToReturn = append(drivesToReturn, v)
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, w)
+		}
+	}
+	if len(missing) != 0 {
+		return nil, fmt.Errorf("the storage controller has no drive named %s", strings.Join(missing, ", "))
+	}
+	return drivesToReturn, nil
+}
+
+// getStorageDrives returns a system, one of its storage controllers and the drives of that controller with the given
+// names
+func getStorageDrives(service *gofish.Service, systemID, storageID string, driveNames []string) (*redfish.ComputerSystem, *redfish.Storage, []*redfish.Drive, diag.Diagnostics) {
+	system, err := getSystemResource(service, systemID)
+	if err != nil {
+		return nil, nil, nil, redfishDiagnostics("Error when retreiving the Systems from the Redfish API", err, nil)
+	}
+	storageControllers, err := system.Storage()
+	if err != nil {
+		return nil, nil, nil, redfishDiagnostics(fmt.Sprintf("Error when retreiving the Storage from %v from the Redfish API", system.Name), err, nil)
+	}
+	storage, err := getStorageController(storageControllers, storageID)
+	if err != nil {
+		return nil, nil, nil, diag.Errorf("Error when getting the storage struct: %s", err)
+	}
+	allStorageDrives, err := storage.Drives()
+	if err != nil {
+		return nil, nil, nil, redfishDiagnostics("Error when getting the drives attached to controller", err, nil)
+	}
+	drives, err := getDrives(allStorageDrives, driveNames)
+	if err != nil {
+		return nil, nil, nil, diag.Errorf("Error when getting the drives: %s", err)
+	}
+	return system, storage, drives, nil
+}
+
+// getDellRaidService returns the URI of the Dell RAID service of a system, or an empty string if it has none
+func getDellRaidService(system *redfish.ComputerSystem) (string, error) {
+	var links struct {
+		Links struct {
+			Oem struct {
+				Dell struct {
+					DellRaidService redfishcommon.Link
+				}
+			}
+		}
+	}
+	if err := getJSON(system.GetClient(), system.ODataID, &links); err != nil {
+		return "", err
+	}
+	return links.Links.Oem.Dell.DellRaidService.String(), nil
+}
+
+// runDellRaidServiceAction runs an action of the Dell RAID service, such as AssignSpare, and returns the URI of the
+// job carrying it out
+func runDellRaidServiceAction(client redfishcommon.Client, raidServiceURI string, action string, payload map[string]interface{}) (string, error) {
+	res, err := client.Post(fmt.Sprintf("%s/Actions/DellRaidService.%s", raidServiceURI, action), payload)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	jobURI := res.Header.Get("Location")
+	if len(jobURI) == 0 {
+		return "", fmt.Errorf("there was some error when retreiving the jobID")
+	}
+	return jobURI, nil
+}
+
+// waitForStorageJobs resets the system when the changes of storage resources are applied on reset, and waits for the
+// jobs applying them to finish. A single reset applies every change staged before.
+func waitForStorageJobs(ctx context.Context, service *gofish.Service, d *schema.ResourceData, waits waitTimes, jobURIs ...string) diag.Diagnostics {
+	if d.Get("settings_apply_time").(string) == string(redfishcommon.OnResetApplyTime) {
+		_, diags := PowerOperation(ctx, d.Get("reset_type").(string), 0, waits.storageVolumeJobCheck, service, d.Get("system_id").(string))
+		if diags.HasError() {
+			return diags
+		}
+	}
+	for _, jobURI := range jobURIs {
+		if jobURI == "" {
+			continue
+		}
+		if err := common.WaitForJobToFinish(ctx, service, jobURI, waits.storageVolumeJobCheck, 0); err != nil {
+			return redfishDiagnostics(fmt.Sprintf("Error, job %s wasn't able to complete", jobURI), err, nil)
+		}
+	}
+	return nil
+}